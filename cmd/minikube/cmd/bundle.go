@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/bundle"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	bundleDriver            string
+	bundleContainerRuntime  string
+	bundleKubernetesVersion string
+	bundleAddons            []string
+)
+
+// bundleCmd represents the set of offline bundle subcommands
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create offline bundles for air-gapped hosts",
+	Long:  "Create offline bundles, which hold everything needed to start a cluster without network access",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube bundle [create]")
+	},
+}
+
+// bundleCreateCmd represents the bundle create command
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create FILE",
+	Short: "Create an offline bundle",
+	Long: `Downloads everything needed to start a cluster with the given driver, container runtime and Kubernetes version, and writes it to a single archive:
+the ISO or kicbase image, the preloaded images or the Kubernetes images, the Kubernetes binaries, and the images of the default and chosen addons.
+Start a cluster from the bundle on a host without network access with "minikube start --bundle FILE".`,
+	Example: "minikube bundle create lab.tar --driver=kvm2 --container-runtime=containerd --kubernetes-version=v1.20.2 --addons=ingress",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube bundle create FILE --driver DRIVER")
+		}
+		if bundleDriver == "" {
+			exit.Message(reason.Usage, "Please specify the driver of the bundle via --driver")
+		}
+		if !driver.Supported(bundleDriver) {
+			exit.Message(reason.DrvUnsupportedOS, "The driver '{{.driver}}' is not supported on {{.os}}", out.V{"driver": bundleDriver, "os": runtime.GOOS})
+		}
+		if !bundle.Supported(bundleDriver) {
+			exit.Message(reason.Usage, "Sorry, the {{.driver}} driver does not support offline bundles", out.V{"driver": bundleDriver})
+		}
+		if _, err := cruntime.New(cruntime.Config{Type: bundleContainerRuntime}); err != nil {
+			exit.Message(reason.Usage, "Invalid container runtime '{{.runtime}}', valid options are: {{.valid}}", out.V{"runtime": bundleContainerRuntime, "valid": strings.Join(cruntime.ValidRuntimes(), ", ")})
+		}
+		v, err := semver.Make(strings.TrimPrefix(bundleKubernetesVersion, version.VersionPrefix))
+		if err != nil {
+			exit.Message(reason.Usage, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": bundleKubernetesVersion, "error": err})
+		}
+
+		m := &bundle.Manifest{
+			MinikubeVersion:   version.GetVersion(),
+			Driver:            bundleDriver,
+			ContainerRuntime:  bundleContainerRuntime,
+			KubernetesVersion: version.VersionPrefix + v.String(),
+			Addons:            bundleAddons,
+		}
+		out.Step(style.FileDownload, "Downloading everything Kubernetes {{.version}} needs on {{.runtime}} with the {{.driver}} driver ...", out.V{"version": m.KubernetesVersion, "runtime": m.ContainerRuntime, "driver": m.Driver})
+		if err := bundle.Cache(m); err != nil {
+			exit.Error(reason.InetCacheBundle, "Failed to cache the bundle contents", err)
+		}
+		if err := bundle.Create(args[0], m); err != nil {
+			exit.Error(reason.HostBundle, "Failed to write the bundle", err)
+		}
+		out.Step(style.Check, "Wrote the offline bundle to {{.file}}: {{.count}} files", out.V{"file": args[0], "count": len(m.Files)})
+		out.Step(style.Tip, "Start a cluster from it with: minikube start --bundle {{.file}}", out.V{"file": args[0]})
+	},
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVar(&bundleDriver, "driver", "", fmt.Sprintf("Driver the bundle is for (%s)", strings.Join(driver.SupportedDrivers(), ", ")))
+	bundleCreateCmd.Flags().StringVar(&bundleContainerRuntime, "container-runtime", "docker", fmt.Sprintf("The container runtime the bundle is for (%s)", strings.Join(cruntime.ValidRuntimes(), ", ")))
+	bundleCreateCmd.Flags().StringVar(&bundleKubernetesVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version the bundle is for")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleAddons, "addons", nil, "Addons whose images are included in the bundle, in addition to the default ones")
+	bundleCmd.AddCommand(bundleCreateCmd)
+}
@@ -42,6 +42,7 @@ var addCacheCmd = &cobra.Command{
 	Short: "Add an image to local cache.",
 	Long:  "Add an image to local cache.",
 	Run: func(cmd *cobra.Command, args []string) {
+		useRegistryAuths()
 		// Cache and load images into docker daemon
 		if err := machine.CacheAndLoadImages(args); err != nil {
 			exit.Error(reason.InternalCacheLoad, "Failed to cache and load images", err)
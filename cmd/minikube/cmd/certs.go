@@ -0,0 +1,244 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// certsExpiringSoon is how long before their expiry certificates are reported as expiring soon
+const certsExpiringSoon = 30 * 24 * time.Hour
+
+var (
+	certsOutput      string
+	certsWaitTimeout time.Duration
+)
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Check and rotate the certificates of a cluster",
+	Long:  "Check when the certificates minikube and kubeadm manage for a cluster expire, and rotate them in place",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube certs [check-expiration|rotate]")
+	},
+}
+
+// certsCheckExpirationCmd represents the certs check-expiration command
+var certsCheckExpirationCmd = &cobra.Command{
+	Use:   "check-expiration",
+	Short: "Check when the certificates of a cluster expire",
+	Long: `Prints when each certificate of a cluster expires: those minikube keeps on the host, and those on every running node,
+including the client certificates kubeadm embeds in the kubeconfig files of the control planes.`,
+	Example: "minikube certs check-expiration -o json",
+	Run: func(cmd *cobra.Command, args []string) {
+		output := strings.ToLower(certsOutput)
+		if output != "table" && output != "json" {
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json'", certsOutput))
+		}
+
+		cname := ClusterFlagValue()
+		api, cc := mustload.Partial(cname)
+		ces, err := bootstrapper.HostCertExpirations(cc.KubernetesConfig)
+		if err != nil {
+			exit.Error(reason.GuestCert, "Failed to read the certificates of the host", err)
+		}
+		for _, n := range cc.Nodes {
+			machineName := config.MachineName(*cc, n)
+			if !machineRunning(api, machineName) {
+				out.WarningT("Skipping node {{.name}}, as it is not running", out.V{"name": machineName})
+				continue
+			}
+			runner := nodeRunner(mustload.ClusterController{Config: cc, API: api}, &n)
+			nces, err := bootstrapper.NodeCertExpirations(runner, machineName)
+			if err != nil {
+				exit.Error(reason.GuestCert, "Failed to read the certificates of the node", err)
+			}
+			ces = append(ces, nces...)
+		}
+
+		if output == "json" {
+			data, err := json.Marshal(ces)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "certs json failure", err)
+			}
+			out.Ln(string(data))
+			return
+		}
+
+		now := time.Now()
+		expiring := 0
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Location", "Certificate", "Managed By", "CA", "Expires", "Residual Time"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, ce := range ces {
+			if ce.NotAfter.Sub(now) < certsExpiringSoon {
+				expiring++
+			}
+			table.Append([]string{ce.Location, ce.Name, ce.ManagedBy, strconv.FormatBool(ce.CA), ce.NotAfter.Format(time.RFC3339), residualTime(ce.NotAfter, now)})
+		}
+		table.Render()
+
+		if expiring > 0 {
+			out.WarningT("{{.count}} certificates have expired or expire within 30 days. To renew them, run: {{.cmd}}", out.V{"count": expiring, "cmd": mustload.ExampleCmd(cname, "certs rotate")})
+		}
+	},
+}
+
+// certsRotateCmd represents the certs rotate command
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the certificates of a running cluster",
+	Long: `Regenerates the certificates minikube signs for the cluster with new keys, renews those kubeadm manages, and pushes them to every running node.
+The control plane is then restarted to load them, and the kubeconfig is refreshed. The CAs are kept, so clients which trust them keep working.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		co := mustload.Running(cname)
+		cc := co.Config
+		cp, err := config.PrimaryControlPlane(cc)
+		if err != nil {
+			exit.Error(reason.GuestCpConfig, "Unable to find control plane", err)
+		}
+
+		out.Step(style.Resetting, "Rotating the certificates of {{.profile}} ...", out.V{"profile": cname})
+		if err := bootstrapper.RemoveProfileCerts(cname); err != nil {
+			exit.Error(reason.GuestCert, "Failed to remove the old certificates", err)
+		}
+
+		// The primary control plane goes first, as the certificates shared by the control planes are signed along with it
+		nodes := []config.Node{cp}
+		for _, n := range cc.Nodes {
+			if n.Name != cp.Name {
+				nodes = append(nodes, n)
+			}
+		}
+		// A highly available cluster may be running without its primary control plane
+		var waiter bootstrapper.Bootstrapper
+		var waitNode config.Node
+		for _, n := range nodes {
+			machineName := config.MachineName(*cc, n)
+			if !machineRunning(co.API, machineName) {
+				out.WarningT("Skipping node {{.name}}, as it is not running. Run {{.cmd}} again once it is started.", out.V{"name": machineName, "cmd": mustload.ExampleCmd(cname, "certs rotate")})
+				continue
+			}
+			bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *cc, nodeRunner(co, &n))
+			if err != nil {
+				exit.Error(reason.InternalBootstrapper, "Failed to get bootstrapper", err)
+			}
+			out.Step(style.SubStep, "Renewing the certificates of {{.name}} ...", out.V{"name": machineName})
+			if err := bs.RotateCerts(*cc, n); err != nil {
+				exit.Error(reason.GuestCert, "Failed to rotate the certificates", err)
+			}
+			if waiter == nil && n.ControlPlane {
+				waiter, waitNode = bs, n
+			}
+		}
+
+		if err := refreshKubeconfig(co); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "Failed to update the kubeconfig", err)
+		}
+		if waiter == nil {
+			exit.Message(reason.GuestCpConfig, "None of the control planes of {{.profile}} is running", out.V{"profile": cname})
+		}
+		if err := waiter.WaitForNode(*cc, waitNode, certsWaitTimeout); err != nil {
+			exit.Error(reason.GuestStart, "The control plane did not become healthy after rotating the certificates", err)
+		}
+		out.Step(style.Check, "Rotated the certificates of {{.profile}}", out.V{"profile": cname})
+	},
+}
+
+// machineRunning returns whether the machine of a node is running
+func machineRunning(api libmachine.API, machineName string) bool {
+	st, err := machine.Status(api, machineName)
+	if err != nil {
+		klog.Warningf("unable to get the status of %s: %v", machineName, err)
+		return false
+	}
+	return st == state.Running.String()
+}
+
+// refreshKubeconfig writes the newly signed client certificate of a cluster to the kubeconfig, keeping its endpoint and the current context
+func refreshKubeconfig(co mustload.ClusterController) error {
+	cc := co.Config
+	hostname, port, err := kubeconfig.Endpoint(cc.Name)
+	if err != nil {
+		klog.Warningf("unable to get the endpoint of %s from the kubeconfig, using %s:%d: %v", cc.Name, co.CP.Hostname, co.CP.Port, err)
+		hostname, port = co.CP.Hostname, co.CP.Port
+	}
+	kcs := &kubeconfig.Settings{
+		ClusterName:          cc.Name,
+		Namespace:            cc.KubernetesConfig.Namespace,
+		ClusterServerAddress: fmt.Sprintf("https://%s", net.JoinHostPort(hostname, strconv.Itoa(port))),
+		ClientCertificate:    localpath.ClientCert(cc.Name),
+		ClientKey:            localpath.ClientKey(cc.Name),
+		CertificateAuthority: bootstrapper.ClusterCACert(cc.KubernetesConfig),
+		KeepContext:          true,
+		EmbedCerts:           cc.EmbedCerts,
+	}
+	if cc.ExecCredential {
+		// the plugin returns the rotated certificates: only the server may need an update
+		if kcs.ExecCredential, err = kubeconfig.ExecCredentialPlugin(cc.Name, ""); err != nil {
+			return err
+		}
+	}
+	kcs.SetPath(kubeconfig.PathFor(cc.Name))
+	return kubeconfig.Update(kcs)
+}
+
+// residualTime returns how long until a certificate expires, in days
+func residualTime(notAfter time.Time, now time.Time) string {
+	d := notAfter.Sub(now)
+	if d <= 0 {
+		return "expired"
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+func init() {
+	certsCheckExpirationCmd.Flags().StringVarP(&certsOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	certsRotateCmd.Flags().DurationVar(&certsWaitTimeout, "wait-timeout", 6*time.Minute, "max time to wait for the control plane to be healthy after the rotation")
+
+	certsCmd.AddCommand(certsCheckExpirationCmd)
+	certsCmd.AddCommand(certsRotateCmd)
+}
@@ -168,13 +168,18 @@ var settings = []Setting{
 		setMap: SetMap,
 	},
 	{
-		name: "embed-certs",
-		set:  SetBool,
+		name:        "embed-certs",
+		set:         SetString,
+		validations: []setFn{IsValidEmbedCerts},
 	},
 	{
 		name: "native-ssh",
 		set:  SetBool,
 	},
+	{
+		name: config.ArtifactMirror,
+		set:  SetString,
+	},
 }
 
 // ConfigCmd represents the config command
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var exportOutput string
+
+var profileExportCmd = &cobra.Command{
+	Use:     "export [MINIKUBE_PROFILE_NAME]",
+	Short:   "Export a profile as a cluster definition",
+	Long:    "Prints the saved configuration of a profile as a cluster definition, which can be used to recreate it with `minikube start --config`. Defaults to the current profile.",
+	Example: "minikube profile export > cluster.yaml\nminikube start -p copy --config cluster.yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			exit.Message(reason.Usage, "usage: minikube profile export [MINIKUBE_PROFILE_NAME]")
+		}
+		profile := ClusterFlagValue()
+		if len(args) == 1 {
+			profile = args[0]
+		}
+
+		cc, err := config.Load(profile)
+		if err != nil {
+			if config.IsNotExist(err) {
+				exit.Message(reason.Usage, `Profile "{{.name}}" not found. Run "minikube profile list" to view all profiles.`, out.V{"name": profile})
+			}
+			exit.Error(reason.HostConfigLoad, "Error loading profile config", err)
+		}
+
+		format := strings.ToLower(exportOutput)
+		data, err := config.SpecFromConfig(*cc).Marshal(format)
+		if err != nil {
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'yaml', 'json'", exportOutput))
+		}
+		out.Ln("%s", strings.TrimSuffix(string(data), "\n"))
+	},
+}
+
+func init() {
+	profileExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "yaml", "The output format. One of 'yaml', 'json'")
+	ProfileCmd.AddCommand(profileExportCmd)
+}
@@ -134,3 +134,12 @@ func IsValidRuntime(name string, runtime string) error {
 	}
 	return nil
 }
+
+// IsValidEmbedCerts checks if a string is a valid embed-certs mode: true, false or exec
+func IsValidEmbedCerts(name string, mode string) error {
+	switch mode {
+	case "true", "false", "exec":
+		return nil
+	}
+	return fmt.Errorf("invalid %s value %q, expected true, false or exec", name, mode)
+}
@@ -125,6 +125,33 @@ func TestValidRuntime(t *testing.T) {
 	runValidations(t, tests, "container-runtime", IsValidRuntime)
 }
 
+func TestValidEmbedCerts(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "true",
+			shouldErr: false,
+		},
+		{
+			value:     "false",
+			shouldErr: false,
+		},
+		{
+			value:     "exec",
+			shouldErr: false,
+		},
+		{
+			value:     "",
+			shouldErr: true,
+		},
+		{
+			value:     "yes",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "embed-certs", IsValidEmbedCerts)
+}
+
 func TestIsURLExists(t *testing.T) {
 
 	self, err := os.Executable()
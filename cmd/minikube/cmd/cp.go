@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	pkgpath "path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+// cpPath is one side of a copy: a path on the host, or a path on a node
+type cpPath struct {
+	// Node is the node the path is on, or nil for the host
+	Node *config.Node
+	// Path is the path of the file
+	Path string
+}
+
+// cpCmd represents the cp command, similar to docker cp
+var cpCmd = &cobra.Command{
+	Use:   "cp <source> <target>",
+	Short: "Copy files between the host and minikube",
+	Long: "Copy a file between the host and a node. Paths on a node are written as <node name>:<absolute path>. " +
+		"A target without a node name is a path on the node given by --node, which defaults to the primary control plane.",
+	Example: "minikube cp a.txt /home/docker/b.txt\n" +
+		"minikube cp a.txt m02:/home/docker/b.txt\n" +
+		"minikube cp m02:/home/docker/b.txt a.txt",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.Message(reason.Usage, `Please specify the path to copy:
+	minikube cp <source file path> <target file absolute path> (example: "minikube cp a/b.txt /copied.txt")`)
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+		if co.CP.Host.DriverName == driver.None {
+			exit.Message(reason.Usage, "'none' driver does not support 'minikube cp' command")
+		}
+
+		defaultNode := co.CP.Node
+		if nodeName != "" {
+			n, _, err := node.Retrieve(*co.Config, nodeName)
+			if err != nil {
+				exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": nodeName})
+			}
+			defaultNode = n
+		}
+
+		src, dst, err := parseCpArgs(*co.Config, defaultNode, args[0], args[1])
+		if err != nil {
+			exit.Message(reason.Usage, "{{.error}}", out.V{"error": err})
+		}
+
+		if src.Node != nil {
+			runner := nodeRunner(co, src.Node)
+			if err := runner.CopyFrom(src.Path, dst.Path); err != nil {
+				exit.Error(reason.InternalCommandRunner, fmt.Sprintf("Fail to copy file %s", src.Path), err)
+			}
+			return
+		}
+
+		fi, err := os.Stat(src.Path)
+		if err != nil {
+			exit.Error(reason.HostPathMissing, "Unable to find source file", err)
+		}
+		fa, err := assets.NewFileAsset(src.Path, pkgpath.Dir(dst.Path), pkgpath.Base(dst.Path), fmt.Sprintf("%o", fi.Mode().Perm()))
+		if err != nil {
+			exit.Error(reason.InternalCommandRunner, "Failed to read source file", err)
+		}
+
+		runner := nodeRunner(co, dst.Node)
+		if err := runner.Copy(fa); err != nil {
+			exit.Error(reason.InternalCommandRunner, fmt.Sprintf("Fail to copy file %s", fa.GetSourcePath()), err)
+		}
+	},
+}
+
+// parseCpArgs works out the direction of a copy from its arguments.
+// A path on a node is written as <node name>:<path>, and a target without a node name is on defaultNode.
+func parseCpArgs(cc config.ClusterConfig, defaultNode *config.Node, srcArg string, dstArg string) (cpPath, cpPath, error) {
+	src := splitCpPath(cc, srcArg)
+	dst := splitCpPath(cc, dstArg)
+
+	if src.Node != nil && dst.Node != nil {
+		return src, dst, fmt.Errorf("copying between nodes is not supported, one of %q or %q must be a path on the host", srcArg, dstArg)
+	}
+	if src.Node == nil && dst.Node == nil {
+		dst.Node = defaultNode
+	}
+
+	remote := &dst
+	local := &src
+	if src.Node != nil {
+		remote, local = &src, &dst
+	}
+	if !pkgpath.IsAbs(remote.Path) {
+		return src, dst, fmt.Errorf("<target file absolute path> must be an absolute Path. Relative Path is not allowed (example: \"/home/docker/copied.txt\")")
+	}
+
+	// Copying into a directory keeps the name of the source file
+	if strings.HasSuffix(dst.Path, "/") || strings.HasSuffix(dst.Path, string(filepath.Separator)) || isLocalDir(dst) {
+		if dst.Node != nil {
+			dst.Path = pkgpath.Join(dst.Path, filepath.Base(local.Path))
+		} else {
+			dst.Path = filepath.Join(dst.Path, pkgpath.Base(remote.Path))
+		}
+	}
+	return src, dst, nil
+}
+
+// splitCpPath splits <node name>:<path> into a node and a path, if the prefix names a node of the cluster
+func splitCpPath(cc config.ClusterConfig, arg string) cpPath {
+	parts := strings.SplitN(arg, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return cpPath{Path: arg}
+	}
+
+	n, _, err := node.Retrieve(cc, parts[0])
+	if err != nil {
+		// Not a node, so likely a host path which contains a colon, like C:\a.txt on Windows
+		return cpPath{Path: arg}
+	}
+	return cpPath{Node: n, Path: parts[1]}
+}
+
+// isLocalDir returns whether p is an existing directory on the host
+func isLocalDir(p cpPath) bool {
+	if p.Node != nil {
+		return false
+	}
+	fi, err := os.Stat(p.Path)
+	return err == nil && fi.IsDir()
+}
+
+// nodeRunner returns a command runner for a node of a running cluster
+func nodeRunner(co mustload.ClusterController, n *config.Node) command.Runner {
+	h, err := machine.LoadHost(co.API, config.MachineName(*co.Config, *n))
+	if err != nil {
+		exit.Error(reason.GuestLoadHost, "Error getting host", err)
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+	}
+	return runner
+}
+
+func init() {
+	cpCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to copy to when the target has no node name. Defaults to the primary control plane.")
+}
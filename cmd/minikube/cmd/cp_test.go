@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParseCpArgs(t *testing.T) {
+	cc := config.ClusterConfig{
+		Name: "minikube",
+		Nodes: []config.Node{
+			{Name: "", ControlPlane: true},
+			{Name: "m02"},
+		},
+	}
+	cp := &cc.Nodes[0]
+
+	tests := []struct {
+		description string
+		src         string
+		dst         string
+		srcNode     string
+		dstNode     string
+		srcPath     string
+		dstPath     string
+		err         bool
+	}{
+		{"host to default node", "a.txt", "/home/docker/b.txt", "", "minikube", "a.txt", "/home/docker/b.txt", false},
+		{"host to named node", "a.txt", "m02:/home/docker/b.txt", "", "m02", "a.txt", "/home/docker/b.txt", false},
+		{"host to machine name", "a.txt", "minikube-m02:/tmp/b.txt", "", "m02", "a.txt", "/tmp/b.txt", false},
+		{"host to node directory", "dir/a.txt", "m02:/tmp/", "", "m02", "dir/a.txt", "/tmp/a.txt", false},
+		{"node to host", "minikube:/etc/hosts", "hosts", "minikube", "", "/etc/hosts", "hosts", false},
+		{"node to host directory", "m02:/etc/hosts", "out/", "m02", "", "/etc/hosts", "out/hosts", false},
+		{"colon in host path", `C:\a.txt`, "/a.txt", "", "minikube", `C:\a.txt`, "/a.txt", false},
+		{"relative target", "a.txt", "b.txt", "", "", "", "", true},
+		{"relative node source", "m02:b.txt", "a.txt", "", "", "", "", true},
+		{"node to node", "m02:/a.txt", "minikube:/a.txt", "", "", "", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			src, dst, err := parseCpArgs(cc, cp, test.src, test.dst)
+			if test.err {
+				if err == nil {
+					t.Fatalf("parseCpArgs(%q, %q) expected an error", test.src, test.dst)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCpArgs(%q, %q) unexpected error: %v", test.src, test.dst, err)
+			}
+			if got := cpNodeName(cc, src); got != test.srcNode {
+				t.Errorf("source node = %q, want %q", got, test.srcNode)
+			}
+			if got := cpNodeName(cc, dst); got != test.dstNode {
+				t.Errorf("target node = %q, want %q", got, test.dstNode)
+			}
+			if src.Path != test.srcPath {
+				t.Errorf("source path = %q, want %q", src.Path, test.srcPath)
+			}
+			if dst.Path != test.dstPath {
+				t.Errorf("target path = %q, want %q", dst.Path, test.dstPath)
+			}
+		})
+	}
+}
+
+func cpNodeName(cc config.ClusterConfig, p cpPath) string {
+	if p.Node == nil {
+		return ""
+	}
+	if p.Node.ControlPlane {
+		return config.MachineName(cc, *p.Node)
+	}
+	return p.Node.Name
+}
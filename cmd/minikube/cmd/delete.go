@@ -46,6 +46,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
 	"k8s.io/minikube/pkg/minikube/style"
 )
 
@@ -284,6 +285,11 @@ func deleteProfile(profile *config.Profile) error {
 	if profile.Config != nil {
 		klog.Infof("%s configuration: %+v", profile.Name, profile.Config)
 
+		// snapshot images and libvirt snapshots are not removed along with the nodes
+		if err := snapshot.DeleteAll(profile.Config); err != nil {
+			out.WarningT("Failed to delete snapshots of {{.profile_name}}: {{.error}}", out.V{"profile_name": profile.Name, "error": err})
+		}
+
 		// if driver is oci driver, delete containers and volumes
 		if driver.IsKIC(profile.Config.Driver) {
 			out.Step(style.DeletingHost, `Deleting "{{.profile_name}}" in {{.driver_name}} ...`, out.V{"profile_name": profile.Name, "driver_name": profile.Config.Driver})
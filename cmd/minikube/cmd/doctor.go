@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/doctor"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+var (
+	doctorDriver string
+	doctorOutput string
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks whether the host is ready to start a cluster",
+	Long: `Runs preflight checks of the host: the health of the driver, the CPUs, memory and free disk space, the container daemon of the docker and podman drivers,
+the ports the none driver listens on, the proxy settings, DNS, and whether the image registries and artifact storage can be reached.
+Each check passes, warns or fails with advice on how to fix it. minikube doctor exits with a nonzero code if any check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if doctorOutput != "text" && doctorOutput != "json" {
+			exit.Message(reason.Usage, "Sorry, the output format {{.output}} is not supported. Valid options are: text, json", out.V{"output": doctorOutput})
+		}
+
+		dc := doctor.Config{
+			Driver:            doctorDriver,
+			MinCPUs:           minimumCPUS,
+			MinMemory:         minUsableMem,
+			RecommendedMemory: minRecommendedMem,
+			MinDisk:           minimumDiskSize,
+		}
+		recommended, err := util.CalculateSizeInMB(defaultDiskSize)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to parse disk size '{{.diskSize}}': {{.error}}", out.V{"diskSize": defaultDiskSize, "error": err})
+		}
+		dc.RecommendedDisk = recommended
+
+		cc, err := config.Load(ClusterFlagValue())
+		if err != nil && !config.IsNotExist(err) {
+			klog.Warningf("unable to load profile %q: %v", ClusterFlagValue(), err)
+		}
+		if cc != nil {
+			if dc.Driver == "" {
+				dc.Driver = cc.Driver
+			}
+			if cp, err := config.PrimaryControlPlane(cc); err == nil {
+				dc.ClusterIP = cp.IP
+			}
+		}
+		if dc.Driver == "" {
+			dc.Driver = viper.GetString("driver")
+		}
+
+		results := doctor.Run(doctor.Checks(dc))
+		if doctorOutput == "json" {
+			data, err := json.Marshal(results)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "doctor json failure", err)
+			}
+			out.Ln(string(data))
+		} else {
+			printDoctorResults(results)
+		}
+		if code := doctor.ExitCode(results); code != 0 {
+			os.Exit(code)
+		}
+	},
+}
+
+// printDoctorResults prints the results of the checks as text
+func printDoctorResults(results []doctor.Result) {
+	failed, warned := 0, 0
+	for _, r := range results {
+		st := style.Check
+		switch r.Status {
+		case doctor.Warn:
+			st = style.Warning
+			warned++
+		case doctor.Fail:
+			st = style.Failure
+			failed++
+		}
+		out.Step(st, "{{.name}}: {{.message}}", out.V{"name": r.Name, "message": r.Message})
+		for _, l := range strings.Split(r.Advice, "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				out.Infof("{{.advice}}", out.V{"advice": l})
+			}
+		}
+		if r.URL != "" {
+			out.Infof("{{.url}}", out.V{"url": r.URL})
+		}
+	}
+
+	if failed > 0 {
+		out.Step(style.Sad, "{{.failed}} of {{.count}} checks failed, {{.warned}} warned. Fix the failures before running minikube start.", out.V{"failed": failed, "warned": warned, "count": len(results)})
+		return
+	}
+	if warned > 0 {
+		out.Step(style.Tip, "All {{.count}} checks passed, {{.warned}} with warnings", out.V{"count": len(results), "warned": warned})
+		return
+	}
+	out.Step(style.Ready, "All {{.count}} checks passed", out.V{"count": len(results)})
+}
+
+func init() {
+	doctorCmd.Flags().StringVar(&doctorDriver, "driver", "", fmt.Sprintf("Driver to check (%s). Defaults to the driver of the profile, or the one minikube start would pick.", strings.Join(driver.SupportedDrivers(), ", ")))
+	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// imageCmd represents the image command
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage images in minikube",
+	Long:  "Build, list, load, pull, remove or save images in the container runtime of every node in the cluster",
+}
+
+// loadImageCmd represents the image load command
+var loadImageCmd = &cobra.Command{
+	Use:     "load IMAGE | ARCHIVE",
+	Short:   "Load an image into minikube",
+	Long:    "Load an image from the local daemon, a remote registry or an image archive into every node of the cluster",
+	Example: "minikube image load busybox:latest\nminikube image load ./busybox.tar",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image or an image archive to load into minikube via <minikube image load IMAGE>")
+		}
+		profile := mustloadProfile()
+
+		var images, archives []string
+		for _, arg := range args {
+			// Anything which exists on disk is treated as an image archive
+			if fi, err := os.Stat(arg); err == nil && !fi.IsDir() {
+				abs, err := filepath.Abs(arg)
+				if err != nil {
+					exit.Error(reason.HostPathMissing, "Unable to find archive", err)
+				}
+				archives = append(archives, abs)
+				continue
+			}
+			images = append(images, arg)
+		}
+
+		if len(images) > 0 {
+			useRegistryAuths()
+			if err := image.SaveToDir(images, constants.ImageCacheDir); err != nil {
+				exit.Error(reason.GuestImageLoad, "Failed to cache images", err)
+			}
+			if err := machine.DoLoadImages(images, []*config.Profile{profile}, constants.ImageCacheDir); err != nil {
+				exit.Error(reason.GuestImageLoad, "Failed to load images", err)
+			}
+		}
+		if len(archives) > 0 {
+			if err := machine.LoadArchives(archives, []*config.Profile{profile}); err != nil {
+				exit.Error(reason.GuestImageLoad, "Failed to load image archives", err)
+			}
+		}
+	},
+}
+
+// removeImageCmd represents the image rm command
+var removeImageCmd = &cobra.Command{
+	Use:     "rm IMAGE [IMAGE...]",
+	Short:   "Remove one or more images",
+	Long:    "Remove one or more images from the container runtime of every node in the cluster",
+	Example: "minikube image rm busybox:latest",
+	Aliases: []string{"remove"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image to remove via <minikube image rm IMAGE>")
+		}
+		profile := mustloadProfile()
+		if err := machine.RemoveImages(args, profile); err != nil {
+			exit.Error(reason.GuestImageRemove, "Failed to remove images", err)
+		}
+	},
+}
+
+// pullImageCmd represents the image pull command
+var pullImageCmd = &cobra.Command{
+	Use:     "pull IMAGE [IMAGE...]",
+	Short:   "Pull images",
+	Long:    "Pull images into the container runtime of every node in the cluster",
+	Example: "minikube image pull busybox:latest",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image to pull via <minikube image pull IMAGE>")
+		}
+		profile := mustloadProfile()
+		if err := machine.PullImages(args, profile); err != nil {
+			exit.Error(reason.GuestImagePull, "Failed to pull images", err)
+		}
+	},
+}
+
+// listImageCmd represents the image ls command
+var listImageCmd = &cobra.Command{
+	Use:     "ls",
+	Short:   "List images",
+	Long:    "List the images available in the container runtime of every node in the cluster",
+	Example: "minikube image ls",
+	Aliases: []string{"list"},
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := mustloadProfile()
+		images, err := machine.ListImages(profile)
+		if err != nil {
+			exit.Error(reason.GuestImageList, "Failed to list images", err)
+		}
+		for _, img := range images {
+			out.Ln(img)
+		}
+	},
+}
+
+// saveImageCmd represents the image save command
+var saveImageCmd = &cobra.Command{
+	Use:     "save IMAGE [ARCHIVE]",
+	Short:   "Save an image from minikube",
+	Long:    "Save an image from the container runtime of a node into an image archive on the host. Defaults to the minikube image cache.",
+	Example: "minikube image save busybox:latest\nminikube image save busybox:latest ./busybox.tar",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 || len(args) > 2 {
+			exit.Message(reason.Usage, "Please provide an image to save via <minikube image save IMAGE [ARCHIVE]>")
+		}
+		img := args[0]
+		dst := localpath.SanitizeCacheDir(filepath.Join(constants.ImageCacheDir, img))
+		if len(args) == 2 {
+			dst = args[1]
+		}
+
+		co := mustload.Running(ClusterFlagValue())
+		n := co.CP.Node
+		if nodeName != "" {
+			var err error
+			n, _, err = node.Retrieve(*co.Config, nodeName)
+			if err != nil {
+				exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": nodeName})
+			}
+		}
+
+		h, err := machine.LoadHost(co.API, config.MachineName(*co.Config, *n))
+		if err != nil {
+			exit.Error(reason.GuestLoadHost, "Error getting host", err)
+		}
+		runner, err := machine.CommandRunner(h)
+		if err != nil {
+			exit.Error(reason.InternalCommandRunner, "Failed to get command runner", err)
+		}
+
+		if err := machine.SaveImage(co.Config, runner, img, dst); err != nil {
+			exit.Error(reason.GuestImageSave, "Failed to save image", err)
+		}
+		out.Step(style.Check, "Saved {{.image}} to {{.path}}", out.V{"image": img, "path": dst})
+	},
+}
+
+var (
+	buildTag      string
+	buildFile     string
+	buildAllNodes bool
+)
+
+// buildImageCmd represents the image build command
+var buildImageCmd = &cobra.Command{
+	Use:     "build PATH",
+	Short:   "Build a container image in minikube",
+	Long:    "Build a container image from a context directory or archive, using the container runtime of the cluster. The image is immediately available to pods on the nodes it was built on.",
+	Example: "minikube image build -t my-app:latest .",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide a build context via <minikube image build PATH>")
+		}
+		src, err := filepath.Abs(args[0])
+		if err != nil {
+			exit.Error(reason.HostPathMissing, "Unable to find build context", err)
+		}
+		if _, err := os.Stat(src); err != nil {
+			exit.Error(reason.HostPathMissing, "Unable to find build context", err)
+		}
+
+		profile := mustloadProfile()
+		built, err := machine.BuildImage(src, buildFile, buildTag, buildAllNodes, nodeName, profile)
+		if err != nil {
+			exit.Error(reason.GuestImageBuild, "Failed to build image", err)
+		}
+		nodes := strings.Join(built, ", ")
+		if buildTag == "" {
+			out.Step(style.Check, "Built image on {{.nodes}}", out.V{"nodes": nodes})
+			return
+		}
+		out.Step(style.Check, "Built {{.tag}} on {{.nodes}}", out.V{"tag": buildTag, "nodes": nodes})
+	},
+}
+
+// mustloadProfile returns the profile of a running cluster, exiting if it is not running
+func mustloadProfile() *config.Profile {
+	cname := ClusterFlagValue()
+	mustload.Running(cname)
+	profile, err := config.LoadProfile(cname)
+	if err != nil {
+		exit.Error(reason.Usage, "loading profile", err)
+	}
+	return profile
+}
+
+func init() {
+	saveImageCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to save the image from. Defaults to the primary control plane.")
+	buildImageCmd.Flags().StringVarP(&buildTag, "tag", "t", "", "Tag to apply to the new image (optional)")
+	buildImageCmd.Flags().StringVarP(&buildFile, "file", "f", "", "Path to the Dockerfile, relative to the build context (default: Dockerfile)")
+	buildImageCmd.Flags().BoolVar(&buildAllNodes, "all", false, "Build the image on every node of the cluster")
+	buildImageCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to build the image on. Defaults to the primary control plane.")
+
+	imageCmd.AddCommand(loadImageCmd)
+	imageCmd.AddCommand(removeImageCmd)
+	imageCmd.AddCommand(pullImageCmd)
+	imageCmd.AddCommand(listImageCmd)
+	imageCmd.AddCommand(saveImageCmd)
+	imageCmd.AddCommand(buildImageCmd)
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	userGroups     []string
+	userTTL        time.Duration
+	usersOutput    string
+	userUseContext bool
+	credentialUser string
+)
+
+// kubeconfigCmd represents the kubeconfig command
+var kubeconfigCmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Manage the kubeconfig users of a cluster",
+	Long:  "Issue client certificates signed by the CA of a cluster to users and groups, add them to the kubeconfig as contexts, and revoke them. Also provides the credential plugin of kubeconfig entries, and the path of the kubeconfig file of a cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube kubeconfig [add-user|list-users|revoke-user|credential|path]")
+	},
+}
+
+// kubeconfigAddUserCmd represents the kubeconfig add-user command
+var kubeconfigAddUserCmd = &cobra.Command{
+	Use:   "add-user NAME",
+	Short: "Add a user signed by the CA of a cluster to the kubeconfig",
+	Long: `Signs a client certificate for a user in groups with the CA of the cluster, and adds a "NAME@PROFILE" context for it to the kubeconfig.
+Kubernetes authenticates the user by the common name of the certificate and the groups by its organizations, so the user has the permissions RBAC grants to them.`,
+	Example: "minikube kubeconfig add-user alice --groups dev,viewers --ttl 24h",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube kubeconfig add-user NAME [--groups GROUP,...] [--ttl DURATION]")
+		}
+		name := args[0]
+		cname := ClusterFlagValue()
+		_, cc := mustload.Partial(cname)
+
+		u, err := bootstrapper.AddUser(cc.KubernetesConfig, name, userGroups, userTTL)
+		if err != nil {
+			exit.Error(reason.GuestCert, "Failed to add the user", err)
+		}
+
+		context := userContext(name, cname)
+		kcs := &kubeconfig.Settings{
+			ClusterName:          cname,
+			UserName:             context,
+			ContextName:          context,
+			Namespace:            cc.KubernetesConfig.Namespace,
+			ClusterServerAddress: clusterServer(cname),
+			ClientCertificate:    bootstrapper.UserCert(cname, name),
+			ClientKey:            bootstrapper.UserKey(cname, name),
+			CertificateAuthority: bootstrapper.ClusterCACert(cc.KubernetesConfig),
+			KeepContext:          !userUseContext,
+			EmbedCerts:           cc.EmbedCerts,
+		}
+		if cc.ExecCredential {
+			if kcs.ExecCredential, err = kubeconfig.ExecCredentialPlugin(cname, name); err != nil {
+				exit.Error(reason.HostKubeconfigUpdate, "Failed to set up the credential plugin", err)
+			}
+		}
+		kcs.SetPath(kubeconfig.PathFor(cname))
+		if err := kubeconfig.Update(kcs); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "Failed to update the kubeconfig", err)
+		}
+
+		groups := strings.Join(u.Groups, ", ")
+		if groups == "" {
+			groups = "no groups"
+		}
+		out.Step(style.Celebrate, `Added the "{{.context}}" context for user {{.name}} in {{.groups}}, valid until {{.expiry}}`, out.V{"context": context, "name": name, "groups": groups, "expiry": u.NotAfter.Format(time.RFC3339)})
+		if !userUseContext {
+			out.Step(style.Tip, "To use it, run: kubectl --context {{.context}} get pods", out.V{"context": context})
+		}
+	},
+}
+
+// kubeconfigListUsersCmd represents the kubeconfig list-users command
+var kubeconfigListUsersCmd = &cobra.Command{
+	Use:     "list-users",
+	Short:   "List the users added to a cluster",
+	Long:    "List the users added with minikube kubeconfig add-user, with their groups, the serial of their certificate, and whether it was revoked",
+	Example: "minikube kubeconfig list-users -o json",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		mustload.Partial(cname)
+
+		users, err := bootstrapper.Users(cname)
+		if err != nil {
+			exit.Error(reason.GuestCert, "Failed to list the users", err)
+		}
+
+		switch strings.ToLower(usersOutput) {
+		case "json":
+			data, err := json.Marshal(users)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "users json failure", err)
+			}
+			out.Ln(string(data))
+		case "table":
+			if len(users) == 0 {
+				out.Step(style.Empty, `No users of "{{.profile}}" were found. You can add one using "{{.cmd}}".`, out.V{"profile": cname, "cmd": mustload.ExampleCmd(cname, "kubeconfig add-user NAME")})
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Name", "Groups", "Serial", "Expires", "Status"})
+			table.SetAutoFormatHeaders(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			now := time.Now()
+			for _, u := range users {
+				status := "active"
+				switch {
+				case u.Revoked:
+					status = "revoked"
+				case now.After(u.NotAfter):
+					status = "expired"
+				}
+				table.Append([]string{u.Name, strings.Join(u.Groups, ","), u.Serial, u.NotAfter.Format(time.RFC3339), status})
+			}
+			table.Render()
+		default:
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json'", usersOutput))
+		}
+	},
+}
+
+// kubeconfigRevokeUserCmd represents the kubeconfig revoke-user command
+var kubeconfigRevokeUserCmd = &cobra.Command{
+	Use:   "revoke-user NAME",
+	Short: "Revoke a user added to a cluster",
+	Long: `Deletes the context and the certificate of a user from the kubeconfig and the host, and records the serial of the certificate in the denylist of the cluster.
+Kubernetes does not check the revocation of client certificates: the API server accepts copies of the certificate until it expires, or until the CA of the cluster changes.`,
+	Example: "minikube kubeconfig revoke-user alice",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube kubeconfig revoke-user NAME")
+		}
+		name := args[0]
+		cname := ClusterFlagValue()
+		mustload.Partial(cname)
+
+		u, err := bootstrapper.RevokeUser(cname, name)
+		notFound := errors.Is(err, bootstrapper.ErrUserNotFound)
+		if err != nil && !notFound {
+			exit.Error(reason.GuestCert, "Failed to revoke the user", err)
+		}
+		// the context outlives the certificate if a previous revocation failed to update the kubeconfig
+		context := userContext(name, cname)
+		if err := kubeconfig.DeleteUser(context, kubeconfig.PathFor(cname)); err != nil {
+			exit.Error(reason.HostKubeconfigDeleteCtx, "Failed to delete the context of the user", err)
+		}
+		if notFound {
+			out.Step(style.Deleted, `User {{.name}} has no certificate: deleted the "{{.context}}" context if it existed`, out.V{"name": name, "context": context})
+			return
+		}
+
+		out.Step(style.Deleted, `Revoked user {{.name}}: deleted the "{{.context}}" context, and added serial {{.serial}} to the denylist`, out.V{"name": name, "context": context, "serial": u.Serial})
+		out.WarningT("Copies of the certificate are accepted by the cluster until {{.expiry}}", out.V{"expiry": u.NotAfter.Format(time.RFC3339)})
+	},
+}
+
+// kubeconfigCredentialCmd represents the kubeconfig credential command
+var kubeconfigCredentialCmd = &cobra.Command{
+	Use:   "credential",
+	Short: "Print the client certificate of a cluster as an exec credential",
+	Long: `Prints the current client certificate and key of a cluster, or of one of its users, as the ExecCredential JSON of a client-go credential plugin.
+minikube start --embed-certs=exec writes kubeconfig entries which run it, so that they keep working when the certificates are regenerated.`,
+	Example: "minikube kubeconfig credential -p minikube --user alice",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		certPath, keyPath := localpath.ClientCert(cname), localpath.ClientKey(cname)
+		if credentialUser != "" {
+			certPath, keyPath = bootstrapper.UserCert(cname, credentialUser), bootstrapper.UserKey(cname, credentialUser)
+		}
+
+		data, err := kubeconfig.ExecCredential(certPath, keyPath)
+		if err != nil {
+			exit.Error(reason.GuestCert, "Failed to read the client certificate", err)
+		}
+		out.Ln(string(data))
+	},
+}
+
+// kubeconfigPathCmd represents the kubeconfig path command
+var kubeconfigPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the kubeconfig file with the context of a cluster",
+	Long: `Prints the path of the kubeconfig file with the context of a cluster: the file in the profile directory if the cluster was started with --kubeconfig-per-profile,
+or else the first file of KUBECONFIG, or ~/.kube/config.`,
+	Example: "export KUBECONFIG=$(minikube kubeconfig path -p minikube)",
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		mustload.Partial(cname)
+		out.Ln(kubeconfig.PathFor(cname))
+	},
+}
+
+// userContext returns the name of the kubeconfig context of a user of a cluster
+func userContext(name, cname string) string {
+	return fmt.Sprintf("%s@%s", name, cname)
+}
+
+// clusterServer returns the address of the API server of a cluster, as in the kubeconfig if possible
+func clusterServer(cname string) string {
+	hostname, port, err := kubeconfig.Endpoint(cname)
+	if err != nil {
+		klog.Warningf("unable to get the endpoint of %s from the kubeconfig, using the control plane: %v", cname, err)
+		co := mustload.Running(cname)
+		hostname, port = co.CP.Hostname, co.CP.Port
+	}
+	return fmt.Sprintf("https://%s", net.JoinHostPort(hostname, strconv.Itoa(port)))
+}
+
+func init() {
+	kubeconfigAddUserCmd.Flags().StringSliceVar(&userGroups, "groups", nil, "Comma separated list of the groups of the user")
+	kubeconfigAddUserCmd.Flags().DurationVar(&userTTL, "ttl", 0, "How long the certificate of the user is valid for. Defaults to the validity of the other certificates of the cluster.")
+	kubeconfigAddUserCmd.Flags().BoolVar(&userUseContext, "use-context", false, "If true, set the context of the user as the current context")
+	kubeconfigCredentialCmd.Flags().StringVar(&credentialUser, "user", "", "The user added with minikube kubeconfig add-user to get the client certificate of, instead of the admin of the cluster")
+	kubeconfigListUsersCmd.Flags().StringVarP(&usersOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+
+	kubeconfigCmd.AddCommand(kubeconfigAddUserCmd)
+	kubeconfigCmd.AddCommand(kubeconfigListUsersCmd)
+	kubeconfigCmd.AddCommand(kubeconfigRevokeUserCmd)
+	kubeconfigCmd.AddCommand(kubeconfigCredentialCmd)
+	kubeconfigCmd.AddCommand(kubeconfigPathCmd)
+}
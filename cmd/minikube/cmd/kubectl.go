@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -49,6 +50,9 @@ minikube kubectl -- get pods --namespace kube-system`,
 		}
 
 		klog.Infof("Running %s %v", c.Path, args)
+		if p := kubeconfig.PathFor(co.Config.Name); p != kubeconfig.PathFromEnv() {
+			c.Env = append(os.Environ(), fmt.Sprintf("%s=%s", constants.KubeconfigEnvVar, p))
+		}
 		c.Stdin = os.Stdin
 		c.Stdout = os.Stdout
 		c.Stderr = os.Stderr
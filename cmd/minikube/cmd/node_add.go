@@ -17,10 +17,14 @@ limitations under the License.
 package cmd
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/mustload"
@@ -28,11 +32,21 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
+	pkgutil "k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
 )
 
 var (
-	cp     bool
-	worker bool
+	cp           bool
+	worker       bool
+	nodeCPUs     int
+	nodeMemory   string
+	nodeDiskSize string
+	nodeLabels   []string
+	nodeTaints   []string
+	nodeVersion  string
+	nodeCount    int
+	nodeParallel int
 )
 
 var nodeAddCmd = &cobra.Command{
@@ -47,9 +61,15 @@ var nodeAddCmd = &cobra.Command{
 			out.FailureT("none driver does not support multi-node clusters")
 		}
 
-		name := node.Name(len(cc.Nodes) + 1)
+		if cp && !config.IsHA(*cc) {
+			exit.Message(reason.Usage, "Control plane nodes can only be added to a cluster started with --ha")
+		}
 
-		out.Step(style.Happy, "Adding node {{.name}} to cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
+		if nodeCount < 1 {
+			exit.Message(reason.Usage, "The number of nodes to add must be at least 1")
+		}
+
+		name := node.Name(len(cc.Nodes) + 1)
 
 		// TODO: Deal with parameters better. Ideally we should be able to acceot any node-specific minikube start params here.
 		n := config.Node{
@@ -58,6 +78,8 @@ var nodeAddCmd = &cobra.Command{
 			ControlPlane:      cp,
 			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		}
+		setNodeResources(cmd, cc, &n)
+		setNodeVersion(cc, &n)
 
 		// Make sure to decrease the default amount of memory we use per VM if this is the first worker node
 		if len(cc.Nodes) == 1 {
@@ -70,6 +92,13 @@ var nodeAddCmd = &cobra.Command{
 			}
 		}
 
+		if nodeCount > 1 {
+			addNodes(cmd, cc, n)
+			return
+		}
+
+		out.Step(style.Happy, "Adding node {{.name}} to cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
+
 		if err := node.Add(cc, n, false); err != nil {
 			_, err := maybeDeleteAndRetry(cmd, *cc, n, nil, err)
 			if err != nil {
@@ -85,11 +114,144 @@ var nodeAddCmd = &cobra.Command{
 	},
 }
 
+// addNodes adds --count nodes shaped like the given one: control planes one at a time, workers in parallel
+func addNodes(cmd *cobra.Command, cc *config.ClusterConfig, tmpl config.Node) {
+	var nodes []config.Node
+	var names []string
+	for i := 0; i < nodeCount; i++ {
+		n := tmpl
+		n.Name = node.Name(len(cc.Nodes) + 1 + i)
+		nodes = append(nodes, n)
+		names = append(names, config.MachineName(*cc, n))
+	}
+
+	out.Step(style.Happy, "Adding nodes {{.names}} to cluster {{.cluster}}", out.V{"names": strings.Join(names, ", "), "cluster": cc.Name})
+
+	delOnFail := viper.GetBool(deleteOnFailure)
+	var err error
+	if tmpl.ControlPlane {
+		for _, n := range nodes {
+			if err = node.Add(cc, n, delOnFail); err != nil {
+				break
+			}
+		}
+	} else {
+		err = addWorkers(cc, nodes, nodeParallel, delOnFail)
+	}
+	if err != nil {
+		if _, err := maybeDeleteAndRetry(cmd, *cc, tmpl, nil, err); err != nil {
+			exit.Error(reason.GuestNodeAdd, "failed to add nodes", err)
+		}
+	}
+
+	if err := config.SaveProfile(cc.Name, cc); err != nil {
+		exit.Error(reason.HostSaveProfile, "failed to save config", err)
+	}
+
+	out.Step(style.Ready, "Successfully added {{.names}} to {{.cluster}}!", out.V{"names": strings.Join(names, ", "), "cluster": cc.Name})
+}
+
 func init() {
 	// TODO(https://github.com/kubernetes/minikube/issues/7366): We should figure out which minikube start flags to actually import
 	nodeAddCmd.Flags().BoolVar(&cp, "control-plane", false, "If true, the node added will also be a control plane in addition to a worker.")
 	nodeAddCmd.Flags().BoolVar(&worker, "worker", true, "If true, the added node will be marked for work. Defaults to true.")
+	nodeAddCmd.Flags().IntVar(&nodeCPUs, "cpus", 0, "Number of CPUs allocated to the node. Defaults to the CPUs of the cluster.")
+	nodeAddCmd.Flags().StringVar(&nodeMemory, "memory", "", "Amount of RAM to allocate to the node (format: <number>[<unit>], where unit = b, k, m or g). Defaults to the memory of the cluster.")
+	nodeAddCmd.Flags().StringVar(&nodeDiskSize, "disk-size", "", "Disk size allocated to the node (format: <number>[<unit>], where unit = b, k, m or g). Defaults to the disk size of the cluster.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeLabels, "labels", nil, "Kubernetes labels of the node, as a comma separated list of key=value pairs.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeTaints, "taints", nil, "Kubernetes taints of the node, as a comma separated list of key[=value]:effect, where effect is NoSchedule, PreferNoSchedule or NoExecute.")
+	nodeAddCmd.Flags().StringVar(&nodeVersion, kubernetesVersion, "", "The Kubernetes version the node runs, at most two minor versions older than the cluster. Defaults to the version of the cluster, which the node then follows on upgrades.")
+	nodeAddCmd.Flags().IntVar(&nodeCount, "count", 1, "The number of nodes to add. Worker nodes are created, configured and joined in parallel.")
+	nodeAddCmd.Flags().IntVar(&nodeParallel, parallelism, node.DefaultParallelism, "The maximum number of worker nodes to add at the same time.")
 	nodeAddCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 
 	nodeCmd.AddCommand(nodeAddCmd)
 }
+
+// setNodeResources sets the resources, labels and taints requested for a new node
+func setNodeResources(cmd *cobra.Command, cc *config.ClusterConfig, n *config.Node) {
+	// containers share the disk of their host, so only the VM drivers can size it
+	respected := map[string]bool{
+		"cpus":      driver.HasNodeResources(cc.Driver),
+		"memory":    driver.HasNodeResources(cc.Driver),
+		"disk-size": driver.HasNodeResources(cc.Driver) && !driver.IsKIC(cc.Driver),
+	}
+	for _, f := range []string{"cpus", "memory", "disk-size"} {
+		if cmd.Flags().Changed(f) && !respected[f] {
+			out.WarningT("The '{{.name}}' driver does not respect the --{{.flag}} flag of a node", out.V{"name": cc.Driver, "flag": f})
+		}
+	}
+
+	if nodeCPUs != 0 {
+		if nodeCPUs < minimumCPUS {
+			exitIfNotForced(reason.RsrcInsufficientCores, "Requested cpu count {{.requested_cpus}} is less than the minimum allowed of {{.minimum_cpus}}", out.V{"requested_cpus": nodeCPUs, "minimum_cpus": minimumCPUS})
+		}
+		n.CPUs = nodeCPUs
+	}
+
+	if nodeMemory != "" {
+		mem, err := pkgutil.CalculateSizeInMB(nodeMemory)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to parse memory '{{.memory}}': {{.error}}", out.V{"memory": nodeMemory, "error": err})
+		}
+		validateRequestedMemorySize(mem, cc.Driver)
+		n.Memory = mem
+	}
+
+	if nodeDiskSize != "" {
+		size, err := pkgutil.CalculateSizeInMB(nodeDiskSize)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to parse disk size '{{.diskSize}}': {{.error}}", out.V{"diskSize": nodeDiskSize, "error": err})
+		}
+		if size < minimumDiskSize {
+			exitIfNotForced(reason.RsrcInsufficientStorage, "Requested disk size {{.requested_size}} is less than minimum of {{.minimum_size}}", out.V{"requested_size": size, "minimum_size": minimumDiskSize})
+		}
+		n.DiskSize = size
+	}
+
+	labels, err := config.ParseNodeLabels(nodeLabels)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid --labels: {{.error}}", out.V{"error": err})
+	}
+	n.Labels = labels
+
+	for _, t := range nodeTaints {
+		if err := config.ValidateNodeTaint(t); err != nil {
+			exit.Message(reason.Usage, "Invalid --taints: {{.error}}", out.V{"error": err})
+		}
+	}
+	n.Taints = nodeTaints
+}
+
+// setNodeVersion pins a new node to the Kubernetes version requested for it, so that it can lag behind the control plane
+func setNodeVersion(cc *config.ClusterConfig, n *config.Node) {
+	if nodeVersion == "" {
+		return
+	}
+	if n.ControlPlane {
+		exit.Message(reason.Usage, "Control plane nodes run the Kubernetes version of the cluster, so --kubernetes-version can not be used with --control-plane")
+	}
+
+	nv, err := pkgutil.ParseKubernetesVersion(nodeVersion)
+	if err != nil {
+		exit.Message(reason.Usage, "Unable to parse Kubernetes version {{.version}}: {{.error}}", out.V{"version": nodeVersion, "error": err})
+	}
+	cv, err := pkgutil.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		exit.Error(reason.InternalSemverParse, "Unable to parse the Kubernetes version of the cluster", err)
+	}
+	oldest, err := pkgutil.ParseKubernetesVersion(constants.OldestKubernetesVersion)
+	if err != nil {
+		exit.Error(reason.InternalSemverParse, "Unable to parse the oldest Kubernetes version", err)
+	}
+
+	if nv.LT(oldest) {
+		exitIfNotForced(reason.KubernetesTooOld, "Specified Kubernetes version {{.specified}} is less than the oldest supported version: {{.oldest}}", out.V{"specified": nv, "oldest": constants.OldestKubernetesVersion})
+	}
+	if err := bsutil.ValidateKubeletSkew(cv, nv); err != nil {
+		exitIfNotForced(reason.KubernetesNodeSkew, "Node {{.name}} can not run Kubernetes v{{.version}}: {{.error}}", out.V{"name": n.Name, "version": nv, "error": err})
+	}
+
+	n.KubernetesVersion = version.VersionPrefix + nv.String()
+	n.PinnedVersion = true
+}
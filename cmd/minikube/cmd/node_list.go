@@ -48,7 +48,7 @@ var nodeListCmd = &cobra.Command{
 
 		for _, n := range cc.Nodes {
 			machineName := config.MachineName(*cc, n)
-			fmt.Printf("%s\t%s\n", machineName, n.IP)
+			fmt.Printf("%s\t%s\t%s\n", machineName, n.IP, n.KubernetesVersion)
 		}
 		os.Exit(0)
 	},
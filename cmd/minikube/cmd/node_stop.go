@@ -18,8 +18,11 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/node"
@@ -52,9 +55,30 @@ var nodeStopCmd = &cobra.Command{
 			out.FatalT("Failed to stop node {{.name}}", out.V{"name": name})
 		}
 		out.Step(style.Stopped, "Successfully stopped node {{.name}}", out.V{"name": machineName})
+
+		// Container drivers reach a highly available cluster through the port of a control plane, so point kubectl at one still running
+		if n.ControlPlane && config.IsHA(*cc) && driver.NeedsPortForward(cc.Driver) {
+			failoverEndpoint(cc)
+		}
 	},
 }
 
+// failoverEndpoint points the kubeconfig of a highly available cluster at a running control plane
+func failoverEndpoint(cc *config.ClusterConfig) {
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		exit.Error(reason.GuestCpConfig, "Unable to find control plane", err)
+	}
+	hostname, _, port, err := driver.ControlPlaneEndpoint(cc, &cp, cc.Driver)
+	if err != nil {
+		out.WarningT("No control plane of {{.cluster}} is reachable: {{.error}}", out.V{"cluster": cc.Name, "error": err})
+		return
+	}
+	if _, err := kubeconfig.UpdateEndpoint(cc.Name, hostname, port, kubeconfig.PathFor(cc.Name), bootstrapper.ClusterCACert(cc.KubernetesConfig), kubeconfig.NewExtension()); err != nil {
+		out.WarningT("Unable to update the kubeconfig of {{.cluster}}: {{.error}}", out.V{"cluster": cc.Name, "error": err})
+	}
+}
+
 func init() {
 	nodeCmd.AddCommand(nodeStopCmd)
 }
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgpreload "k8s.io/minikube/pkg/minikube/preload"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	preloadContainerRuntime  string
+	preloadKubernetesVersion string
+	preloadImages            []string
+	preloadAddons            []string
+)
+
+// preloadCmd represents the set of preload subcommands
+var preloadCmd = &cobra.Command{
+	Use:   "preload",
+	Short: "Manage preloaded images tarballs",
+	Long:  "Manage the tarballs of preloaded images and Kubernetes binaries, which speed up starting a cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube preload [build]")
+	},
+}
+
+// preloadBuildCmd represents the preload build command
+var preloadBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a preloaded images tarball locally",
+	Long: `Builds a preloaded images tarball for a Kubernetes version and container runtime in a temporary docker container, including extra images and the images of addons.
+The tarball replaces the official one in the cache, so that the next "minikube start" with the same Kubernetes version and container runtime uses it automatically.`,
+	Example: "minikube preload build --kubernetes-version=v1.20.2 --container-runtime=containerd --images=registry.example.com/platform/base:1.0 --addons=ingress",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.Message(reason.Usage, "Usage: minikube preload build --kubernetes-version VERSION --container-runtime RUNTIME")
+		}
+		if _, err := cruntime.New(cruntime.Config{Type: preloadContainerRuntime}); err != nil {
+			exit.Message(reason.Usage, "Invalid container runtime '{{.runtime}}', valid options are: {{.valid}}", out.V{"runtime": preloadContainerRuntime, "valid": strings.Join(cruntime.ValidRuntimes(), ", ")})
+		}
+		v, err := semver.Make(strings.TrimPrefix(preloadKubernetesVersion, version.VersionPrefix))
+		if err != nil {
+			exit.Message(reason.Usage, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": preloadKubernetesVersion, "error": err})
+		}
+		if _, err := exec.LookPath(oci.Docker); err != nil {
+			exit.Error(reason.DrvNotFound, fmt.Sprintf("%s not found on PATH, but it is needed to build the tarball", oci.Docker), err)
+		}
+
+		cfg := pkgpreload.Config{
+			KubernetesVersion: version.VersionPrefix + v.String(),
+			ContainerRuntime:  preloadContainerRuntime,
+			ExtraImages:       preloadImages,
+		}
+		for _, name := range preloadAddons {
+			addon, ok := assets.Addons[name]
+			if !ok {
+				exit.Message(reason.Usage, "The '{{.name}}' addon is not a valid addon packaged with minikube", out.V{"name": name})
+			}
+			imgs, err := addon.Images(config.KubernetesConfig{KubernetesVersion: cfg.KubernetesVersion, ContainerRuntime: cfg.ContainerRuntime})
+			if err != nil {
+				exit.Error(reason.InternalAddonEnable, "Failed to get the images of the addon", err)
+			}
+			cfg.ExtraImages = append(cfg.ExtraImages, imgs...)
+		}
+
+		out.Step(style.Caching, "Building the preload tarball for Kubernetes {{.version}} on {{.runtime}} ...", out.V{"version": cfg.KubernetesVersion, "runtime": cfg.ContainerRuntime})
+		dst := download.TarballPath(cfg.KubernetesVersion, cfg.ContainerRuntime)
+		tmp := dst + ".build"
+		if err := os.MkdirAll(filepath.Dir(tmp), 0o755); err != nil {
+			exit.Error(reason.HostHomeMkdir, "Failed to create the cache directory", err)
+		}
+		if err := pkgpreload.Build(cfg, tmp); err != nil {
+			os.Remove(tmp)
+			exit.Error(reason.GuestPreloadBuild, "Failed to build the preload tarball", err)
+		}
+		if err := download.SavePreload(tmp, cfg.KubernetesVersion, cfg.ContainerRuntime); err != nil {
+			os.Remove(tmp)
+			exit.Error(reason.InetCacheTar, "Failed to save the preload tarball", err)
+		}
+		out.Step(style.Check, "Saved the preload tarball to {{.path}}", out.V{"path": dst})
+		out.Step(style.Tip, "The next start with Kubernetes {{.version}} on {{.runtime}} uses it automatically", out.V{"version": cfg.KubernetesVersion, "runtime": cfg.ContainerRuntime})
+	},
+}
+
+func init() {
+	preloadBuildCmd.Flags().StringVar(&preloadContainerRuntime, "container-runtime", "docker", fmt.Sprintf("The container runtime the tarball is for (%s)", strings.Join(cruntime.ValidRuntimes(), ", ")))
+	preloadBuildCmd.Flags().StringVar(&preloadKubernetesVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The Kubernetes version the tarball is for")
+	preloadBuildCmd.Flags().StringSliceVar(&preloadImages, "images", nil, "Images to preload in addition to the Kubernetes ones")
+	preloadBuildCmd.Flags().StringSliceVar(&preloadAddons, "addons", nil, "Addons whose images are preloaded in addition to the Kubernetes ones")
+	preloadCmd.AddCommand(preloadBuildCmd)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var (
+	registryUsername      string
+	registryPassword      string
+	registryPasswordStdin bool
+)
+
+// registryCmd represents the set of registry subcommands
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Manage the credentials of private registries",
+	Long:  "Manage the credentials the nodes of the cluster use to pull from private registries",
+	Run: func(cmd *cobra.Command, args []string) {
+		exit.Message(reason.Usage, "Usage: minikube registry [login]")
+	},
+}
+
+// registryLoginCmd represents the registry login command
+var registryLoginCmd = &cobra.Command{
+	Use:   "login SERVER",
+	Short: "Log the nodes of the cluster in to a private registry",
+	Long: `Stores the credentials of a private registry in the profile, and writes them into the container runtime configuration of every node, including nodes added later.
+Images of static pods, DaemonSets in any namespace and cached images can then be pulled from the registry without imagePullSecrets.`,
+	Example: "minikube registry login registry.example.com --username user --password-stdin < password.txt",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube registry login SERVER --username USERNAME --password PASSWORD")
+		}
+		if registryUsername == "" {
+			exit.Message(reason.Usage, "Please provide the registry username via --username")
+		}
+		password := registryPassword
+		if registryPasswordStdin {
+			if password != "" {
+				exit.Message(reason.Usage, "--password and --password-stdin are mutually exclusive")
+			}
+			b, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				exit.Error(reason.Usage, "Unable to read the password from stdin", err)
+			}
+			password = strings.TrimRight(string(b), "\r\n")
+		}
+		if password == "" {
+			exit.Message(reason.Usage, "Please provide the registry password via --password or --password-stdin")
+		}
+
+		cname := ClusterFlagValue()
+		mustload.Partial(cname)
+		auth := config.RegistryAuth{Server: args[0], Username: registryUsername, Password: password}
+		if auth.Host() == "" {
+			exit.Message(reason.Usage, "Sorry, the registry server is invalid: {{.server}}", out.V{"server": args[0]})
+		}
+		if err := config.SaveRegistryAuth(cname, auth); err != nil {
+			exit.Error(reason.HostSaveProfile, "Failed to save the registry credentials", err)
+		}
+
+		auths, err := config.LoadRegistryAuths(cname)
+		if err != nil {
+			exit.Error(reason.HostConfigLoad, "Failed to load the registry credentials", err)
+		}
+		profile, err := config.LoadProfile(cname)
+		if err != nil {
+			exit.Error(reason.Usage, "loading profile", err)
+		}
+		if err := machine.ConfigureRegistryAuth(profile, auths); err != nil {
+			exit.Error(reason.GuestRegistryAuth, "Failed to configure the registry credentials", err)
+		}
+		out.Step(style.Check, "Logged in to {{.server}}", out.V{"server": auth.Host()})
+	},
+}
+
+// useRegistryAuths makes the registry credentials of the profile available to the images pulled on the host
+func useRegistryAuths() {
+	auths, err := config.LoadRegistryAuths(ClusterFlagValue())
+	if err != nil {
+		klog.Warningf("unable to load registry credentials: %v", err)
+		return
+	}
+	image.UseRegistryAuths(auths)
+}
+
+func init() {
+	registryLoginCmd.Flags().StringVarP(&registryUsername, "username", "u", "", "Username of the registry")
+	registryLoginCmd.Flags().StringVar(&registryPassword, "password", "", "Password of the registry")
+	registryLoginCmd.Flags().BoolVar(&registryPasswordStdin, "password-stdin", false, "Read the password of the registry from stdin")
+	registryCmd.AddCommand(registryLoginCmd)
+}
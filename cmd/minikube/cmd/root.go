@@ -33,6 +33,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/reason"
@@ -170,6 +171,7 @@ func init() {
 
 	RootCmd.PersistentFlags().StringP(config.ProfileName, "p", constants.DefaultClusterName, `The name of the minikube VM being used. This can be set to allow having multiple instances of minikube independently.`)
 	RootCmd.PersistentFlags().StringP(configCmd.Bootstrapper, "b", "kubeadm", "The name of the cluster bootstrapper that will set up the Kubernetes cluster.")
+	RootCmd.PersistentFlags().StringSlice(config.ArtifactMirror, nil, "Mirrors to download the ISO, preload, Kubernetes binaries and drivers from, in order, before the upstream locations. Each is an http(s):// or file:// URL serving the artifacts under their upstream paths.")
 
 	groups := templates.CommandGroups{
 		{
@@ -190,6 +192,8 @@ func init() {
 				dockerEnvCmd,
 				podmanEnvCmd,
 				cacheCmd,
+				imageCmd,
+				preloadCmd,
 			},
 		},
 		{
@@ -199,6 +203,8 @@ func init() {
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
 				updateContextCmd,
+				certsCmd,
+				kubeconfigCmd,
 			},
 		},
 		{
@@ -213,8 +219,12 @@ func init() {
 			Commands: []*cobra.Command{
 				mountCmd,
 				sshCmd,
+				cpCmd,
 				kubectlCmd,
 				nodeCmd,
+				snapshotCmd,
+				registryCmd,
+				bundleCmd,
 			},
 		},
 		{
@@ -224,6 +234,7 @@ func init() {
 				sshHostCmd,
 				ipCmd,
 				logsCmd,
+				doctorCmd,
 				updateCheckCmd,
 				versionCmd,
 				optionsCmd,
@@ -273,6 +284,8 @@ func setupViper() {
 	viper.SetDefault(config.WantNoneDriverWarning, true)
 	viper.SetDefault(config.ShowDriverDeprecationNotification, true)
 	viper.SetDefault(config.ShowBootstrapperDeprecationNotification, true)
+
+	download.SetMirrors(viper.GetStringSlice(config.ArtifactMirror))
 }
 
 func addToPath(dir string) {
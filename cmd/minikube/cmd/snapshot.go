@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var snapshotOutput string
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save, restore and delete snapshots of a cluster",
+	Long:  "Save and restore named snapshots of a cluster: an etcd snapshot plus the filesystem of every node. Supported by the docker, podman and kvm2 drivers.",
+}
+
+// saveSnapshotCmd represents the snapshot save command
+var saveSnapshotCmd = &cobra.Command{
+	Use:     "save NAME",
+	Short:   "Save a snapshot of a running cluster",
+	Long:    "Save a named snapshot of a running cluster. Kubernetes is paused on every node while the snapshot is taken, so that etcd and the nodes are captured at the same point in time.",
+	Example: "minikube snapshot save seeded",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide a name for the snapshot via <minikube snapshot save NAME>")
+		}
+		name := args[0]
+		co := mustload.Running(ClusterFlagValue())
+		if !snapshot.Supported(co.Config.Driver) {
+			exit.Message(reason.DrvUnsupportedProfile, "The {{.driver}} driver does not support snapshots", out.V{"driver": co.Config.Driver})
+		}
+
+		out.Step(style.Waiting, "Saving snapshot {{.name}} of {{.profile}} ...", out.V{"name": name, "profile": co.Config.Name})
+		s, err := snapshot.Save(co.API, co.Config, name)
+		if err != nil {
+			exit.Error(reason.GuestSnapshotSave, "Failed to save snapshot", err)
+		}
+		out.Step(style.Check, "Saved snapshot {{.name}} ({{.size}})", out.V{"name": s.Name, "size": units.HumanSize(float64(s.Size))})
+	},
+}
+
+// restoreSnapshotCmd represents the snapshot restore command
+var restoreSnapshotCmd = &cobra.Command{
+	Use:     "restore NAME",
+	Short:   "Restore a cluster to a snapshot",
+	Long:    "Restore a cluster to the state it was in when a snapshot was saved. Any changes made since are lost.",
+	Example: "minikube snapshot restore seeded",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide the name of the snapshot via <minikube snapshot restore NAME>")
+		}
+		name := args[0]
+		cname := ClusterFlagValue()
+		api, cc := mustload.Partial(cname)
+		if !snapshot.Supported(cc.Driver) {
+			exit.Message(reason.DrvUnsupportedProfile, "The {{.driver}} driver does not support snapshots", out.V{"driver": cc.Driver})
+		}
+
+		out.Step(style.Waiting, "Restoring {{.profile}} to snapshot {{.name}} ...", out.V{"name": name, "profile": cname})
+		if err := snapshot.Restore(api, cc, name); err != nil {
+			exit.Error(reason.GuestSnapshotRestore, "Failed to restore snapshot", err)
+		}
+
+		// Container drivers publish the API server on a new host port when the node is recreated
+		co := mustload.Running(cname)
+		if _, err := kubeconfig.UpdateEndpoint(cname, co.CP.Hostname, co.CP.Port, kubeconfig.PathFor(cname), bootstrapper.ClusterCACert(cc.KubernetesConfig), kubeconfig.NewExtension()); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "update config", err)
+		}
+		out.Step(style.Check, "Restored {{.profile}} to snapshot {{.name}}", out.V{"name": name, "profile": cname})
+	},
+}
+
+// deleteSnapshotCmd represents the snapshot delete command
+var deleteSnapshotCmd = &cobra.Command{
+	Use:     "delete NAME",
+	Short:   "Delete a snapshot",
+	Long:    "Delete a snapshot, along with the container images or libvirt snapshots it uses",
+	Example: "minikube snapshot delete seeded",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide the name of the snapshot via <minikube snapshot delete NAME>")
+		}
+		name := args[0]
+		_, cc := mustload.Partial(ClusterFlagValue())
+		if err := snapshot.Delete(cc, name); err != nil {
+			exit.Error(reason.GuestSnapshotDelete, "Failed to delete snapshot", err)
+		}
+		out.Step(style.Deleted, "Deleted snapshot {{.name}}", out.V{"name": name})
+	},
+}
+
+// listSnapshotCmd represents the snapshot list command
+var listSnapshotCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the snapshots of a cluster",
+	Long:    "List the snapshots of a cluster, with their size and creation time",
+	Example: "minikube snapshot list",
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cname := ClusterFlagValue()
+		snapshots, err := snapshot.List(cname)
+		if err != nil {
+			exit.Error(reason.GuestSnapshotList, "Failed to list snapshots", err)
+		}
+
+		switch strings.ToLower(snapshotOutput) {
+		case "json":
+			if snapshots == nil {
+				snapshots = []*snapshot.Snapshot{}
+			}
+			data, err := json.Marshal(snapshots)
+			if err != nil {
+				exit.Error(reason.InternalJSONMarshal, "snapshot json failure", err)
+			}
+			out.Ln(string(data))
+		case "table":
+			if len(snapshots) == 0 {
+				out.Step(style.Empty, `No snapshots of "{{.profile}}" were found. You can create one using "{{.cmd}}".`, out.V{"profile": cname, "cmd": mustload.ExampleCmd(cname, "snapshot save NAME")})
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Name", "Created", "Size", "Version"})
+			table.SetAutoFormatHeaders(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			for _, s := range snapshots {
+				table.Append([]string{s.Name, s.Created.Format(time.RFC3339), units.HumanSize(float64(s.Size)), s.KubernetesVersion})
+			}
+			table.Render()
+		default:
+			exit.Message(reason.Usage, fmt.Sprintf("invalid output format: %s. Valid values: 'table', 'json'", snapshotOutput))
+		}
+	},
+}
+
+func init() {
+	listSnapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+
+	snapshotCmd.AddCommand(saveSnapshotCmd)
+	snapshotCmd.AddCommand(restoreSnapshotCmd)
+	snapshotCmd.AddCommand(deleteSnapshotCmd)
+	snapshotCmd.AddCommand(listSnapshotCmd)
+}
@@ -28,6 +28,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/docker/machine/libmachine/ssh"
@@ -129,7 +130,19 @@ func platform() string {
 
 // runStart handles the executes the flow of "minikube start"
 func runStart(cmd *cobra.Command, args []string) {
-	register.SetEventLogPath(localpath.EventLog(ClusterFlagValue()))
+	if clusterSpecFile != "" {
+		if err := applyClusterSpec(cmd, clusterSpecFile); err != nil {
+			exit.Message(reason.Usage, "Invalid cluster definition {{.file}}: {{.error}}", out.V{"file": clusterSpecFile, "error": err})
+		}
+	}
+	if bundleFile != "" {
+		if err := applyBundle(cmd, bundleFile); err != nil {
+			exit.Error(reason.HostBundle, "Failed to use the offline bundle", err)
+		}
+	}
+	if !viper.GetBool(dryRun) {
+		register.SetEventLogPath(localpath.EventLog(ClusterFlagValue()))
+	}
 
 	out.SetJSON(outputFormat == "json")
 	if err := pkgtrace.Initialize(viper.GetString(trace)); err != nil {
@@ -139,7 +152,7 @@ func runStart(cmd *cobra.Command, args []string) {
 	displayVersion(version.GetVersion())
 
 	// No need to do the update check if no one is going to see it
-	if !viper.GetBool(interactive) || !viper.GetBool(dryRun) {
+	if (!viper.GetBool(interactive) || !viper.GetBool(dryRun)) && !download.IsOffline() {
 		// Avoid blocking execution on optional HTTP fetches
 		go notify.MaybePrintUpdateTextFromGithub()
 	}
@@ -217,9 +230,11 @@ func runStart(cmd *cobra.Command, args []string) {
 					klog.Warningf("%s profile does not exist, trying anyways.", ClusterFlagValue())
 				}
 
-				err = deleteProfile(profile)
-				if err != nil {
-					out.WarningT("Failed to delete cluster {{.name}}, proceeding with retry anyway.", out.V{"name": ClusterFlagValue()})
+				if !viper.GetBool(dryRun) {
+					err = deleteProfile(profile)
+					if err != nil {
+						out.WarningT("Failed to delete cluster {{.name}}, proceeding with retry anyway.", out.V{"name": ClusterFlagValue()})
+					}
 				}
 				starter, err = provisionWithDriver(cmd, ds, existing)
 				if err != nil {
@@ -236,6 +251,11 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if viper.GetBool(dryRun) {
+		showDryRun(dryRunPlanFor(ds, alts, specified, existing, starter))
+		return
+	}
+
 	if existing != nil && driver.IsKIC(existing.Driver) {
 		if viper.GetBool(createMount) {
 			old := ""
@@ -255,7 +275,8 @@ func runStart(cmd *cobra.Command, args []string) {
 			// Stop and start again if it's crio because it's broken above v1.17.3
 			out.WarningT("Due to issues with CRI-O post v1.17.3, we need to restart your cluster.")
 			out.WarningT("See details at https://github.com/kubernetes/minikube/issues/8861")
-			stopProfile(existing.Name)
+			// no stop reason, as the cluster is started again right away
+			stopProfile(existing.Name, "")
 			starter, err = provisionWithDriver(cmd, ds, existing)
 			if err != nil {
 				exitGuestProvision(err)
@@ -302,8 +323,10 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 
 	// This is about as far as we can go without overwriting config files
 	if viper.GetBool(dryRun) {
-		out.Step(style.DryRun, `dry-run validation complete!`)
-		os.Exit(0)
+		if driver.IsVM(driverName) && !driver.IsSSH(driverName) {
+			cc.MinikubeISO = plannedISO(viper.GetStringSlice(isoURL))
+		}
+		return node.Starter{Cfg: &cc, Node: &n}, nil
 	}
 
 	if driver.IsVM(driverName) && !driver.IsSSH(driverName) {
@@ -333,6 +356,21 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 		return node.Starter{}, err
 	}
 
+	// The virtual IP is taken from the network of the primary control plane, so it is only known once the node has an IP
+	if viper.GetBool(ha) && existing == nil {
+		vip, err := bsutil.HAVIP(n.IP, viper.GetString(haVirtualIP))
+		if err != nil {
+			return node.Starter{}, errors.Wrap(err, "choosing a virtual IP")
+		}
+		if bsutil.AddressInUse(mRunner, vip) {
+			exit.Message(reason.Usage, "The virtual IP {{.vip}} is already in use, choose an unused address of the network of the nodes with --ha-virtual-ip", out.V{"vip": vip})
+		}
+		cc.KubernetesConfig.APIServerHAVIP = vip
+		if err := config.SaveProfile(cc.Name, &cc); err != nil {
+			return node.Starter{}, errors.Wrap(err, "Failed to save config")
+		}
+	}
+
 	return node.Starter{
 		Runner:         mRunner,
 		PreExists:      preExists,
@@ -345,6 +383,14 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 }
 
 func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.ClusterConfig) (*kubeconfig.Settings, error) {
+	if existing != nil && config.IsHA(*existing) {
+		if err := node.StartSecondaryControlPlanes(starter.Cfg, viper.GetBool(deleteOnFailure)); err != nil {
+			return nil, errors.Wrap(err, "starting control planes")
+		}
+	} else if existing != nil && viper.GetBool(ha) {
+		out.WarningT("The cluster {{.cluster}} already exists without a highly available control plane, so the --ha parameter will be ignored.", out.V{"cluster": existing.Name})
+	}
+
 	kubeconfig, err := node.Start(starter, true)
 	if err != nil {
 		kubeconfig, err = maybeDeleteAndRetry(cmd, *starter.Cfg, *starter.Node, starter.ExistingAddons, err)
@@ -364,38 +410,71 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 	if numNodes > 1 {
 		if driver.BareMetal(starter.Cfg.Driver) {
 			exit.Message(reason.DrvUnsupportedMulti, "The none driver is not compatible with multi-node clusters.")
-		} else {
-			if existing == nil {
-				for i := 1; i < numNodes; i++ {
-					nodeName := node.Name(i + 1)
-					n := config.Node{
-						Name:              nodeName,
-						Worker:            true,
-						ControlPlane:      false,
-						KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
-					}
-					out.Ln("") // extra newline for clarity on the command line
-					err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
-					if err != nil {
-						return nil, errors.Wrap(err, "adding node")
-					}
-				}
-			} else {
-				for _, n := range existing.Nodes {
-					if !n.ControlPlane {
-						err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
-						if err != nil {
-							return nil, errors.Wrap(err, "adding node")
-						}
-					}
+		}
+
+		var nodes []config.Node
+		if existing != nil {
+			// The secondary control planes of a highly available cluster are started again too: those which were
+			// not restarted early still have to be upgraded or to finish joining, and the rest are only checked
+			for _, n := range existing.Nodes {
+				if !n.ControlPlane || config.IsHA(*existing) {
+					nodes = append(nodes, n)
 				}
 			}
+		} else {
+			cps := config.HAControlPlanes
+			if specControlPlanes > 1 {
+				cps = specControlPlanes
+			}
+			for i := 1; i < numNodes; i++ {
+				nodes = append(nodes, config.Node{
+					Name:              node.Name(i + 1),
+					Worker:            true,
+					ControlPlane:      config.IsHA(*starter.Cfg) && i < cps,
+					KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
+				})
+			}
+		}
+		if err := startNodes(starter.Cfg, nodes); err != nil {
+			return nil, err
 		}
 	}
 
 	return kubeconfig, nil
 }
 
+// startNodes adds or restarts the nodes of a cluster other than its primary control plane.
+// Control planes join one at a time, while workers are started --parallelism at a time.
+func startNodes(cc *config.ClusterConfig, nodes []config.Node) error {
+	var workers []config.Node
+	for _, n := range nodes {
+		if config.IsPrimaryControlPlane(*cc, n) {
+			continue
+		}
+		if !n.ControlPlane {
+			workers = append(workers, n)
+			continue
+		}
+		out.Ln("") // extra newline for clarity on the command line
+		if err := node.Add(cc, n, viper.GetBool(deleteOnFailure)); err != nil {
+			return errors.Wrap(err, "adding node")
+		}
+	}
+	return addWorkers(cc, workers, viper.GetInt(parallelism), viper.GetBool(deleteOnFailure))
+}
+
+// addWorkers starts worker nodes in parallel, each of which reports its own failure
+func addWorkers(cc *config.ClusterConfig, nodes []config.Node, parallel int, delOnFail bool) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	out.Ln("") // extra newline for clarity on the command line
+	if errs := node.AddNodes(cc, nodes, parallel, delOnFail); len(errs) > 0 {
+		return fmt.Errorf("%d of %d nodes failed to start", len(errs), len(nodes))
+	}
+	return nil
+}
+
 func warnAboutMultiNodeCNI() {
 	out.WarningT("Cluster was created without any CNI, adding node to it might cause broken network.")
 }
@@ -435,6 +514,9 @@ func showKubectlInfo(kcs *kubeconfig.Settings, k8sVersion string, machineName st
 	// To be shown at the end, regardless of exit path
 	defer func() {
 		register.Reg.SetStep(register.Done)
+		if p := kubeconfig.PathFor(kcs.ClusterName); p != kubeconfig.PathFromEnv() {
+			out.Step(style.Kubectl, "The context of {{.name}} is in its own kubeconfig file. To use it, run: export KUBECONFIG={{.path}}", out.V{"name": kcs.ClusterName, "path": p})
+		}
 		if kcs.KeepContext {
 			out.Step(style.Kubectl, "To connect to this cluster, use:  --context={{.name}}", out.V{"name": kcs.ClusterName})
 		} else {
@@ -1039,6 +1121,20 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 
 	validateCPUCount(drvName)
 
+	if viper.GetBool(ha) {
+		if driver.BareMetal(drvName) || driver.IsSSH(drvName) {
+			exit.Message(reason.DrvUnsupportedMulti, "The {{.driver}} driver does not support highly available clusters", out.V{"driver": drvName})
+		}
+		if driver.NeedsPortForward(drvName) {
+			out.WarningT("The virtual IP of the control plane is not reachable from the host with the {{.driver}} driver: kubectl will use the port of a running control plane", out.V{"driver": drvName})
+		}
+		if viper.GetInt(nodes) < config.HAControlPlanes {
+			viper.Set(nodes, config.HAControlPlanes)
+		}
+	} else if viper.GetString(haVirtualIP) != "" {
+		exit.Message(reason.Usage, "The --ha-virtual-ip flag can only be used with --ha")
+	}
+
 	if cmd.Flags().Changed(memory) {
 		if !driver.HasResourceLimits(drvName) {
 			out.WarningT("The '{{.name}}' driver does not respect the --memory flag", out.V{"name": drvName})
@@ -1120,9 +1216,58 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 
 	validateRegistryMirror()
 	validateInsecureRegistry()
+	validateCertFlags()
+	validateAutoStopIdle(drvName)
 
 }
 
+// validateCertFlags validates that the --ca-cert and --ca-key flags name a CA which can sign certificates,
+// and the options of the certificates minikube generates
+func validateCertFlags() {
+	opts := util.CertOptions{
+		KeyAlgorithm: strings.ToLower(viper.GetString(certKeyAlgorithm)),
+		KeySize:      viper.GetInt(certKeySize),
+		Validity:     viper.GetDuration(certValidity),
+	}
+	viper.Set(certKeyAlgorithm, opts.KeyAlgorithm)
+	if err := opts.WithDefaults().Validate(); err != nil {
+		exit.Message(reason.Usage, "Invalid certificate options: {{.error}}", out.V{"error": err})
+	}
+
+	certPath, keyPath := viper.GetString(caCert), viper.GetString(caKey)
+	if certPath == "" && keyPath == "" {
+		return
+	}
+	if certPath == "" || keyPath == "" {
+		exit.Message(reason.Usage, "The --ca-cert and --ca-key flags must be set together")
+	}
+	ca, err := util.ValidateCA(certPath, keyPath)
+	if err != nil {
+		exit.Message(reason.Usage, "The CA set with --ca-cert cannot sign the certificates of the cluster: {{.error}}", out.V{"error": err})
+	}
+	if time.Now().Add(opts.Validity).After(ca.NotAfter) {
+		exit.Message(reason.Usage, "The --cert-validity of {{.validity}} outlasts the CA, which expires on {{.expiry}}", out.V{"validity": opts.Validity, "expiry": ca.NotAfter.Format(time.RFC3339)})
+	}
+}
+
+// validateAutoStopIdle validates that the idle auto-stop can stop the nodes of the cluster
+func validateAutoStopIdle(drvName string) {
+	idle := viper.GetDuration(autoStopIdle)
+	if idle == 0 {
+		return
+	}
+	if idle < time.Minute {
+		exit.Message(reason.Usage, "The --auto-stop-idle flag must be at least 1m, or 0 to disable it")
+	}
+	if driver.BareMetal(drvName) || driver.IsSSH(drvName) {
+		exit.Message(reason.Usage, "The {{.driver}} driver does not support --auto-stop-idle, as it would stop the host", out.V{"driver": drvName})
+	}
+	// Only the API server of the primary control plane is watched, so requests served by the others would go unnoticed
+	if viper.GetBool(ha) {
+		exit.Message(reason.Usage, "The --auto-stop-idle flag is not supported for highly available clusters")
+	}
+}
+
 // This function validates if the --registry-mirror
 // args match the format of http://localhost
 func validateRegistryMirror() {
@@ -1172,19 +1317,13 @@ func createNode(cc config.ClusterConfig, kubeNodeName string, existing *config.C
 	// Create the initial node, which will necessarily be a control plane
 	if existing != nil {
 		cp, err := config.PrimaryControlPlane(existing)
-		cp.KubernetesVersion = getKubernetesVersion(&cc)
 		if err != nil {
 			return cc, config.Node{}, err
 		}
 
-		// Make sure that existing nodes honor if KubernetesVersion gets specified on restart
-		// KubernetesVersion is the only attribute that the user can override in the Node object
-		nodes := []config.Node{}
-		for _, n := range existing.Nodes {
-			n.KubernetesVersion = getKubernetesVersion(&cc)
-			nodes = append(nodes, n)
-		}
-		cc.Nodes = nodes
+		// Existing nodes keep the KubernetesVersion they run: if it is older than the
+		// requested version, the node is upgraded in place and its version updated once it starts
+		cc.Nodes = append([]config.Node{}, existing.Nodes...)
 
 		return cc, cp, nil
 	}
@@ -1277,6 +1416,31 @@ func validateKubernetesVersion(old *config.ClusterConfig) {
 			out.V{"prefix": version.VersionPrefix, "new": nvs, "old": ovs, "profile": profileArg, "suggestedName": suggestedName})
 
 	}
+
+	// Nodes are upgraded in place by kubeadm, which can only move one minor version at a time
+	for _, n := range old.Nodes {
+		if n.KubernetesVersion == "" {
+			continue
+		}
+		nv, err := semver.Make(strings.TrimPrefix(n.KubernetesVersion, version.VersionPrefix))
+		if err != nil {
+			klog.Errorf("Error parsing version %q of node %s: %v", n.KubernetesVersion, n.Name, err)
+			continue
+		}
+		// Pinned nodes are not upgraded, so they only need to stay within the skew the kubelet supports
+		if n.PinnedVersion {
+			if err := bsutil.ValidateKubeletSkew(nvs, nv); err != nil {
+				exitIfNotForced(reason.KubernetesNodeSkew, "Node {{.name}} is pinned to Kubernetes v{{.old}}, which a v{{.new}} control plane does not support: {{.error}}", out.V{"name": n.Name, "old": nv, "new": nvs, "error": err})
+				break
+			}
+			continue
+		}
+		if nvs.Major == nv.Major && nvs.Minor > nv.Minor+1 {
+			next := semver.Version{Major: nv.Major, Minor: nv.Minor + 1}
+			exitIfNotForced(reason.KubernetesUpgradeSkew, "Unable to upgrade node {{.name}} from Kubernetes v{{.old}} to v{{.new}} in place: upgrade to v{{.next}} first", out.V{"name": n.Name, "old": nv, "new": nvs, "next": fmt.Sprintf("%d.%d", next.Major, next.Minor)})
+			break
+		}
+	}
 	if defaultVersion.GT(nvs) {
 		out.Step(style.New, "Kubernetes {{.new}} is now available. If you would like to upgrade, specify: --kubernetes-version={{.prefix}}{{.new}}", out.V{"prefix": version.VersionPrefix, "new": defaultVersion})
 	}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// dryRunPlan is what a start would do, as printed by --dry-run
+type dryRunPlan struct {
+	Driver       string                `json:"driver"`
+	DriverReason string                `json:"driverReason"`
+	Alternates   []string              `json:"alternates,omitempty"`
+	Config       *config.ClusterConfig `json:"config"`
+	Downloads    []node.Artifact       `json:"downloads"`
+	Steps        []string              `json:"steps"`
+}
+
+// dryRunPlanFor returns the plan of a start, from the selected driver and the generated config
+func dryRunPlanFor(ds registry.DriverState, alts []registry.DriverState, specified bool, existing *config.ClusterConfig, starter node.Starter) dryRunPlan {
+	p := dryRunPlan{
+		Driver:       ds.Name,
+		DriverReason: "automatically selected, as the healthy driver with the highest priority",
+		Config:       starter.Cfg,
+	}
+	switch {
+	case existing != nil:
+		p.DriverReason = "used by the existing profile"
+	case specified:
+		p.DriverReason = "set by the user configuration"
+	}
+	for _, a := range alts {
+		p.Alternates = append(p.Alternates, a.Name)
+	}
+
+	var err error
+	p.Downloads, err = node.Artifacts(starter.Cfg)
+	if err != nil {
+		exit.Error(reason.InternalCacheList, "Failed to list the downloads", err)
+	}
+
+	var toEnable []string
+	if viper.GetBool(installAddons) {
+		existingAddons := map[string]bool{}
+		if existing != nil && existing.Addons != nil {
+			existingAddons = existing.Addons
+		}
+		toEnable = addons.ToEnable(starter.Cfg, existingAddons, config.AddonList)
+	}
+	numNodes := viper.GetInt(nodes)
+	if existing != nil {
+		numNodes = len(existing.Nodes)
+	}
+	p.Steps, err = node.Steps(starter.Cfg, existing != nil, toEnable, numNodes)
+	if err != nil {
+		exit.Error(reason.Usage, "Failed to plan the start", err)
+	}
+	return p
+}
+
+// showDryRun prints the plan of a start as text, or as the message of an info event in JSON
+func showDryRun(p dryRunPlan) {
+	if out.JSON {
+		data, err := json.Marshal(p)
+		if err != nil {
+			exit.Error(reason.InternalJSONMarshal, "dry-run json failure", err)
+		}
+		register.PrintInfo(string(data))
+		return
+	}
+
+	reasonText := p.DriverReason
+	if len(p.Alternates) > 0 {
+		reasonText += "; other choices: " + strings.Join(p.Alternates, ", ")
+	}
+	out.Step(style.Sparkle, "Driver: {{.driver}} ({{.reason}})", out.V{"driver": p.Driver, "reason": reasonText})
+
+	if len(p.Downloads) == 0 {
+		out.Step(style.Caching, "Downloads: none")
+	} else {
+		out.Step(style.Caching, "Downloads:")
+		for _, a := range p.Downloads {
+			state := "to download"
+			if a.Cached {
+				state = "cached"
+			}
+			out.Infof("{{.kind}} {{.name}} ({{.state}})", out.V{"kind": a.Kind, "name": a.Name, "state": state})
+		}
+	}
+
+	out.Step(style.DryRun, "Steps:")
+	for _, s := range p.Steps {
+		out.Infof("{{.step}}", out.V{"step": s})
+	}
+
+	data, err := json.MarshalIndent(p.Config, "", "    ")
+	if err != nil {
+		exit.Error(reason.InternalJSONMarshal, "dry-run json failure", err)
+	}
+	out.Step(style.Documentation, "The cluster config which would be saved:")
+	out.Ln("%s", data)
+	out.Step(style.DryRun, `dry-run validation complete! Nothing was created or downloaded.`)
+}
+
+// plannedISO returns the ISO URL a start would use: the first one which is cached, or else the first one
+func plannedISO(urls []string) string {
+	for _, u := range urls {
+		if download.ISOCached(u) {
+			return u
+		}
+	}
+	if len(urls) == 0 {
+		return ""
+	}
+	return urls[0]
+}
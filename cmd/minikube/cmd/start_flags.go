@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,6 +31,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/bundle"
 	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -36,6 +39,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
@@ -116,10 +120,27 @@ const (
 	sshSSHPort              = "ssh-port"
 	defaultSSHUser          = "root"
 	defaultSSHPort          = 22
+	clusterSpec             = "config"
+	offlineBundle           = "bundle"
+	ha                      = "ha"
+	haVirtualIP             = "ha-virtual-ip"
+	parallelism             = "parallelism"
+	caCert                  = "ca-cert"
+	caKey                   = "ca-key"
+	certKeyAlgorithm        = "cert-key-algorithm"
+	certKeySize             = "cert-key-size"
+	certValidity            = "cert-validity"
+	kubeconfigPerProfile    = "kubeconfig-per-profile"
+	autoStopIdle            = "auto-stop-idle"
 )
 
 var (
-	outputFormat string
+	outputFormat    string
+	clusterSpecFile string
+	bundleFile      string
+	bundleImages    []string
+	// specControlPlanes is the number of control planes of the cluster definition the cluster is started from, if any
+	specControlPlanes int
 )
 
 // initMinikubeFlags includes commandline flags for minikube.
@@ -131,7 +152,7 @@ func initMinikubeFlags() {
 	viper.AutomaticEnv()
 	startCmd.Flags().Bool(force, false, "Force minikube to perform possibly dangerous operations")
 	startCmd.Flags().Bool(interactive, true, "Allow user prompts for more information")
-	startCmd.Flags().Bool(dryRun, false, "dry-run mode. Validates configuration, but does not mutate system state")
+	startCmd.Flags().Bool(dryRun, false, "dry-run mode. Validates configuration and prints what a start would do, without mutating system state")
 
 	startCmd.Flags().Int(cpus, 2, "Number of CPUs allocated to Kubernetes.")
 	startCmd.Flags().String(memory, "", "Amount of RAM to allocate to Kubernetes (format: <number>[<unit>], where unit = b, k, m or g).")
@@ -141,7 +162,9 @@ func initMinikubeFlags() {
 	startCmd.Flags().StringSlice(isoURL, download.DefaultISOURLs(), "Locations to fetch the minikube ISO from.")
 	startCmd.Flags().String(kicBaseImage, kic.BaseImage, "The base image to use for docker/podman drivers. Intended for local development.")
 	startCmd.Flags().Bool(keepContext, false, "This will keep the existing kubectl context and will create a minikube context.")
-	startCmd.Flags().Bool(embedCerts, false, "if true, will embed the certs in kubeconfig.")
+	startCmd.Flags().Var(new(embedCertsValue), embedCerts, "if true, will embed the certs in kubeconfig. If exec, the kubeconfig runs the minikube kubeconfig credential plugin to get the current client certificate instead.")
+	startCmd.Flags().Lookup(embedCerts).NoOptDefVal = "true"
+	startCmd.Flags().Bool(kubeconfigPerProfile, false, "If true, write the context of the cluster to a kubeconfig file in the profile directory instead of the one from KUBECONFIG. Run 'minikube kubeconfig path' to get its path.")
 	startCmd.Flags().String(containerRuntime, "docker", fmt.Sprintf("The container runtime to be used (%s).", strings.Join(cruntime.ValidRuntimes(), ", ")))
 	startCmd.Flags().Bool(createMount, false, "This will start the mount daemon and automatically mount files into minikube.")
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":/minikube-host", "The argument to pass the minikube mount command on start.")
@@ -152,16 +175,22 @@ func initMinikubeFlags() {
 	startCmd.Flags().String(cniFlag, "", "CNI plug-in to use. Valid options: auto, bridge, calico, cilium, flannel, kindnet, or path to a CNI manifest (default: auto)")
 	startCmd.Flags().StringSlice(waitComponents, kverify.DefaultWaitList, fmt.Sprintf("comma separated list of Kubernetes components to verify and wait for after starting a cluster. defaults to %q, available options: %q . other acceptable values are 'all' or 'none', 'true' and 'false'", strings.Join(kverify.DefaultWaitList, ","), strings.Join(kverify.AllComponentsList, ",")))
 	startCmd.Flags().Duration(waitTimeout, 6*time.Minute, "max time to wait per Kubernetes or host to be healthy.")
+	startCmd.Flags().Duration(autoStopIdle, 0, "Stop the cluster once its API server has served no requests from outside the cluster for this long (e.g. --auto-stop-idle=30m). 0 disables it.")
 	startCmd.Flags().Bool(nativeSSH, true, "Use native Golang SSH client (default true). Set to 'false' to use the command line 'ssh' command when accessing the docker machine. Useful for the machine drivers when they will not start with 'Waiting for SSH'.")
 	startCmd.Flags().Bool(autoUpdate, true, "If set, automatically updates drivers to the latest version. Defaults to true.")
 	startCmd.Flags().Bool(installAddons, true, "If set, install addons. Defaults to true.")
 	startCmd.Flags().IntP(nodes, "n", 1, "The number of nodes to spin up. Defaults to 1.")
+	startCmd.Flags().Int(parallelism, node.DefaultParallelism, "The maximum number of worker nodes to create, configure and join at the same time.")
+	startCmd.Flags().Bool(ha, false, "Create a highly available cluster: the first three nodes run the control plane, which is reached through a virtual IP. Not supported by the none and ssh drivers.")
+	startCmd.Flags().String(haVirtualIP, "", "The virtual IP of the control plane of a highly available cluster, which must be unused within the network of the nodes. Defaults to the last address of the /24 network of the nodes.")
 	startCmd.Flags().Bool(preload, true, "If set, download tarball of preloaded images if available to improve start time. Defaults to true.")
 	startCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 	startCmd.Flags().Bool(forceSystemd, false, "If set, force the container runtime to use sytemd as cgroup manager. Defaults to false.")
 	startCmd.Flags().StringP(network, "", "", "network to run minikube with. Only available with the docker/podman drivers. If left empty, minikube will create a new network.")
 	startCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
 	startCmd.Flags().StringP(trace, "", "", "Send trace events. Options include: [gcp]")
+	startCmd.Flags().StringVar(&clusterSpecFile, clusterSpec, "", "Path to a YAML or JSON cluster definition, as written by `minikube profile export`. Flags given on the command line take precedence over the file.")
+	startCmd.Flags().StringVar(&bundleFile, offlineBundle, "", "Path to an offline bundle, as written by `minikube bundle create`. Populates the caches from the bundle, and starts the cluster without network access.")
 }
 
 // initKubernetesFlags inits the commandline flags for Kubernetes related options
@@ -179,6 +208,11 @@ func initKubernetesFlags() {
 	startCmd.Flags().String(apiServerName, constants.APIServerName, "The authoritative apiserver hostname for apiserver certificates and connectivity. This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().StringSliceVar(&apiServerNames, "apiserver-names", nil, "A set of apiserver names which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().IPSliceVar(&apiServerIPs, "apiserver-ips", nil, "A set of apiserver IP Addresses which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().String(caCert, "", "Path to the certificate of a CA which signs the apiserver, client and proxy-client certificates of the cluster, instead of the minikubeCA. Requires --ca-key.")
+	startCmd.Flags().String(caKey, "", "Path to the private key of the CA set with --ca-cert.")
+	startCmd.Flags().String(certKeyAlgorithm, pkgutil.RSA, "Key algorithm of the certificates minikube generates for the cluster. Options include: [rsa,ecdsa]")
+	startCmd.Flags().Int(certKeySize, 0, "Key size of the certificates minikube generates: the RSA key size in bits, or the ECDSA curve size (256, 384 or 521). Defaults to 2048 for RSA and 256 for ECDSA.")
+	startCmd.Flags().Duration(certValidity, pkgutil.DefaultCertOptions.Validity, "Validity of the certificates minikube generates for the cluster.")
 }
 
 // initDriverFlags inits the commandline flags for vm drivers
@@ -220,8 +254,8 @@ func initDriverFlags() {
 
 // initNetworkingFlags inits the commandline flags for connectivity related flags for start
 func initNetworkingFlags() {
-	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
-	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
+	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure registries to pass to the container runtime. The default service CIDR range will automatically be added for the Docker daemon; networks are only supported by Docker.")
+	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors of docker.io to pass to the container runtime")
 	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to gcr.io. Set it to \"auto\" to let minikube decide one for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	startCmd.Flags().String(imageMirrorCountry, "", "Country code of the image mirror to be used. Leave empty to use the global one. For Chinese mainland users, set it to cn.")
 	startCmd.Flags().String(serviceCIDR, constants.DefaultServiceCIDR, "The CIDR to be used for service cluster IPs.")
@@ -235,6 +269,124 @@ func initNetworkingFlags() {
 	startCmd.Flags().Int(sshSSHPort, defaultSSHPort, "SSH port (ssh driver only)")
 }
 
+// applyBundle populates the caches from an offline bundle, sets the start flags from its manifest and prevents any download.
+// Flags which were set on the command line must match the bundle.
+func applyBundle(cmd *cobra.Command, file string) error {
+	read := bundle.Extract
+	if viper.GetBool(dryRun) {
+		read = bundle.ReadManifest
+	} else {
+		out.Step(style.Caching, "Extracting offline bundle {{.file}} ...", out.V{"file": file})
+	}
+	m, err := read(file)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		"driver":          m.Driver,
+		containerRuntime:  m.ContainerRuntime,
+		kubernetesVersion: m.KubernetesVersion,
+		kicBaseImage:      m.BaseImage,
+	}
+	for flag, value := range values {
+		if value == "" {
+			continue
+		}
+		if cmd.Flags().Changed(flag) {
+			if got := cmd.Flags().Lookup(flag).Value.String(); strings.TrimPrefix(got, version.VersionPrefix) != strings.TrimPrefix(value, version.VersionPrefix) {
+				return fmt.Errorf("--%s=%s does not match the bundle, which was created for %s", flag, got, value)
+			}
+			continue
+		}
+		klog.Infof("setting --%s=%s from %s", flag, value, file)
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			return errors.Wrapf(err, "setting --%s", flag)
+		}
+	}
+	if len(m.Addons) > 0 && !cmd.Flags().Changed("addons") {
+		if err := cmd.Flags().Set("addons", strings.Join(m.Addons, ",")); err != nil {
+			return errors.Wrap(err, "setting --addons")
+		}
+	}
+
+	bundleImages = m.Images
+	download.SetOffline(true)
+	return nil
+}
+
+// applyClusterSpec sets the start flags from a cluster definition file.
+// Flags which were set on the command line take precedence over the file.
+func applyClusterSpec(cmd *cobra.Command, file string) error {
+	s, err := config.LoadSpec(file)
+	if err != nil {
+		return err
+	}
+
+	type flagValue struct {
+		flag  string
+		value string
+	}
+	values := []flagValue{
+		{config.ProfileName, s.Name},
+		{"driver", s.Driver},
+		{memory, s.Memory},
+		{humanReadableDiskSize, s.DiskSize},
+		{kicBaseImage, s.BaseImage},
+		{network, s.Network},
+		{"insecure-registry", strings.Join(s.InsecureRegistries, ",")},
+		{"registry-mirror", strings.Join(s.RegistryMirrors, ",")},
+		{ports, strings.Join(s.Ports, ",")},
+		{"addons", strings.Join(s.Addons, ",")},
+		{kubernetesVersion, s.Kubernetes.Version},
+		{containerRuntime, s.Kubernetes.ContainerRuntime},
+		{cniFlag, s.Kubernetes.CNI},
+		{networkPlugin, s.Kubernetes.NetworkPlugin},
+		{featureGates, s.Kubernetes.FeatureGates},
+		{serviceCIDR, s.Kubernetes.ServiceCIDR},
+		{dnsDomain, s.Kubernetes.DNSDomain},
+		{apiServerName, s.Kubernetes.APIServerName},
+		{"apiserver-names", strings.Join(s.Kubernetes.APIServerNames, ",")},
+		{imageRepository, s.Kubernetes.ImageRepository},
+	}
+	if s.CPUs > 0 {
+		values = append(values, flagValue{cpus, strconv.Itoa(s.CPUs)})
+	}
+	if s.Kubernetes.APIServerPort > 0 {
+		values = append(values, flagValue{apiServerPort, strconv.Itoa(s.Kubernetes.APIServerPort)})
+	}
+	if len(s.Nodes) > 0 {
+		values = append(values, flagValue{nodes, strconv.Itoa(len(s.Nodes))})
+	}
+	if s.HA() {
+		values = append(values, flagValue{ha, "true"})
+		specControlPlanes = s.ControlPlanes()
+	}
+	if len(s.Mounts) > 0 {
+		values = append(values, flagValue{createMount, "true"}, flagValue{mountString, s.Mounts[0].String()})
+	}
+
+	for _, v := range values {
+		if v.value == "" || cmd.Flags().Changed(v.flag) {
+			continue
+		}
+		klog.Infof("setting --%s=%s from %s", v.flag, v.value, file)
+		if err := cmd.Flags().Set(v.flag, v.value); err != nil {
+			return errors.Wrapf(err, "setting --%s", v.flag)
+		}
+	}
+
+	// extra-config accumulates values, so each entry is set on its own
+	if !cmd.Flags().Changed("extra-config") {
+		for _, e := range s.Kubernetes.ExtraConfig {
+			if err := cmd.Flags().Set("extra-config", e); err != nil {
+				return errors.Wrap(err, "setting --extra-config")
+			}
+		}
+	}
+	return nil
+}
+
 // ClusterFlagValue returns the current cluster name based on flags
 func ClusterFlagValue() string {
 	return viper.GetString(config.ProfileName)
@@ -245,6 +397,8 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 	var cc config.ClusterConfig
 	if existing != nil {
 		cc = updateExistingConfigFromFlags(cmd, existing)
+		// the cluster is being started, so the reason it was last stopped no longer applies
+		cc.StopReason = ""
 	} else {
 		klog.Info("no existing cluster config was found, will generate one from the flags ")
 		sysLimit, containerLimit, err := memoryLimits(drvName)
@@ -314,7 +468,9 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 		cc = config.ClusterConfig{
 			Name:                    ClusterFlagValue(),
 			KeepContext:             viper.GetBool(keepContext),
-			EmbedCerts:              viper.GetBool(embedCerts),
+			EmbedCerts:              viper.GetString(embedCerts) == "true",
+			ExecCredential:          viper.GetString(embedCerts) == embedCertsExec,
+			KubeconfigPerProfile:    viper.GetBool(kubeconfigPerProfile),
 			MinikubeISO:             viper.GetString(isoURL),
 			KicBaseImage:            viper.GetString(kicBaseImage),
 			Network:                 viper.GetString(network),
@@ -369,8 +525,17 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 				ShouldLoadCachedImages: viper.GetBool(cacheImages),
 				CNI:                    chosenCNI,
 				NodePort:               viper.GetInt(apiServerPort),
+				CACertPath:             absPath(viper.GetString(caCert)),
+				CAKeyPath:              absPath(viper.GetString(caKey)),
+				CertKeyAlgorithm:       viper.GetString(certKeyAlgorithm),
+				CertKeySize:            viper.GetInt(certKeySize),
+				CertValidity:           viper.GetDuration(certValidity),
 			},
-			MultiNodeRequested: viper.GetInt(nodes) > 1,
+			MultiNodeRequested: viper.GetInt(nodes) > 1 || viper.GetBool(ha),
+			Mount:              viper.GetBool(createMount),
+			MountString:        viper.GetString(mountString),
+			OfflineImages:      bundleImages,
+			AutoStopIdle:       viper.GetDuration(autoStopIdle),
 		}
 		cc.VerifyComponents = interpretWaitFlag(*cmd)
 		if viper.GetBool(createMount) && driver.IsKIC(drvName) {
@@ -447,13 +612,25 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 	}
 
 	if cmd.Flags().Changed(embedCerts) {
-		cc.EmbedCerts = viper.GetBool(embedCerts)
+		cc.EmbedCerts = viper.GetString(embedCerts) == "true"
+		cc.ExecCredential = viper.GetString(embedCerts) == embedCertsExec
+	}
+
+	if cmd.Flags().Changed(kubeconfigPerProfile) {
+		cc.KubeconfigPerProfile = viper.GetBool(kubeconfigPerProfile)
 	}
 
 	if cmd.Flags().Changed(isoURL) {
 		cc.MinikubeISO = viper.GetString(isoURL)
 	}
 
+	if cmd.Flags().Changed(autoStopIdle) {
+		if viper.GetDuration(autoStopIdle) > 0 && config.IsHA(*existing) {
+			exit.Message(reason.Usage, "The --auto-stop-idle flag is not supported for highly available clusters")
+		}
+		cc.AutoStopIdle = viper.GetDuration(autoStopIdle)
+	}
+
 	if cc.Memory == 0 {
 		klog.Info("Existing config file was missing memory. (could be an old minikube config), will use the default value")
 		memInMB, err := pkgutil.CalculateSizeInMB(viper.GetString(memory))
@@ -529,6 +706,14 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.HypervExternalAdapter = viper.GetString(hypervExternalAdapter)
 	}
 
+	if cmd.Flags().Changed(createMount) {
+		cc.Mount = viper.GetBool(createMount)
+	}
+
+	if cmd.Flags().Changed(mountString) {
+		cc.MountString = viper.GetString(mountString)
+	}
+
 	if cmd.Flags().Changed(kvmNetwork) {
 		cc.KVMNetwork = viper.GetString(kvmNetwork)
 	}
@@ -661,9 +846,77 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		cc.KicBaseImage = viper.GetString(kicBaseImage)
 	}
 
+	if cmd.Flags().Changed(offlineBundle) {
+		cc.OfflineImages = bundleImages
+	}
+
+	if cmd.Flags().Changed(caCert) && absPath(viper.GetString(caCert)) != cc.KubernetesConfig.CACertPath ||
+		cmd.Flags().Changed(caKey) && absPath(viper.GetString(caKey)) != cc.KubernetesConfig.CAKeyPath {
+		out.WarningT("You cannot change the CA of an existing minikube cluster. Please first delete the cluster.")
+	}
+
+	if cmd.Flags().Changed(certKeyAlgorithm) {
+		cc.KubernetesConfig.CertKeyAlgorithm = viper.GetString(certKeyAlgorithm)
+	}
+
+	if cmd.Flags().Changed(certKeySize) {
+		cc.KubernetesConfig.CertKeySize = viper.GetInt(certKeySize)
+	}
+
+	if cmd.Flags().Changed(certValidity) {
+		cc.KubernetesConfig.CertValidity = viper.GetDuration(certValidity)
+	}
+
 	return cc
 }
 
+// embedCertsExec is the --embed-certs mode in which the kubeconfig runs the credential plugin
+const embedCertsExec = "exec"
+
+// embedCertsValue is the value of the --embed-certs flag: true, false or exec
+type embedCertsValue string
+
+// String returns the value of the flag
+func (e *embedCertsValue) String() string {
+	if *e == "" {
+		return "false"
+	}
+	return string(*e)
+}
+
+// Set sets the value of the flag, which is a boolean or exec
+func (e *embedCertsValue) Set(s string) error {
+	s = strings.ToLower(s)
+	if s == embedCertsExec {
+		*e = embedCertsValue(s)
+		return nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("invalid value %q, expected true, false or exec", s)
+	}
+	*e = embedCertsValue(strconv.FormatBool(b))
+	return nil
+}
+
+// Type returns the type of the flag
+func (e *embedCertsValue) Type() string {
+	return "string"
+}
+
+// absPath returns the absolute path of a file given on the command line, or "" if none is given
+func absPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		klog.Warningf("unable to get the absolute path of %s: %v", p, err)
+		return p
+	}
+	return abs
+}
+
 // interpretWaitFlag interprets the wait flag and respects the legacy minikube users
 // returns map of components to wait for
 func interpretWaitFlag(cmd cobra.Command) map[string]bool {
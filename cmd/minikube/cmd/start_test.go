@@ -310,3 +310,32 @@ func TestBaseImageFlagDriverCombo(t *testing.T) {
 		})
 	}
 }
+
+func TestEmbedCertsValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"true", "true", false},
+		{"false", "false", false},
+		{"exec", "exec", false},
+		{"EXEC", "exec", false},
+		{"1", "true", false},
+		{"F", "false", false},
+		{"yes", "false", true},
+		{"", "false", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			var e embedCertsValue
+			err := e.Set(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tc.value, err, tc.wantErr)
+			}
+			if got := e.String(); got != tc.want {
+				t.Errorf("Set(%q) = %q, want %q", tc.value, got, tc.want)
+			}
+		})
+	}
+}
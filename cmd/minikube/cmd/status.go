@@ -47,6 +47,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/schedule"
 	"k8s.io/minikube/pkg/version"
 )
 
@@ -136,6 +137,10 @@ type Status struct {
 	Kubeconfig string
 	Worker     bool
 	TimeToStop string
+	// KubernetesVersion is the version of Kubernetes the node runs
+	KubernetesVersion string
+	// StopReason is why the cluster was stopped, set on the stopped primary control plane
+	StopReason string `json:",omitempty"`
 }
 
 // ClusterState holds a cluster state representation
@@ -144,6 +149,7 @@ type ClusterState struct {
 
 	BinaryVersion string
 	TimeToStop    string
+	StopReason    string `json:",omitempty"`
 	Components    map[string]BaseState
 	Nodes         []NodeState
 }
@@ -151,7 +157,8 @@ type ClusterState struct {
 // NodeState holds a node state representation
 type NodeState struct {
 	BaseState
-	Components map[string]BaseState `json:",omitempty"`
+	KubernetesVersion string               `json:",omitempty"`
+	Components        map[string]BaseState `json:",omitempty"`
 }
 
 // BaseState holds a component state representation, such as "apiserver" or "kubeconfig"
@@ -180,15 +187,20 @@ const (
 type: Control Plane
 host: {{.Host}}
 kubelet: {{.Kubelet}}
+kubernetesVersion: {{.KubernetesVersion}}
 apiserver: {{.APIServer}}
 kubeconfig: {{.Kubeconfig}}
 timeToStop: {{.TimeToStop}}
+{{- if .StopReason}}
+stopReason: {{.StopReason}}
+{{- end}}
 
 `
 	workerStatusFormat = `{{.Name}}
 type: Worker
 host: {{.Host}}
 kubelet: {{.Kubelet}}
+kubernetesVersion: {{.KubernetesVersion}}
 
 `
 )
@@ -311,6 +323,11 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 		Kubeconfig: Nonexistent,
 		Worker:     !controlPlane,
 		TimeToStop: Nonexistent,
+		// Nodes record the version they run once started, which is behind the cluster version until they are upgraded
+		KubernetesVersion: n.KubernetesVersion,
+	}
+	if st.KubernetesVersion == "" {
+		st.KubernetesVersion = config.NodeKubernetesVersion(cc, n)
 	}
 
 	hs, err := machine.Status(api, name)
@@ -331,6 +348,9 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 		st.APIServer = st.Host
 		st.Kubelet = st.Host
 		st.Kubeconfig = st.Host
+		if st.Host == state.Stopped.String() && config.IsPrimaryControlPlane(cc, n) {
+			st.StopReason = schedule.StopReason(&cc)
+		}
 		return st, nil
 	}
 
@@ -370,9 +390,8 @@ func nodeStatus(api libmachine.API, cc config.ClusterConfig, n config.Node) (*St
 
 	stk := kverify.ServiceStatus(cr, "kubelet")
 	st.Kubelet = stk.String()
-	if cc.ScheduledStop != nil {
-		initiationTime := time.Unix(cc.ScheduledStop.InitiationTime, 0)
-		st.TimeToStop = time.Until(initiationTime.Add(cc.ScheduledStop.Duration)).String()
+	if left, ok := schedule.TimeToStop(&cc, time.Now()); ok {
+		st.TimeToStop = left.Round(time.Second).String()
 	}
 	// Early exit for worker nodes
 	if !controlPlane {
@@ -497,6 +516,7 @@ func clusterState(sts []*Status) ClusterState {
 		},
 
 		TimeToStop: sts[0].TimeToStop,
+		StopReason: sts[0].StopReason,
 
 		Components: map[string]BaseState{
 			"kubeconfig": {Name: "kubeconfig", StatusCode: statusCode(sts[0].Kubeconfig), StatusName: codeNames[statusCode(sts[0].Kubeconfig)]},
@@ -509,6 +529,7 @@ func clusterState(sts []*Status) ClusterState {
 				Name:       st.Name,
 				StatusCode: statusCode(st.Host),
 			},
+			KubernetesVersion: st.KubernetesVersion,
 			Components: map[string]BaseState{
 				"kubelet": {Name: "kubelet", StatusCode: statusCode(st.Kubelet)},
 			},
@@ -51,18 +51,23 @@ func TestStatusText(t *testing.T) {
 	}{
 		{
 			name:  "ok",
-			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, TimeToStop: "10m"},
-			want:  "minikube\ntype: Control Plane\nhost: Running\nkubelet: Running\napiserver: Running\nkubeconfig: Configured\ntimeToStop: 10m\n\n",
+			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Running", APIServer: "Running", Kubeconfig: Configured, TimeToStop: "10m", KubernetesVersion: "v1.20.0"},
+			want:  "minikube\ntype: Control Plane\nhost: Running\nkubelet: Running\nkubernetesVersion: v1.20.0\napiserver: Running\nkubeconfig: Configured\ntimeToStop: 10m\n\n",
 		},
 		{
 			name:  "paused",
-			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Stopped", APIServer: "Paused", Kubeconfig: Configured, TimeToStop: Nonexistent},
-			want:  "minikube\ntype: Control Plane\nhost: Running\nkubelet: Stopped\napiserver: Paused\nkubeconfig: Configured\ntimeToStop: Nonexistent\n\n",
+			state: &Status{Name: "minikube", Host: "Running", Kubelet: "Stopped", APIServer: "Paused", Kubeconfig: Configured, TimeToStop: Nonexistent, KubernetesVersion: "v1.20.0"},
+			want:  "minikube\ntype: Control Plane\nhost: Running\nkubelet: Stopped\nkubernetesVersion: v1.20.0\napiserver: Paused\nkubeconfig: Configured\ntimeToStop: Nonexistent\n\n",
 		},
 		{
 			name:  "down",
-			state: &Status{Name: "minikube", Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured, TimeToStop: Nonexistent},
-			want:  "minikube\ntype: Control Plane\nhost: Stopped\nkubelet: Stopped\napiserver: Stopped\nkubeconfig: Misconfigured\ntimeToStop: Nonexistent\n\n\nWARNING: Your kubectl is pointing to stale minikube-vm.\nTo fix the kubectl context, run `minikube update-context`\n",
+			state: &Status{Name: "minikube", Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: Misconfigured, TimeToStop: Nonexistent, KubernetesVersion: "v1.19.4"},
+			want:  "minikube\ntype: Control Plane\nhost: Stopped\nkubelet: Stopped\nkubernetesVersion: v1.19.4\napiserver: Stopped\nkubeconfig: Misconfigured\ntimeToStop: Nonexistent\n\n\nWARNING: Your kubectl is pointing to stale minikube-vm.\nTo fix the kubectl context, run `minikube update-context`\n",
+		},
+		{
+			name:  "idle",
+			state: &Status{Name: "minikube", Host: "Stopped", Kubelet: "Stopped", APIServer: "Stopped", Kubeconfig: "Stopped", TimeToStop: Nonexistent, KubernetesVersion: "v1.20.0", StopReason: "the API server was idle for 30m0s"},
+			want:  "minikube\ntype: Control Plane\nhost: Stopped\nkubelet: Stopped\nkubernetesVersion: v1.20.0\napiserver: Stopped\nkubeconfig: Stopped\ntimeToStop: Nonexistent\nstopReason: the API server was idle for 30m0s\n\n",
 		},
 	}
 	for _, tc := range tests {
@@ -19,6 +19,7 @@ package cmd
 import (
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/docker/machine/libmachine"
@@ -44,7 +45,7 @@ import (
 var (
 	stopAll               bool
 	keepActive            bool
-	scheduledStopDuration time.Duration
+	scheduledStopDuration scheduleValue
 	cancelScheduledStop   bool
 )
 
@@ -59,12 +60,8 @@ var stopCmd = &cobra.Command{
 func init() {
 	stopCmd.Flags().BoolVar(&stopAll, "all", false, "Set flag to stop all profiles (clusters)")
 	stopCmd.Flags().BoolVar(&keepActive, "keep-context-active", false, "keep the kube-context active after cluster is stopped. Defaults to false.")
-	stopCmd.Flags().DurationVar(&scheduledStopDuration, "schedule", 0*time.Second, "Set flag to stop cluster after a set amount of time (e.g. --schedule=5m)")
+	stopCmd.Flags().Var(&scheduledStopDuration, "schedule", "Set flag to stop cluster after a set amount of time (e.g. --schedule=5m). Prefix the duration with + or - to extend or shorten an existing scheduled stop (e.g. --schedule=+10m)")
 	stopCmd.Flags().BoolVar(&cancelScheduledStop, "cancel-scheduled", false, "cancel any existing scheduled stop requests")
-
-	if err := stopCmd.Flags().MarkHidden("schedule"); err != nil {
-		klog.Info("unable to mark --schedule flag as hidden")
-	}
 	stopCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
 
 	if err := viper.GetViper().BindPFlags(stopCmd.Flags()); err != nil {
@@ -97,29 +94,42 @@ func runStop(cmd *cobra.Command, args []string) {
 		profilesToStop = append(profilesToStop, cname)
 	}
 
+	duration := scheduledStopDuration.d
+	if scheduledStopDuration.relative {
+		duration = rescheduledStop(profilesToStop, duration)
+	}
+
 	// Kill any existing scheduled stops
 	schedule.KillExisting(profilesToStop)
 	if cancelScheduledStop {
+		if err := schedule.Clear(profilesToStop); err != nil {
+			klog.Warningf("unable to clear scheduled stop: %v", err)
+		}
 		register.Reg.SetStep(register.Done)
 		out.Step(style.Stopped, `All existing scheduled stops cancelled`)
 		return
 	}
 
-	if scheduledStopDuration != 0 {
-		if err := schedule.Daemonize(profilesToStop, scheduledStopDuration); err != nil {
+	if duration != 0 {
+		if err := schedule.Daemonize(profilesToStop, duration); err != nil {
 			exit.Message(reason.DaemonizeError, "unable to daemonize: {{.err}}", out.V{"err": err.Error()})
 		}
 		// if OS is windows, scheduled stop is now being handled within minikube, so return
 		if runtime.GOOS == "windows" {
 			return
 		}
-		klog.Infof("sleeping %s before completing stop...", scheduledStopDuration.String())
-		time.Sleep(scheduledStopDuration)
+		klog.Infof("sleeping %s before completing stop...", duration.String())
+		time.Sleep(duration)
+	}
+
+	stopReason := schedule.StoppedByUser
+	if duration != 0 {
+		stopReason = schedule.StoppedBySchedule
 	}
 
 	stoppedNodes := 0
 	for _, profile := range profilesToStop {
-		stoppedNodes = stopProfile(profile)
+		stoppedNodes = stopProfile(profile, stopReason)
 	}
 
 	register.Reg.SetStep(register.Done)
@@ -128,7 +138,7 @@ func runStop(cmd *cobra.Command, args []string) {
 	}
 }
 
-func stopProfile(profile string) int {
+func stopProfile(profile string, stopReason string) int {
 	stoppedNodes := 0
 	register.Reg.SetStep(register.Stopping)
 
@@ -150,14 +160,69 @@ func stopProfile(profile string) int {
 	}
 
 	if !keepActive {
-		if err := kubeconfig.DeleteContext(profile, kubeconfig.PathFromEnv()); err != nil {
+		if err := kubeconfig.DeleteContext(profile, kubeconfig.PathFor(profile)); err != nil {
 			exit.Error(reason.HostKubeconfigDeleteCtx, "delete ctx", err)
 		}
 	}
 
+	if err := schedule.Stopped(profile, stopReason); err != nil {
+		klog.Warningf("unable to record the stop of %s: %v", profile, err)
+	}
+
 	return stoppedNodes
 }
 
+// rescheduledStop returns the new duration of a scheduled stop which is extended or shortened by change
+func rescheduledStop(profiles []string, change time.Duration) time.Duration {
+	if len(profiles) != 1 {
+		exit.Message(reason.Usage, "A scheduled stop can only be extended or shortened for a single profile")
+	}
+	_, cc := mustload.Partial(profiles[0])
+	left, ok := schedule.TimeToStop(cc, time.Now())
+	if !ok {
+		exit.Message(reason.Usage, "There is no scheduled stop for {{.profile}} to change, use --schedule without + or - to schedule one", out.V{"profile": profiles[0]})
+	}
+	duration := left + change
+	if duration <= 0 {
+		exit.Message(reason.Usage, "The scheduled stop for {{.profile}} is due in {{.left}}, it cannot be shortened by {{.change}}", out.V{"profile": profiles[0], "left": left.Round(time.Second), "change": -change})
+	}
+	return duration
+}
+
+// scheduleValue is the value of the --schedule flag, a duration which may be relative to an existing scheduled stop
+type scheduleValue struct {
+	d        time.Duration
+	relative bool
+}
+
+// String returns the value of the flag
+func (v *scheduleValue) String() string {
+	if v.d == 0 && !v.relative {
+		// no stop is scheduled, and "0" keeps the default out of the help text
+		return "0"
+	}
+	if v.relative && v.d >= 0 {
+		return "+" + v.d.String()
+	}
+	return v.d.String()
+}
+
+// Set sets the value of the flag, a duration optionally prefixed with + or -
+func (v *scheduleValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	v.d = d
+	v.relative = strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-")
+	return nil
+}
+
+// Type returns the type of the flag
+func (v *scheduleValue) Type() string {
+	return "duration"
+}
+
 func stop(api libmachine.API, machineName string) bool {
 	nonexistent := false
 
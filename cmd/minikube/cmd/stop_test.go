@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		want     time.Duration
+		relative bool
+		str      string
+	}{
+		{"5m", 5 * time.Minute, false, "5m0s"},
+		{"+10m", 10 * time.Minute, true, "+10m0s"},
+		{"-30s", -30 * time.Second, true, "-30s"},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			var v scheduleValue
+			if err := v.Set(test.value); err != nil {
+				t.Fatalf("Set(%q): %v", test.value, err)
+			}
+			if v.d != test.want || v.relative != test.relative {
+				t.Errorf("Set(%q) = %v, relative %v; want %v, relative %v", test.value, v.d, v.relative, test.want, test.relative)
+			}
+			if v.String() != test.str {
+				t.Errorf("String() = %q; want %q", v.String(), test.str)
+			}
+		})
+	}
+
+	var v scheduleValue
+	if err := v.Set("soon"); err == nil {
+		t.Errorf("Set(%q) did not return an error", "soon")
+	}
+}
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/mustload"
@@ -26,18 +28,26 @@ import (
 	"k8s.io/minikube/pkg/minikube/style"
 )
 
+var pruneContexts bool
+
 // updateContextCmd represents the update-context command
 var updateContextCmd = &cobra.Command{
 	Use:   "update-context",
 	Short: "Update kubeconfig in case of an IP or port change",
 	Long: `Retrieves the IP address of the running cluster, checks it
-			with IP in kubeconfig, and corrects kubeconfig if incorrect.`,
+			with IP in kubeconfig, and corrects kubeconfig if incorrect.
+			With --prune, removes the contexts, clusters and users minikube added to the kubeconfig for profiles which no longer exist instead.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if pruneContexts {
+			pruneKubeconfig()
+			return
+		}
+
 		cname := ClusterFlagValue()
 		co := mustload.Running(cname)
 		//	cluster extension metada for kubeconfig
 
-		updated, err := kubeconfig.UpdateEndpoint(cname, co.CP.Hostname, co.CP.Port, kubeconfig.PathFromEnv(), kubeconfig.NewExtension())
+		updated, err := kubeconfig.UpdateEndpoint(cname, co.CP.Hostname, co.CP.Port, kubeconfig.PathFor(cname), bootstrapper.ClusterCACert(co.Config.KubernetesConfig), kubeconfig.NewExtension())
 		if err != nil {
 			exit.Error(reason.HostKubeconfigUpdate, "update config", err)
 		}
@@ -47,10 +57,30 @@ var updateContextCmd = &cobra.Command{
 			out.Step(style.Meh, `No changes required for the "{{.context}}" context`, out.V{"context": cname})
 		}
 
-		if err := kubeconfig.SetCurrentContext(cname, kubeconfig.PathFromEnv()); err != nil {
+		if err := kubeconfig.SetCurrentContext(cname, kubeconfig.PathFor(cname)); err != nil {
 			out.ErrT(style.Sad, `Error while setting kubectl current context:  {{.error}}`, out.V{"error": err})
 		} else {
 			out.Step(style.Kubectl, `Current context is "{{.context}}"`, out.V{"context": cname})
 		}
 	},
 }
+
+// pruneKubeconfig removes the entries of deleted profiles from the kubeconfig
+func pruneKubeconfig() {
+	path := kubeconfig.PathFromEnv()
+	pruned, err := kubeconfig.Prune(func(profile string) bool { return config.ProfileExists(profile) }, path)
+	if err != nil {
+		exit.Error(reason.HostKubeconfigUpdate, "prune config", err)
+	}
+	if len(pruned) == 0 {
+		out.Step(style.Meh, "No entries of deleted profiles were found in {{.path}}", out.V{"path": path})
+		return
+	}
+	for _, name := range pruned {
+		out.Step(style.Deleted, `Removed "{{.name}}" from {{.path}}`, out.V{"name": name, "path": path})
+	}
+}
+
+func init() {
+	updateContextCmd.Flags().BoolVar(&pruneContexts, "prune", false, "If true, remove the contexts, clusters and users of profiles which no longer exist from the kubeconfig, instead of updating the context of the profile")
+}
@@ -367,23 +367,21 @@ func verifyAddonStatusInternal(cc *config.ClusterConfig, name string, val string
 	return nil
 }
 
-// Start enables the default addons for a profile, plus any additional
-func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]bool, additional []string) {
-	defer wg.Done()
-
-	start := time.Now()
-	klog.Infof("enableAddons start: toEnable=%v, additional=%s", toEnable, additional)
-	defer func() {
-		klog.Infof("enableAddons completed in %s", time.Since(start))
-	}()
+// ToEnable returns the sorted names of the addons a start enables: those enabled in toEnable,
+// the ones enabled by default which toEnable doesn't mention, and the additional ones
+func ToEnable(cc *config.ClusterConfig, toEnable map[string]bool, additional []string) []string {
+	enable := map[string]bool{}
+	for name, v := range toEnable {
+		enable[name] = v
+	}
 
 	// Get the default values of any addons not saved to our config
 	for name, a := range assets.Addons {
 		defaultVal := a.IsEnabled(cc)
 
-		_, exists := toEnable[name]
+		_, exists := enable[name]
 		if !exists {
-			toEnable[name] = defaultVal
+			enable[name] = defaultVal
 		}
 	}
 
@@ -396,17 +394,31 @@ func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]boo
 		// if the specified addon doesn't exist, skip enabling
 		_, e := isAddonValid(name)
 		if e {
-			toEnable[name] = true
+			enable[name] = true
 		}
 	}
 
 	toEnableList := []string{}
-	for k, v := range toEnable {
+	for k, v := range enable {
 		if v {
 			toEnableList = append(toEnableList, k)
 		}
 	}
 	sort.Strings(toEnableList)
+	return toEnableList
+}
+
+// Start enables the default addons for a profile, plus any additional
+func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]bool, additional []string) {
+	defer wg.Done()
+
+	start := time.Now()
+	klog.Infof("enableAddons start: toEnable=%v, additional=%s", toEnable, additional)
+	defer func() {
+		klog.Infof("enableAddons completed in %s", time.Since(start))
+	}()
+
+	toEnableList := ToEnable(cc, toEnable, additional)
 
 	var awg sync.WaitGroup
 
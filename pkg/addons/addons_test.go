@@ -23,6 +23,8 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/localpath"
@@ -150,3 +152,17 @@ func TestStart(t *testing.T) {
 		t.Errorf("expected dashboard to be enabled")
 	}
 }
+
+func TestToEnable(t *testing.T) {
+	cc := &config.ClusterConfig{Name: "toenable"}
+	toEnable := map[string]bool{"storage-provisioner": false, "ingress": true}
+
+	got := ToEnable(cc, toEnable, []string{"heapster", "unknown-addon"})
+	want := []string{"default-storageclass", "ingress", "metrics-server"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ToEnable() mismatch (-want +got):\n%s", diff)
+	}
+	if len(toEnable) != 2 {
+		t.Errorf("ToEnable() modified its argument: %v", toEnable)
+	}
+}
@@ -72,6 +72,70 @@ func NewDriver(c Config) *Driver {
 
 // Create a host using the driver's config
 func (d *Driver) Create() error {
+	params := d.containerParams()
+
+	exists, err := oci.ContainerExists(d.OCIBinary, params.Name, true)
+	if err != nil {
+		klog.Warningf("failed to check if container already exists: %v", err)
+	}
+	if exists {
+		// if container was created by minikube it is safe to delete and recreate it.
+		if oci.IsCreatedByMinikube(d.OCIBinary, params.Name) {
+			klog.Info("Found already existing abandoned minikube container, will try to delete.")
+			if err := oci.DeleteContainer(d.OCIBinary, params.Name); err != nil {
+				klog.Errorf("Failed to delete a conflicting minikube container %s. You might need to restart your %s daemon and delete it manually and try again: %v", params.Name, params.OCIBinary, err)
+			}
+		} else {
+			// The conflicting container name was not created by minikube
+			// user has a container that conflicts with minikube profile name, will not delete users container.
+			return errors.Wrapf(err, "user has a conflicting container name %q with minikube container. Needs to be deleted by user's consent.", params.Name)
+		}
+	}
+
+	if err := oci.PrepareContainerNode(params); err != nil {
+		return errors.Wrap(err, "setting up container node")
+	}
+
+	var waitForPreload sync.WaitGroup
+	waitForPreload.Add(1)
+	var pErr error
+	go func() {
+		defer waitForPreload.Done()
+		// If preload doesn't exist, don't bother extracting tarball to volume
+		if !download.PreloadExists(d.NodeConfig.KubernetesVersion, d.NodeConfig.ContainerRuntime) {
+			return
+		}
+		t := time.Now()
+		klog.Infof("Starting extracting preloaded images to volume ...")
+		// Extract preloaded images to container
+		if err := oci.ExtractTarballToVolume(d.NodeConfig.OCIBinary, download.TarballPath(d.NodeConfig.KubernetesVersion, d.NodeConfig.ContainerRuntime), params.Name, d.NodeConfig.ImageDigest); err != nil {
+			if strings.Contains(err.Error(), "No space left on device") {
+				pErr = oci.ErrInsufficientDockerStorage
+				return
+			}
+			klog.Infof("Unable to extract preloaded tarball to volume: %v", err)
+		} else {
+			klog.Infof("duration metric: took %f seconds to extract preloaded images to volume", time.Since(t).Seconds())
+		}
+	}()
+	if pErr == oci.ErrInsufficientDockerStorage {
+		return pErr
+	}
+
+	if err := oci.CreateContainerNode(params); err != nil {
+		return errors.Wrap(err, "create kic node")
+	}
+
+	if err := d.prepareSSH(); err != nil {
+		return errors.Wrap(err, "prepare kic ssh")
+	}
+
+	waitForPreload.Wait()
+	return nil
+}
+
+// containerParams returns the parameters of the node container, creating its network if needed
+func (d *Driver) containerParams() oci.CreateParams {
 	params := oci.CreateParams{
 		Mounts:        d.NodeConfig.Mounts,
 		Name:          d.NodeConfig.MachineName,
@@ -127,64 +191,17 @@ func (d *Driver) Create() error {
 			ContainerPort: constants.RegistryAddonPort,
 		},
 	)
+	return params
+}
 
-	exists, err := oci.ContainerExists(d.OCIBinary, params.Name, true)
-	if err != nil {
-		klog.Warningf("failed to check if container already exists: %v", err)
-	}
-	if exists {
-		// if container was created by minikube it is safe to delete and recreate it.
-		if oci.IsCreatedByMinikube(d.OCIBinary, params.Name) {
-			klog.Info("Found already existing abandoned minikube container, will try to delete.")
-			if err := oci.DeleteContainer(d.OCIBinary, params.Name); err != nil {
-				klog.Errorf("Failed to delete a conflicting minikube container %s. You might need to restart your %s daemon and delete it manually and try again: %v", params.Name, params.OCIBinary, err)
-			}
-		} else {
-			// The conflicting container name was not created by minikube
-			// user has a container that conflicts with minikube profile name, will not delete users container.
-			return errors.Wrapf(err, "user has a conflicting container name %q with minikube container. Needs to be deleted by user's consent.", params.Name)
-		}
-	}
-
-	if err := oci.PrepareContainerNode(params); err != nil {
-		return errors.Wrap(err, "setting up container node")
-	}
-
-	var waitForPreload sync.WaitGroup
-	waitForPreload.Add(1)
-	var pErr error
-	go func() {
-		defer waitForPreload.Done()
-		// If preload doesn't exist, don't bother extracting tarball to volume
-		if !download.PreloadExists(d.NodeConfig.KubernetesVersion, d.NodeConfig.ContainerRuntime) {
-			return
-		}
-		t := time.Now()
-		klog.Infof("Starting extracting preloaded images to volume ...")
-		// Extract preloaded images to container
-		if err := oci.ExtractTarballToVolume(d.NodeConfig.OCIBinary, download.TarballPath(d.NodeConfig.KubernetesVersion, d.NodeConfig.ContainerRuntime), params.Name, d.NodeConfig.ImageDigest); err != nil {
-			if strings.Contains(err.Error(), "No space left on device") {
-				pErr = oci.ErrInsufficientDockerStorage
-				return
-			}
-			klog.Infof("Unable to extract preloaded tarball to volume: %v", err)
-		} else {
-			klog.Infof("duration metric: took %f seconds to extract preloaded images to volume", time.Since(t).Seconds())
-		}
-	}()
-	if pErr == oci.ErrInsufficientDockerStorage {
-		return pErr
-	}
-
+// CreateFromImage creates the node container from image, reusing the existing node volume and its contents.
+// It is used to restore a node from a committed snapshot of its container.
+func (d *Driver) CreateFromImage(image string) error {
+	params := d.containerParams()
+	params.Image = image
 	if err := oci.CreateContainerNode(params); err != nil {
 		return errors.Wrap(err, "create kic node")
 	}
-
-	if err := d.prepareSSH(); err != nil {
-		return errors.Wrap(err, "prepare kic ssh")
-	}
-
-	waitForPreload.Wait()
 	return nil
 }
 
@@ -322,6 +322,48 @@ func StartContainer(ociBin string, container string) error {
 	return nil
 }
 
+// PauseContainer pauses all processes of a container with "docker/podman pause"
+func PauseContainer(ociBin string, container string) error {
+	if _, err := runCmd(exec.Command(ociBin, "pause", container)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnpauseContainer resumes all processes of a paused container with "docker/podman unpause"
+func UnpauseContainer(ociBin string, container string) error {
+	if _, err := runCmd(exec.Command(ociBin, "unpause", container)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CommitContainer creates an image from the filesystem of a container with "docker/podman commit".
+// Volumes mounted into the container are not part of the image.
+func CommitContainer(ociBin string, container string, image string) error {
+	if _, err := runCmd(exec.Command(ociBin, "commit", "--change", fmt.Sprintf("LABEL %s=true", CreatedByLabelKey), container, image)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ImageSize returns the size of an image in bytes
+func ImageSize(ociBin string, image string) (int64, error) {
+	rr, err := runCmd(exec.Command(ociBin, "image", "inspect", "-f", "{{.Size}}", image))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(rr.Stdout.String()), 10, 64)
+}
+
+// RemoveImage removes an image with "docker/podman rmi"
+func RemoveImage(ociBin string, image string) error {
+	if _, err := runCmd(exec.Command(ociBin, "rmi", image)); err != nil {
+		return err
+	}
+	return nil
+}
+
 // ContainerID returns id of a container name
 func ContainerID(ociBin string, nameOrID string) (string, error) {
 	rr, err := runCmd(exec.Command(ociBin, "container", "inspect", "-f", "{{.Id}}", nameOrID))
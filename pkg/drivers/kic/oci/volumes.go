@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -98,6 +99,36 @@ func ExtractTarballToVolume(ociBin string, tarballPath, volumeName, imageName st
 	return nil
 }
 
+// ExportVolumeToTarball runs a docker image imageName which archives the contents of the volume named volumeName
+// to the tarball at tarballPath
+func ExportVolumeToTarball(ociBin string, volumeName, tarballPath, imageName string) error {
+	cmdArgs := []string{"run", "--rm", "--entrypoint", "/usr/bin/tar"}
+	if ociBin == Podman && runtime.GOOS == "linux" {
+		cmdArgs = append(cmdArgs, "--security-opt", "label=disable")
+	}
+	cmdArgs = append(cmdArgs, "-v", fmt.Sprintf("%s:/snapshot", filepath.Dir(tarballPath)), "-v", fmt.Sprintf("%s:/exportDir:ro", volumeName), imageName, "-I", "lz4", "-cf", "/snapshot/"+filepath.Base(tarballPath), "-C", "/exportDir", ".")
+	cmd := exec.Command(ociBin, cmdArgs...)
+	if _, err := runCmd(cmd); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreVolumeFromTarball runs a docker image imageName which replaces the contents of the volume named volumeName
+// with the tarball at tarballPath
+func RestoreVolumeFromTarball(ociBin string, tarballPath, volumeName, imageName string) error {
+	cmdArgs := []string{"run", "--rm", "--entrypoint", "/bin/bash"}
+	if ociBin == Podman && runtime.GOOS == "linux" {
+		cmdArgs = append(cmdArgs, "--security-opt", "label=disable")
+	}
+	cmdArgs = append(cmdArgs, "-v", fmt.Sprintf("%s:/snapshot.tar:ro", tarballPath), "-v", fmt.Sprintf("%s:/extractDir", volumeName), imageName, "-c", "find /extractDir -mindepth 1 -delete && tar -I lz4 -xpf /snapshot.tar -C /extractDir")
+	cmd := exec.Command(ociBin, cmdArgs...)
+	if _, err := runCmd(cmd); err != nil {
+		return err
+	}
+	return nil
+}
+
 // createVolume creates a volume to be attached to the container with correct labels and prefixes based on profile name
 // Caution ! if volume already exists does NOT return an error and will not apply the minikube labels on it.
 // TODO: this should be fixed as a part of https://github.com/kubernetes/minikube/issues/6530
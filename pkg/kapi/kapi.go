@@ -36,6 +36,7 @@ import (
 	watchtools "k8s.io/client-go/tools/watch"
 	"k8s.io/klog/v2"
 	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/vmpath"
 )
@@ -50,6 +51,10 @@ var (
 // ClientConfig returns the client configuration for a kubectl context
 func ClientConfig(context string) (*rest.Config, error) {
 	loader := clientcmd.NewDefaultClientConfigLoadingRules()
+	// the context of a profile with its own kubeconfig file is only found there
+	if p := kubeconfig.PathFor(context); p != kubeconfig.PathFromEnv() {
+		loader.ExplicitPath = p
+	}
 	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, &clientcmd.ConfigOverrides{CurrentContext: context})
 	c, err := cc.ClientConfig()
 	if err != nil {
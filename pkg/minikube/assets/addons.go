@@ -17,8 +17,11 @@ limitations under the License.
 package assets
 
 import (
+	"io"
+	"regexp"
 	"runtime"
 
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/vmpath"
@@ -58,6 +61,33 @@ func (a *Addon) IsEnabled(cc *config.ClusterConfig) bool {
 	return a.enabled
 }
 
+// imageRe matches the images referenced by the manifests of an addon
+var imageRe = regexp.MustCompile(`(?m)^[ \t]*(?:-[ \t]*)?image:[ \t]*["']?([^"'\s]+)["']?`)
+
+// Images returns the images the addon deploys for the given Kubernetes configuration
+func (a *Addon) Images(cfg config.KubernetesConfig) ([]string, error) {
+	data := GenerateTemplateData(cfg)
+	seen := map[string]bool{}
+	images := []string{}
+	for _, asset := range a.Assets {
+		f, err := asset.Evaluate(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "evaluate %s", asset.SourcePath)
+		}
+		contents := make([]byte, f.GetLength())
+		if _, err := io.ReadFull(f, contents); err != nil {
+			return nil, errors.Wrapf(err, "read %s", asset.SourcePath)
+		}
+		for _, m := range imageRe.FindAllStringSubmatch(string(contents), -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				images = append(images, m[1])
+			}
+		}
+	}
+	return images, nil
+}
+
 // Addons is the list of addons
 // TODO: Make dynamically loadable: move this data to a .yaml file within each addon directory
 var Addons = map[string]*Addon{
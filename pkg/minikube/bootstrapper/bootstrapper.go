@@ -40,11 +40,23 @@ type Bootstrapper interface {
 	DeleteCluster(config.KubernetesConfig) error
 	WaitForNode(config.ClusterConfig, config.Node, time.Duration) error
 	JoinCluster(config.ClusterConfig, config.Node, string) error
+	// RestartControlPlaneNode restarts the static pods of a control plane node which already joined the cluster.
+	RestartControlPlaneNode(config.ClusterConfig, config.Node) error
 	UpdateNode(config.ClusterConfig, config.Node, cruntime.Manager) error
-	GenerateToken(config.ClusterConfig) (string, error)
+	// UpgradeControlPlane upgrades a control plane node in place to the cluster's Kubernetes version.
+	UpgradeControlPlane(config.ClusterConfig, config.Node) error
+	// UpgradeNode upgrades a worker node in place to the cluster's Kubernetes version.
+	UpgradeNode(config.ClusterConfig, config.Node) error
+	DrainNode(config.ClusterConfig, config.Node) error
+	UncordonNode(config.ClusterConfig, config.Node) error
+	GenerateToken(config.ClusterConfig, config.Node) (string, error)
+	// LabelAndTaintNode applies the labels and taints of a node, running against the control plane.
+	LabelAndTaintNode(config.ClusterConfig, config.Node) error
 	// LogCommands returns a map of log type to a command which will display that log.
 	LogCommands(config.ClusterConfig, LogOptions) map[string]string
 	SetupCerts(config.KubernetesConfig, config.Node) error
+	// RotateCerts renews the certificates of a node, and restarts its control plane to load them.
+	RotateCerts(config.ClusterConfig, config.Node) error
 	GetAPIServerStatus(string, int) (string, error)
 }
 
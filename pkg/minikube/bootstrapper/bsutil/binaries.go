@@ -37,7 +37,8 @@ import (
 	"k8s.io/minikube/pkg/minikube/vmpath"
 )
 
-// TransferBinaries transfers all required Kubernetes binaries
+// TransferBinaries transfers all required Kubernetes binaries.
+// sm stops the kubelet before its binary is copied; with a nil sm, a kubelet of another version keeps running.
 func TransferBinaries(cfg config.KubernetesConfig, c command.Runner, sm sysinit.Manager) error {
 	ok, err := binariesExist(cfg, c)
 	if err == nil && ok {
@@ -61,7 +62,7 @@ func TransferBinaries(cfg config.KubernetesConfig, c command.Runner, sm sysinit.
 				return errors.Wrapf(err, "downloading %s", name)
 			}
 
-			if name == "kubelet" && sm.Active(name) {
+			if name == "kubelet" && sm != nil && sm.Active(name) {
 				if err := sm.ForceStop(name); err != nil {
 					klog.Errorf("unable to stop kubelet: %v", err)
 				}
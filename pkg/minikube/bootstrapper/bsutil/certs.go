@@ -0,0 +1,33 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+)
+
+// RenewCertsCmd returns the command which renews the certificates and kubeconfig files kubeadm manages, keeping their keys
+func RenewCertsCmd(version semver.Version, conf string) string {
+	// the certs command graduated from alpha in kubeadm v1.20
+	phase := "certs"
+	if version.LT(semver.MustParse("1.20.0")) {
+		phase = "alpha certs"
+	}
+	return fmt.Sprintf("%s %s renew all --config %s", InvokeKubeadm("v"+version.String()), phase, conf)
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+)
+
+func TestRenewCertsCmd(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.20.0", "sudo env PATH=/var/lib/minikube/binaries/v1.20.0:$PATH kubeadm certs renew all --config /var/tmp/minikube/kubeadm.yaml"},
+		{"1.19.4", "sudo env PATH=/var/lib/minikube/binaries/v1.19.4:$PATH kubeadm alpha certs renew all --config /var/tmp/minikube/kubeadm.yaml"},
+	}
+	for _, test := range tests {
+		if got := RenewCertsCmd(semver.MustParse(test.version), KubeadmYamlPath); got != test.want {
+			t.Errorf("RenewCertsCmd(%s) = %q, expected %q", test.version, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"path"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// IdleStopAuditPolicyPath is where the audit policy of the idle auto-stop is written on control plane nodes.
+// kubeadm already mounts the certs directory into the API server pod, so the policy is kept there.
+var IdleStopAuditPolicyPath = path.Join(vmpath.GuestKubernetesCertsDir, "idle-stop-audit-policy.yaml")
+
+// idleStopAuditPolicy logs the requests which don't come from the cluster itself, such as kubectl on the host.
+// The minikube-idle-stop service counts these events in the API server log to tell whether the cluster is in use.
+var idleStopAuditPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+  - RequestReceived
+rules:
+  - level: None
+    users:
+      - system:apiserver
+      - system:kube-controller-manager
+      - system:kube-scheduler
+      - system:kube-proxy
+  - level: None
+    userGroups:
+      - system:nodes
+      - system:serviceaccounts
+  - level: None
+    nonResourceURLs:
+      - /healthz*
+      - /livez*
+      - /readyz*
+      - /version
+  - level: Metadata
+`
+
+// IdleStopAuditPolicy returns the audit policy which makes the API server log the requests from outside the cluster
+func IdleStopAuditPolicy() []byte {
+	return []byte(idleStopAuditPolicy)
+}
+
+// idleStopOptions returns opts with the API server flags which write audit events to its log, unless audit logging is already configured
+func idleStopOptions(opts config.ExtraOptionSlice) config.ExtraOptionSlice {
+	if opts.Get("audit-policy-file", Apiserver) != "" || opts.Get("audit-log-path", Apiserver) != "" {
+		klog.Warningf("audit logging is configured by --extra-config, the idle auto-stop only sees the requests it logs to stdout")
+		return opts
+	}
+	withAudit := append(config.ExtraOptionSlice{}, opts...)
+	return append(withAudit,
+		config.ExtraOption{Component: Apiserver, Key: "audit-policy-file", Value: IdleStopAuditPolicyPath},
+		config.ExtraOption{Component: Apiserver, Key: "audit-log-path", Value: "-"},
+	)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestIdleStopOptions(t *testing.T) {
+	opts := config.ExtraOptionSlice{{Component: Apiserver, Key: "v", Value: "2"}}
+	got := idleStopOptions(opts)
+	if got.Get("audit-policy-file", Apiserver) != IdleStopAuditPolicyPath {
+		t.Errorf("audit-policy-file = %q, expected %q", got.Get("audit-policy-file", Apiserver), IdleStopAuditPolicyPath)
+	}
+	if got.Get("audit-log-path", Apiserver) != "-" {
+		t.Errorf("audit-log-path = %q, expected %q", got.Get("audit-log-path", Apiserver), "-")
+	}
+	if got.Get("v", Apiserver) != "2" {
+		t.Errorf("existing options were not kept: %v", got)
+	}
+	if len(opts) != 1 {
+		t.Errorf("idleStopOptions() modified its argument: %v", opts)
+	}
+
+	configured := config.ExtraOptionSlice{{Component: Apiserver, Key: "audit-log-path", Value: "/var/log/audit.log"}}
+	got = idleStopOptions(configured)
+	if len(got) != 1 || got.Get("audit-log-path", Apiserver) != "/var/log/audit.log" {
+		t.Errorf("idleStopOptions() overrode the audit logging of --extra-config: %v", got)
+	}
+}
@@ -66,7 +66,12 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		return nil, errors.Wrap(err, "getting cgroup driver")
 	}
 
-	componentOpts, err := createExtraComponentConfig(k8s.ExtraOptions, version, componentFeatureArgs, cp)
+	extraOpts := k8s.ExtraOptions
+	if cc.AutoStopIdle > 0 {
+		extraOpts = idleStopOptions(extraOpts)
+	}
+
+	componentOpts, err := createExtraComponentConfig(extraOpts, version, componentFeatureArgs, cp)
 	if err != nil {
 		return nil, errors.Wrap(err, "generating extra component config for kubeadm")
 	}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os/exec"
+	"path"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// KubeVIPManifestPath is where the kube-vip static pod is written on control plane nodes
+var KubeVIPManifestPath = path.Join(vmpath.GuestManifestsDir, "kube-vip.yaml")
+
+// HAVIP returns the virtual IP of the API server for a highly available cluster whose primary control plane has the given IP.
+// A requested address must be within the /24 network of the node. Otherwise, this is the last usable address of the network,
+// as minikube hands out node addresses from the bottom of the network.
+func HAVIP(nodeIP string, requested string) (string, error) {
+	ip := net.ParseIP(nodeIP).To4()
+	if ip == nil {
+		return "", fmt.Errorf("%q is not an IPv4 address", nodeIP)
+	}
+	vip := make(net.IP, len(ip))
+	copy(vip, ip)
+	vip[3] = 254
+	if requested != "" {
+		vip = net.ParseIP(requested).To4()
+		if vip == nil {
+			return "", fmt.Errorf("virtual IP %q is not an IPv4 address", requested)
+		}
+		if !vip.Mask(net.CIDRMask(24, 32)).Equal(ip.Mask(net.CIDRMask(24, 32))) || vip[3] == 0 || vip[3] == 255 {
+			return "", fmt.Errorf("virtual IP %s is not a usable address of the network of node IP %s", requested, nodeIP)
+		}
+	}
+	if vip.Equal(ip) {
+		return "", fmt.Errorf("virtual IP %s is the address of the node", vip)
+	}
+	return vip.String(), nil
+}
+
+// AddressInUse returns whether an address answers to ping from a node
+func AddressInUse(r command.Runner, ip string) bool {
+	_, err := r.RunCmd(exec.Command("ping", "-c", "1", "-W", "1", ip))
+	return err == nil
+}
+
+// NewKubeVIPManifest returns the static pod which announces the virtual IP of the API server on the given network interface
+func NewKubeVIPManifest(cfg config.ClusterConfig, iface string) ([]byte, error) {
+	cp, err := config.PrimaryControlPlane(&cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "control plane")
+	}
+
+	opts := struct {
+		Image     string
+		Interface string
+		Port      int
+		VIP       string
+	}{
+		Image:     images.KubeVIP(cfg.KubernetesConfig.ImageRepository),
+		Interface: iface,
+		Port:      cp.Port,
+		VIP:       cfg.KubernetesConfig.APIServerHAVIP,
+	}
+
+	var b bytes.Buffer
+	if err := ktmpl.KubeVIPTemplate.Execute(&b, opts); err != nil {
+		return nil, errors.Wrap(err, "template execute")
+	}
+	return b.Bytes(), nil
+}
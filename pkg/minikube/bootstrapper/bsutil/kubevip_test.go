@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestHAVIP(t *testing.T) {
+	tests := []struct {
+		ip        string
+		requested string
+		expected  string
+		err       bool
+	}{
+		{ip: "192.168.49.2", expected: "192.168.49.254"},
+		{ip: "192.168.39.100", expected: "192.168.39.254"},
+		{ip: "192.168.39.254", err: true},
+		{ip: "fd00::2", err: true},
+		{ip: "", err: true},
+		{ip: "192.168.49.2", requested: "192.168.49.100", expected: "192.168.49.100"},
+		{ip: "192.168.49.2", requested: "192.168.49.2", err: true},
+		{ip: "192.168.49.2", requested: "192.168.50.100", err: true},
+		{ip: "192.168.49.2", requested: "192.168.49.255", err: true},
+		{ip: "192.168.49.2", requested: "vip", err: true},
+	}
+	for _, test := range tests {
+		got, err := HAVIP(test.ip, test.requested)
+		if (err != nil) != test.err {
+			t.Fatalf("HAVIP(%q, %q) error = %v, expected error: %v", test.ip, test.requested, err, test.err)
+		}
+		if got != test.expected {
+			t.Errorf("HAVIP(%q, %q) = %q, expected %q", test.ip, test.requested, got, test.expected)
+		}
+	}
+}
+
+func TestNewKubeVIPManifest(t *testing.T) {
+	cfg := config.ClusterConfig{
+		KubernetesConfig: config.KubernetesConfig{APIServerHAVIP: "192.168.49.254"},
+		Nodes:            []config.Node{{ControlPlane: true, Port: 8443}},
+	}
+	got, err := NewKubeVIPManifest(cfg, "eth0")
+	if err != nil {
+		t.Fatalf("NewKubeVIPManifest() error = %v", err)
+	}
+	for _, want := range []string{
+		"image: ghcr.io/kube-vip/kube-vip:v0.4.0",
+		"value: \"8443\"",
+		"value: eth0",
+		"value: 192.168.49.254",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("NewKubeVIPManifest() does not contain %q:\n%s", want, got)
+		}
+	}
+}
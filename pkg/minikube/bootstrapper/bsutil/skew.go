@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+// MaxKubeletSkew is the number of minor versions a kubelet may lag behind the API server
+const MaxKubeletSkew = 2
+
+// ValidateKubeletSkew checks that a node running the given version is supported by a control plane running the cluster version
+func ValidateKubeletSkew(cluster semver.Version, node semver.Version) error {
+	if node.GT(cluster) {
+		return fmt.Errorf("the kubelet v%s must not be newer than the control plane v%s", node, cluster)
+	}
+	if node.Major != cluster.Major || cluster.Minor > node.Minor+MaxKubeletSkew {
+		return fmt.Errorf("the kubelet v%s may lag the control plane v%s by at most %d minor versions", node, cluster, MaxKubeletSkew)
+	}
+	return nil
+}
+
+// KubeletConfigMapName returns the name of the ConfigMap which kubeadm join reads the kubelet configuration of the given version from
+func KubeletConfigMapName(version semver.Version) string {
+	return fmt.Sprintf("kubelet-config-%d.%d", version.Major, version.Minor)
+}
+
+// KubeletConfigMapCmd returns the command which lets kubeadm join a node running an older minor version than the cluster.
+// kubeadm only creates the kubelet ConfigMap, and the role to read it, for the version of the control plane.
+func KubeletConfigMapCmd(cluster semver.Version, node semver.Version, kubectl string) string {
+	dryRun := "--dry-run=client"
+	if cluster.LT(semver.MustParse("1.18.0")) {
+		dryRun = "--dry-run"
+	}
+
+	src := KubeletConfigMapName(cluster)
+	dst := KubeletConfigMapName(node)
+	role := "kubeadm:" + dst
+	apply := fmt.Sprintf("%s apply -f -", kubectl)
+	cmds := []string{
+		fmt.Sprintf("%s -n kube-system get configmap %s -o jsonpath='{.data.kubelet}' | %s -n kube-system create configmap %s --from-file=kubelet=/dev/stdin %s -o yaml | %s",
+			kubectl, src, kubectl, dst, dryRun, apply),
+		fmt.Sprintf("%s -n kube-system create role %s --verb=get --resource=configmaps --resource-name=%s %s -o yaml | %s",
+			kubectl, role, dst, dryRun, apply),
+		fmt.Sprintf("%s -n kube-system create rolebinding %s --role=%s --group=system:nodes --group=system:bootstrappers:kubeadm:default-node-token %s -o yaml | %s",
+			kubectl, role, role, dryRun, apply),
+	}
+	return strings.Join(cmds, " && ")
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+)
+
+func TestValidateKubeletSkew(t *testing.T) {
+	tests := []struct {
+		cluster string
+		node    string
+		err     bool
+	}{
+		{cluster: "1.20.0", node: "1.20.0"},
+		{cluster: "1.20.2", node: "1.20.0"},
+		{cluster: "1.20.0", node: "1.19.4"},
+		{cluster: "1.20.0", node: "1.18.10"},
+		{cluster: "1.20.0", node: "1.17.0", err: true},
+		{cluster: "1.19.4", node: "1.20.0", err: true},
+	}
+	for _, test := range tests {
+		err := ValidateKubeletSkew(semver.MustParse(test.cluster), semver.MustParse(test.node))
+		if (err != nil) != test.err {
+			t.Errorf("ValidateKubeletSkew(%s, %s) error = %v, expected error: %v", test.cluster, test.node, err, test.err)
+		}
+	}
+}
+
+func TestKubeletConfigMapCmd(t *testing.T) {
+	tests := []struct {
+		cluster string
+		dryRun  string
+	}{
+		{cluster: "1.20.0", dryRun: "--dry-run=client "},
+		{cluster: "1.17.3", dryRun: "--dry-run "},
+	}
+	for _, test := range tests {
+		got := KubeletConfigMapCmd(semver.MustParse(test.cluster), semver.MustParse("1.16.0"), "kubectl")
+		for _, want := range []string{
+			"get configmap " + KubeletConfigMapName(semver.MustParse(test.cluster)) + " ",
+			"create configmap kubelet-config-1.16 --from-file=kubelet=/dev/stdin " + test.dryRun,
+			"create role kubeadm:kubelet-config-1.16 --verb=get --resource=configmaps --resource-name=kubelet-config-1.16 " + test.dryRun,
+			"create rolebinding kubeadm:kubelet-config-1.16 --role=kubeadm:kubelet-config-1.16 ",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("KubeletConfigMapCmd(%s) = %q, expected it to contain %q", test.cluster, got, want)
+			}
+		}
+	}
+}
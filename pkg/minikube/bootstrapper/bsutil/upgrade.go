@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// DrainTimeout is how long kubectl drain may wait for pods to be evicted
+const DrainTimeout = 5 * time.Minute
+
+// NeedsUpgrade returns whether a node runs an older Kubernetes version than the cluster
+func NeedsUpgrade(cfg config.ClusterConfig, n config.Node) (bool, error) {
+	// Nodes which were never started have nothing to upgrade, and pinned nodes stay on their version
+	if n.KubernetesVersion == "" || n.PinnedVersion {
+		return false, nil
+	}
+	current, err := util.ParseKubernetesVersion(n.KubernetesVersion)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing node version %q", n.KubernetesVersion)
+	}
+	target, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing cluster version %q", cfg.KubernetesConfig.KubernetesVersion)
+	}
+	return current.LT(target), nil
+}
+
+// DrainArgs returns the kubectl arguments to evict all workloads from a node
+func DrainArgs(version semver.Version, node string) []string {
+	// --delete-local-data was renamed in kubectl v1.20
+	emptyDir := "--delete-emptydir-data"
+	if version.LT(semver.MustParse("1.20.0")) {
+		emptyDir = "--delete-local-data"
+	}
+	// --force evicts bare pods such as the storage-provisioner, which is recreated by the addon manager
+	return []string{"drain", node, "--ignore-daemonsets", emptyDir, "--force", fmt.Sprintf("--timeout=%s", DrainTimeout)}
+}
+
+// UpgradeApplyCmd returns the command which upgrades the control plane to the version in the kubeadm config
+func UpgradeApplyCmd(version semver.Version, conf string) string {
+	cmd := fmt.Sprintf("%s upgrade apply v%s --config %s --yes --force --ignore-preflight-errors=all", InvokeKubeadm("v"+version.String()), version, conf)
+	// Certificates are managed by minikube, so kubeadm must not renew them
+	if version.GTE(semver.MustParse("1.15.0")) {
+		cmd += " --certificate-renewal=false"
+	}
+	return cmd
+}
+
+// UpgradeNodeCmd returns the command which upgrades the kubelet configuration of a node
+func UpgradeNodeCmd(version semver.Version) string {
+	if version.LT(semver.MustParse("1.15.0")) {
+		return fmt.Sprintf("%s upgrade node config --kubelet-version v%s", InvokeKubeadm("v"+version.String()), version)
+	}
+	return fmt.Sprintf("%s upgrade node --certificate-renewal=false", InvokeKubeadm("v"+version.String()))
+}
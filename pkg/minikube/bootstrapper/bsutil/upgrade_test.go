@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestNeedsUpgrade(t *testing.T) {
+	tests := []struct {
+		description string
+		node        string
+		pinned      bool
+		cluster     string
+		expected    bool
+		err         bool
+	}{
+		{description: "older node", node: "v1.19.4", cluster: "v1.20.0", expected: true},
+		{description: "patch release", node: "v1.20.0", cluster: "v1.20.2", expected: true},
+		{description: "same version", node: "v1.20.0", cluster: "v1.20.0", expected: false},
+		{description: "newer node", node: "v1.20.0", cluster: "v1.19.4", expected: false},
+		{description: "new node", node: "", cluster: "v1.20.0", expected: false},
+		{description: "pinned node", node: "v1.19.4", pinned: true, cluster: "v1.20.0", expected: false},
+		{description: "invalid node version", node: "vfoo", cluster: "v1.20.0", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{KubernetesVersion: test.cluster}}
+			got, err := NeedsUpgrade(cfg, config.Node{Name: "m02", KubernetesVersion: test.node, PinnedVersion: test.pinned})
+			if (err != nil) != test.err {
+				t.Fatalf("NeedsUpgrade() error = %v, expected error: %v", err, test.err)
+			}
+			if got != test.expected {
+				t.Errorf("NeedsUpgrade() = %v, expected %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestDrainArgs(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{version: "1.20.0", expected: "drain minikube-m02 --ignore-daemonsets --delete-emptydir-data --force --timeout=5m0s"},
+		{version: "1.19.4", expected: "drain minikube-m02 --ignore-daemonsets --delete-local-data --force --timeout=5m0s"},
+	}
+	for _, test := range tests {
+		got := strings.Join(DrainArgs(semver.MustParse(test.version), "minikube-m02"), " ")
+		if got != test.expected {
+			t.Errorf("DrainArgs(%s) = %q, expected %q", test.version, got, test.expected)
+		}
+	}
+}
+
+func TestUpgradeCmds(t *testing.T) {
+	tests := []struct {
+		version string
+		apply   string
+		node    string
+	}{
+		{
+			version: "1.20.0",
+			apply:   "sudo env PATH=/var/lib/minikube/binaries/v1.20.0:$PATH kubeadm upgrade apply v1.20.0 --config /var/tmp/minikube/kubeadm.yaml.new --yes --force --ignore-preflight-errors=all --certificate-renewal=false",
+			node:    "sudo env PATH=/var/lib/minikube/binaries/v1.20.0:$PATH kubeadm upgrade node --certificate-renewal=false",
+		},
+		{
+			version: "1.14.10",
+			apply:   "sudo env PATH=/var/lib/minikube/binaries/v1.14.10:$PATH kubeadm upgrade apply v1.14.10 --config /var/tmp/minikube/kubeadm.yaml.new --yes --force --ignore-preflight-errors=all",
+			node:    "sudo env PATH=/var/lib/minikube/binaries/v1.14.10:$PATH kubeadm upgrade node config --kubelet-version v1.14.10",
+		},
+	}
+	for _, test := range tests {
+		v := semver.MustParse(test.version)
+		if got := UpgradeApplyCmd(v, KubeadmYamlPath+".new"); got != test.apply {
+			t.Errorf("UpgradeApplyCmd(%s) = %q, expected %q", test.version, got, test.apply)
+		}
+		if got := UpgradeNodeCmd(v); got != test.node {
+			t.Errorf("UpgradeNodeCmd(%s) = %q, expected %q", test.version, got, test.node)
+		}
+	}
+}
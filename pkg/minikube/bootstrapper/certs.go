@@ -51,9 +51,9 @@ func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig, n config.Node)
 	localPath := localpath.Profile(k8s.ClusterName)
 	klog.Infof("Setting up %s for IP: %s\n", localPath, n.IP)
 
-	ccs, err := generateSharedCACerts()
+	ccs, err := setupCACerts(k8s)
 	if err != nil {
-		return nil, errors.Wrap(err, "shared CA certs")
+		return nil, errors.Wrap(err, "CA certs")
 	}
 
 	xfer, err := generateProfileCerts(k8s, n, ccs)
@@ -80,7 +80,7 @@ func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig, n config.Node)
 		copyableFiles = append(copyableFiles, certFile)
 	}
 
-	caCerts, err := collectCACerts()
+	caCerts, err := collectCACerts(ccs.caCert)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +131,32 @@ func SetupCerts(cmd command.Runner, k8s config.KubernetesConfig, n config.Node)
 	return copyableFiles, nil
 }
 
+// sharedControlPlaneCerts are generated by kubeadm init on the primary control plane, and shared by all control planes
+var sharedControlPlaneCerts = []string{"sa.key", "sa.pub", "front-proxy-ca.crt", "front-proxy-ca.key", "etcd/ca.crt", "etcd/ca.key"}
+
+// CopySharedCerts copies the certificates that every control plane must share from the primary control plane to another one
+func CopySharedCerts(from command.Runner, to command.Runner) error {
+	if _, err := to.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Join(vmpath.GuestKubernetesCertsDir, "etcd"))); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	for _, name := range sharedControlPlaneCerts {
+		src := path.Join(vmpath.GuestKubernetesCertsDir, name)
+		rr, err := from.RunCmd(exec.Command("sudo", "cat", src))
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", src)
+		}
+		perms := "0644"
+		if strings.HasSuffix(name, ".key") {
+			perms = "0600"
+		}
+		f := assets.NewMemoryAssetTarget(rr.Stdout.Bytes(), src, perms)
+		if err := to.Copy(f); err != nil {
+			return errors.Wrapf(err, "copying %s", src)
+		}
+	}
+	return nil
+}
+
 // CACerts has cert and key for CA (and Proxy)
 type CACerts struct {
 	caCert    string
@@ -139,15 +165,81 @@ type CACerts struct {
 	proxyKey  string
 }
 
-// generateSharedCACerts generates CA certs shared among profiles, but only if missing
-func generateSharedCACerts() (CACerts, error) {
+// ClusterCACert returns the CA certificate which signs the certs of a cluster
+func ClusterCACert(k8s config.KubernetesConfig) string {
+	return caCertPaths(k8s).caCert
+}
+
+// caCertPaths returns where the CAs which sign the certs of a cluster are kept on the host
+func caCertPaths(k8s config.KubernetesConfig) CACerts {
+	if k8s.CACertPath != "" {
+		// A CA supplied by the user is copied into the profile, and signs the aggregator proxy-client cert as well
+		profilePath := localpath.Profile(k8s.ClusterName)
+		return CACerts{
+			caCert:    filepath.Join(profilePath, "ca.crt"),
+			caKey:     filepath.Join(profilePath, "ca.key"),
+			proxyCert: filepath.Join(profilePath, "proxy-client-ca.crt"),
+			proxyKey:  filepath.Join(profilePath, "proxy-client-ca.key"),
+		}
+	}
 	globalPath := localpath.MiniPath()
-	cc := CACerts{
+	return CACerts{
 		caCert:    localpath.CACert(),
 		caKey:     filepath.Join(globalPath, "ca.key"),
 		proxyCert: filepath.Join(globalPath, "proxy-client-ca.crt"),
 		proxyKey:  filepath.Join(globalPath, "proxy-client-ca.key"),
 	}
+}
+
+// setupCACerts returns the CAs of a cluster: the one supplied by the user, or else the ones shared among profiles
+func setupCACerts(k8s config.KubernetesConfig) (CACerts, error) {
+	if k8s.CACertPath != "" {
+		return installCustomCA(k8s)
+	}
+	return generateSharedCACerts()
+}
+
+// installCustomCA copies the CA supplied by the user into the profile, unless it is there already.
+// The copy is kept for the lifetime of the cluster, even if the supplied files change.
+func installCustomCA(k8s config.KubernetesConfig) (CACerts, error) {
+	cc := caCertPaths(k8s)
+	if canRead(cc.caCert) && canRead(cc.caKey) && canRead(cc.proxyCert) && canRead(cc.proxyKey) {
+		klog.Infof("skipping CA installation, found %s", cc.caCert)
+		return cc, nil
+	}
+
+	if _, err := util.ValidateCA(k8s.CACertPath, k8s.CAKeyPath); err != nil {
+		return cc, errors.Wrap(err, "invalid CA")
+	}
+	files := []struct {
+		src  string
+		dst  string
+		perm os.FileMode
+	}{
+		{k8s.CACertPath, cc.caCert, 0644},
+		{k8s.CAKeyPath, cc.caKey, 0600},
+		{k8s.CACertPath, cc.proxyCert, 0644},
+		{k8s.CAKeyPath, cc.proxyKey, 0600},
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f.src)
+		if err != nil {
+			return cc, errors.Wrapf(err, "reading %s", f.src)
+		}
+		if err := os.MkdirAll(filepath.Dir(f.dst), 0755); err != nil {
+			return cc, errors.Wrap(err, "mkdir")
+		}
+		klog.Infof("copying %s -> %s", f.src, f.dst)
+		if err := ioutil.WriteFile(f.dst, data, f.perm); err != nil {
+			return cc, errors.Wrapf(err, "writing %s", f.dst)
+		}
+	}
+	return cc, nil
+}
+
+// generateSharedCACerts generates CA certs shared among profiles, but only if missing
+func generateSharedCACerts() (CACerts, error) {
+	cc := caCertPaths(config.KubernetesConfig{})
 
 	caCertSpecs := []struct {
 		certPath string
@@ -204,6 +296,13 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 	}
 
 	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName, constants.ControlPlaneAlias)
+
+	// Every control plane of a highly available cluster serves the virtual IP, and kubeadm
+	// expects the serving certificate of a joining control plane to be valid for its node name
+	if k8s.APIServerHAVIP != "" {
+		apiServerIPs = append(apiServerIPs, net.ParseIP(k8s.APIServerHAVIP))
+		apiServerNames = append(apiServerNames, k8s.ClusterName, fmt.Sprintf("%s-%s", k8s.ClusterName, n.Name))
+	}
 	apiServerAlternateNames := append(
 		apiServerNames,
 		util.GetAlternateDNS(k8s.DNSDomain)...)
@@ -281,6 +380,7 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 			cp, kp, spec.subject,
 			spec.ips, spec.alternateNames,
 			spec.caCertPath, spec.caKeyPath,
+			certOptions(k8s),
 		)
 		if err != nil {
 			return xfer, errors.Wrapf(err, "generate signed cert for %q", spec.subject)
@@ -301,6 +401,39 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 	return xfer, nil
 }
 
+// certOptions returns the key and validity of the certs minikube signs for a cluster, which are in the system:masters group
+func certOptions(k8s config.KubernetesConfig) util.CertOptions {
+	return util.CertOptions{KeyAlgorithm: k8s.CertKeyAlgorithm, KeySize: k8s.CertKeySize, Validity: k8s.CertValidity, Groups: []string{"system:masters"}}.WithDefaults()
+}
+
+// RemoveProfileCerts removes the certificates generateProfileCerts signed for a profile, so that the next SetupCerts generates new ones.
+// The CAs are shared among profiles, and are kept.
+func RemoveProfileCerts(clusterName string) error {
+	profilePath := localpath.Profile(clusterName)
+	patterns := []string{
+		localpath.ClientCert(clusterName),
+		localpath.ClientKey(clusterName),
+		// the apiserver certs are also kept suffixed with a hash of their IPs and names
+		filepath.Join(profilePath, "apiserver.crt*"),
+		filepath.Join(profilePath, "apiserver.key*"),
+		filepath.Join(profilePath, "proxy-client.crt"),
+		filepath.Join(profilePath, "proxy-client.key"),
+	}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "glob %s", pattern)
+		}
+		for _, m := range matches {
+			klog.Infof("removing %s", m)
+			if err := os.Remove(m); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "remove %s", m)
+			}
+		}
+	}
+	return nil
+}
+
 // isValidPEMCertificate checks whether the input file is a valid PEM certificate (with at least one CERTIFICATE block)
 func isValidPEMCertificate(filePath string) (bool, error) {
 	fileBytes, err := ioutil.ReadFile(filePath)
@@ -326,7 +459,7 @@ func isValidPEMCertificate(filePath string) (bool, error) {
 
 // collectCACerts looks up all PEM certificates with .crt or .pem extension in ~/.minikube/certs to copy to the host.
 // minikube root CA is also included but libmachine certificates (ca.pem/cert.pem) are excluded.
-func collectCACerts() (map[string]string, error) {
+func collectCACerts(caCert string) (map[string]string, error) {
 	localPath := localpath.MiniPath()
 	certFiles := map[string]string{}
 
@@ -375,7 +508,7 @@ func collectCACerts() (map[string]string, error) {
 	}
 
 	// populates minikube CA
-	certFiles[filepath.Join(localPath, "ca.crt")] = path.Join(vmpath.GuestCertAuthDir, "minikubeCA.pem")
+	certFiles[caCert] = path.Join(vmpath.GuestCertAuthDir, "minikubeCA.pem")
 
 	filtered := map[string]string{}
 	for k, v := range certFiles {
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+const (
+	// ManagedByMinikube marks the certificates minikube generates and copies to the nodes
+	ManagedByMinikube = "minikube"
+	// ManagedByKubeadm marks the certificates kubeadm generates on the nodes
+	ManagedByKubeadm = "kubeadm"
+)
+
+// CertExpiry is when a certificate of a cluster expires
+type CertExpiry struct {
+	// Location is "host", or the machine name of the node which holds the certificate
+	Location string `json:"location"`
+	// Name is the path of the certificate relative to the certificates directory, or the kubeconfig file embedding it
+	Name      string    `json:"name"`
+	ManagedBy string    `json:"managedBy"`
+	CA        bool      `json:"ca"`
+	NotAfter  time.Time `json:"notAfter"`
+}
+
+// nodeMinikubeCerts are the certificates of a node which minikube generates, as opposed to kubeadm
+var nodeMinikubeCerts = map[string]bool{"ca.crt": true, "proxy-client-ca.crt": true, "apiserver.crt": true, "proxy-client.crt": true}
+
+// kubeadmKubeconfigs are the kubeconfig files in which kubeadm embeds the client certificates of the control plane
+var kubeadmKubeconfigs = []string{kconst.AdminKubeConfigFileName, kconst.ControllerManagerKubeConfigFileName, kconst.SchedulerKubeConfigFileName}
+
+// HostCertExpirations returns when the certificates minikube keeps on the host for a cluster expire
+func HostCertExpirations(k8s config.KubernetesConfig) ([]CertExpiry, error) {
+	profilePath := localpath.Profile(k8s.ClusterName)
+	ccs := caCertPaths(k8s)
+	paths := []string{
+		ccs.caCert,
+		ccs.proxyCert,
+		localpath.ClientCert(k8s.ClusterName),
+		filepath.Join(profilePath, "apiserver.crt"),
+		filepath.Join(profilePath, "proxy-client.crt"),
+	}
+
+	ces := []CertExpiry{}
+	for _, p := range paths {
+		if !canRead(p) {
+			continue
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", p)
+		}
+		cert, err := parseCert(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", p)
+		}
+		name, err := filepath.Rel(localpath.MiniPath(), p)
+		if err != nil {
+			name = p
+		}
+		ces = append(ces, CertExpiry{Location: "host", Name: filepath.ToSlash(name), ManagedBy: ManagedByMinikube, CA: cert.IsCA, NotAfter: cert.NotAfter})
+	}
+	return ces, nil
+}
+
+// NodeCertExpirations returns when the certificates of a node expire, including the client certificates embedded in the kubeconfig files of kubeadm
+func NodeCertExpirations(cr command.Runner, location string) ([]CertExpiry, error) {
+	rr, err := cr.RunCmd(exec.Command("sudo", "find", vmpath.GuestKubernetesCertsDir, "-name", "*.crt"))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing certificates")
+	}
+	paths := strings.Fields(rr.Stdout.String())
+	sort.Strings(paths)
+
+	ces := []CertExpiry{}
+	for _, p := range paths {
+		data, err := readFile(cr, p)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := parseCert(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", p)
+		}
+		name := strings.TrimPrefix(p, vmpath.GuestKubernetesCertsDir+"/")
+		managedBy := ManagedByKubeadm
+		if nodeMinikubeCerts[name] {
+			managedBy = ManagedByMinikube
+		}
+		ces = append(ces, CertExpiry{Location: location, Name: name, ManagedBy: managedBy, CA: cert.IsCA, NotAfter: cert.NotAfter})
+	}
+
+	// kubeadm only writes these kubeconfig files on control planes
+	rr, err = cr.RunCmd(exec.Command("sudo", "find", kconst.KubernetesDir, "-maxdepth", "1", "-name", "*.conf"))
+	if err != nil {
+		return nil, errors.Wrap(err, "listing kubeconfig files")
+	}
+	existing := map[string]bool{}
+	for _, p := range strings.Fields(rr.Stdout.String()) {
+		existing[path.Base(p)] = true
+	}
+	for _, name := range kubeadmKubeconfigs {
+		if !existing[name] {
+			continue
+		}
+		data, err := readFile(cr, path.Join(kconst.KubernetesDir, name))
+		if err != nil {
+			return nil, err
+		}
+		cert, err := kubeconfigCert(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", name)
+		}
+		if cert == nil {
+			continue
+		}
+		ces = append(ces, CertExpiry{Location: location, Name: name, ManagedBy: ManagedByKubeadm, NotAfter: cert.NotAfter})
+	}
+	return ces, nil
+}
+
+// readFile returns the contents of a file on a node
+func readFile(cr command.Runner, p string) ([]byte, error) {
+	rr, err := cr.RunCmd(exec.Command("sudo", "cat", p))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", p)
+	}
+	return rr.Stdout.Bytes(), nil
+}
+
+// parseCert parses the first certificate of PEM encoded data
+func parseCert(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// kubeconfigCert returns the embedded client certificate of a kubeconfig file, or nil if it has none
+func kubeconfigCert(data []byte) (*x509.Certificate, error) {
+	cfg, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode")
+	}
+	for _, ai := range cfg.AuthInfos {
+		if len(ai.ClientCertificateData) > 0 {
+			return parseCert(ai.ClientCertificateData)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestCertExpirations(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	if _, err := generateSharedCACerts(); err != nil {
+		t.Fatalf("generateSharedCACerts: %v", err)
+	}
+	client := localpath.ClientCert("p1")
+	if err := util.GenerateSignedCert(client, localpath.ClientKey("p1"), "minikube-user", nil, nil, localpath.CACert(), filepath.Join(tempDir, "ca.key"), util.DefaultCertOptions); err != nil {
+		t.Fatalf("GenerateSignedCert: %v", err)
+	}
+
+	ces, err := HostCertExpirations(config.KubernetesConfig{ClusterName: "p1"})
+	if err != nil {
+		t.Fatalf("HostCertExpirations: %v", err)
+	}
+	got := []string{}
+	for _, ce := range ces {
+		got = append(got, fmt.Sprintf("%s %s %s %v", ce.Location, ce.Name, ce.ManagedBy, ce.CA))
+	}
+	want := []string{"host ca.crt minikube true", "host proxy-client-ca.crt minikube true", "host profiles/p1/client.crt minikube false"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("HostCertExpirations() mismatch (-want +got):\n%s", diff)
+	}
+	if d := time.Until(ces[2].NotAfter); d < 364*24*time.Hour || d > 366*24*time.Hour {
+		t.Errorf("client certificate expires in %s, want a year", d)
+	}
+
+	ca, err := ioutil.ReadFile(localpath.CACert())
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	kubeconfig := fmt.Sprintf("apiVersion: v1\nkind: Config\nusers:\n- name: kubernetes-admin\n  user:\n    client-certificate-data: %s\n", base64.StdEncoding.EncodeToString(ca))
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo find /var/lib/minikube/certs -name *.crt":      "/var/lib/minikube/certs/etcd/ca.crt\n/var/lib/minikube/certs/apiserver.crt\n",
+		"sudo cat /var/lib/minikube/certs/etcd/ca.crt":       string(ca),
+		"sudo cat /var/lib/minikube/certs/apiserver.crt":     string(ca),
+		"sudo find /etc/kubernetes -maxdepth 1 -name *.conf": "/etc/kubernetes/admin.conf\n/etc/kubernetes/kubelet.conf\n",
+		"sudo cat /etc/kubernetes/admin.conf":                kubeconfig,
+	})
+	ces, err = NodeCertExpirations(f, "m01")
+	if err != nil {
+		t.Fatalf("NodeCertExpirations: %v", err)
+	}
+	got = []string{}
+	for _, ce := range ces {
+		got = append(got, fmt.Sprintf("%s %s %s", ce.Location, ce.Name, ce.ManagedBy))
+	}
+	want = []string{"m01 apiserver.crt minikube", "m01 etcd/ca.crt kubeadm", "m01 admin.conf kubeadm"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NodeCertExpirations() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRemoveProfileCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	profilePath := localpath.Profile("p1")
+	if err := os.MkdirAll(profilePath, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	removed := []string{"client.crt", "client.key", "apiserver.crt", "apiserver.crt.0a1b2c3d", "apiserver.key.0a1b2c3d", "proxy-client.key"}
+	kept := []string{"config.json", "id_rsa"}
+	for _, name := range append(removed, kept...) {
+		if err := ioutil.WriteFile(filepath.Join(profilePath, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := ioutil.WriteFile(localpath.CACert(), []byte("ca"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if err := RemoveProfileCerts("p1"); err != nil {
+		t.Fatalf("RemoveProfileCerts: %v", err)
+	}
+	for _, name := range removed {
+		if _, err := os.Stat(filepath.Join(profilePath, name)); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed: %v", name, err)
+		}
+	}
+	for _, p := range []string{filepath.Join(profilePath, kept[0]), filepath.Join(profilePath, kept[1]), localpath.CACert()} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s was removed: %v", p, err)
+		}
+	}
+}
@@ -147,3 +147,11 @@ func KindNet(repo string) string {
 	}
 	return path.Join(repo, "kindnetd:0.5.4")
 }
+
+// KubeVIP returns the image used to announce the virtual IP of a highly available control plane
+func KubeVIP(repo string) string {
+	if repo == "" {
+		repo = "ghcr.io/kube-vip"
+	}
+	return path.Join(repo, "kube-vip:v0.4.0")
+}
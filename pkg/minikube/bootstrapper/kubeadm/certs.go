@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+// etcdHealthTimeout is how long the etcd member of a restarted control plane has to become healthy
+const etcdHealthTimeout = 2 * time.Minute
+
+// controlPlaneComponents are the static pods which load the certificates of a control plane on start
+var controlPlaneComponents = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"}
+
+// RotateCerts renews the certificates of a node, and restarts its control plane to load them, waiting only for etcd.
+// The certificates minikube signs are regenerated only if they were removed from the host first, see bootstrapper.RemoveProfileCerts.
+func (k *Bootstrapper) RotateCerts(cfg config.ClusterConfig, n config.Node) error {
+	start := time.Now()
+	klog.Infof("RotateCerts: %s", n.Name)
+	defer func() {
+		klog.Infof("RotateCerts complete in %s", time.Since(start))
+	}()
+
+	if n.ControlPlane {
+		// A control plane which joined the cluster only has the configuration minikube generated for it
+		conf := bsutil.KubeadmYamlPath
+		if _, err := k.c.RunCmd(exec.Command("sudo", "/bin/bash", "-c", fmt.Sprintf("test -f %[1]s || cp %[1]s.new %[1]s", conf))); err != nil {
+			return errors.Wrap(err, "cp")
+		}
+		version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+		if err != nil {
+			return errors.Wrap(err, "parsing Kubernetes version")
+		}
+		// kubeadm renews the apiserver certificate as well, so it runs before minikube copies its own
+		if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", bsutil.RenewCertsCmd(version, conf))); err != nil {
+			return errors.Wrap(err, "kubeadm certs renew")
+		}
+	}
+
+	if err := k.SetupCerts(cfg.KubernetesConfig, n); err != nil {
+		return errors.Wrap(err, "setup certs")
+	}
+
+	if !n.ControlPlane {
+		return nil
+	}
+	return k.restartControlPlaneComponents(cfg)
+}
+
+// restartControlPlaneComponents stops the containers of the control plane, for the kubelet to start them again.
+// It waits for etcd to be healthy again, so that restarting the control planes one after the other keeps the quorum of etcd.
+func (k *Bootstrapper) restartControlPlaneComponents(cfg config.ClusterConfig) error {
+	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c, Socket: cfg.KubernetesConfig.CRISocket})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	for _, name := range controlPlaneComponents {
+		ids, err := cr.ListContainers(cruntime.ListOptions{Name: name})
+		if err != nil {
+			return errors.Wrapf(err, "list %s", name)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if err := cr.StopContainers(ids); err != nil {
+			return errors.Wrapf(err, "stop %s", name)
+		}
+	}
+	return k.waitForEtcd(cfg, cr, etcdHealthTimeout)
+}
+
+// waitForEtcd waits for the etcd member of the node to report that it is healthy
+func (k *Bootstrapper) waitForEtcd(cfg config.ClusterConfig, cr cruntime.Manager, timeout time.Duration) error {
+	kv, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parse kubernetes version")
+	}
+	// etcdctl of etcd 3.3, before Kubernetes v1.17, defaults to the v2 API, which has no endpoint health command
+	etcdctl := func(id string) []string { return []string{"crictl", "exec", id, "etcdctl"} }
+	if kv.LT(semver.MustParse("1.17.0")) {
+		etcdctl = func(id string) []string { return []string{"crictl", "exec", id, "env", "ETCDCTL_API=3", "etcdctl"} }
+	}
+	if cr.Name() == "Docker" {
+		etcdctl = func(id string) []string { return []string{"docker", "exec", "-e", "ETCDCTL_API=3", id, "etcdctl"} }
+	}
+	certs := path.Join(vmpath.GuestKubernetesCertsDir, "etcd")
+
+	healthy := func() error {
+		ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Running, Name: "etcd"})
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return fmt.Errorf("etcd is not running")
+		}
+		c := exec.Command("sudo", append(etcdctl(ids[0]), "--endpoints=https://127.0.0.1:2379",
+			"--cacert="+path.Join(certs, "ca.crt"), "--cert="+path.Join(certs, "healthcheck-client.crt"), "--key="+path.Join(certs, "healthcheck-client.key"),
+			"endpoint", "health")...)
+		_, err = k.c.RunCmd(c)
+		return err
+	}
+	if err := retry.Expo(healthy, time.Second, timeout); err != nil {
+		return errors.Wrap(err, "etcd health")
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"os/exec"
 	"path"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -230,6 +231,17 @@ func (k *Bootstrapper) init(cfg config.ClusterConfig) error {
 		return errors.Wrap(err, "clearing stale configs")
 	}
 
+	// kubeadm waits for the API server on the virtual IP, so kube-vip must start along with the control plane
+	if config.IsHA(cfg) {
+		cp, err := config.PrimaryControlPlane(&cfg)
+		if err != nil {
+			return errors.Wrap(err, "control plane")
+		}
+		if err := k.installKubeVIP(cfg, cp); err != nil {
+			return err
+		}
+	}
+
 	conf := bsutil.KubeadmYamlPath
 	ctx, cancel := context.WithTimeout(context.Background(), initTimeoutMinutes*time.Minute)
 	defer cancel()
@@ -603,7 +615,7 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 	}
 
 	// Save the costly tax of reinstalling Kubernetes if the only issue is a missing kube context
-	_, err = kubeconfig.UpdateEndpoint(cfg.Name, hostname, port, kubeconfig.PathFromEnv(), kubeconfig.NewExtension())
+	_, err = kubeconfig.UpdateEndpoint(cfg.Name, hostname, port, kubeconfig.PathFor(cfg.Name), bootstrapper.ClusterCACert(cfg.KubernetesConfig), kubeconfig.NewExtension())
 	if err != nil {
 		klog.Warningf("unable to update kubeconfig (cluster will likely require a reset): %v", err)
 	}
@@ -708,8 +720,19 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 		klog.Infof("JoinCluster complete in %s", time.Since(start))
 	}()
 
+	// A control plane which was already joined keeps its etcd member, which must not be reset: other members may need it for quorum
+	if n.ControlPlane {
+		if _, err := k.c.RunCmd(exec.Command("sudo", "test", "-d", path.Join(bsutil.EtcdDataDir(), "member"))); err == nil {
+			klog.Infof("%s is already a member of the control plane, restarting it", n.Name)
+			return k.RestartControlPlaneNode(cc, n)
+		}
+	}
+
 	// Join the master by specifying its token
 	joinCmd = fmt.Sprintf("%s --node-name=%s", joinCmd, config.MachineName(cc, n))
+	if n.ControlPlane {
+		joinCmd = fmt.Sprintf("%s --control-plane --apiserver-advertise-address=%s --apiserver-bind-port=%d", joinCmd, n.IP, n.Port)
+	}
 
 	join := func() error {
 		// reset first to clear any possibly existing state
@@ -736,11 +759,52 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 		return errors.Wrap(err, "starting kubelet")
 	}
 
+	if n.ControlPlane && config.IsHA(cc) {
+		return k.installKubeVIP(cc, n)
+	}
 	return nil
 }
 
-// GenerateToken creates a token and returns the appropriate kubeadm join command to run, or the already existing token
-func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
+// RestartControlPlaneNode restarts the static pods of a control plane node which is not the primary one, without waiting for them.
+// The etcd member of the node resumes from its data directory, so the members can regain quorum before the API server is waited for.
+func (k *Bootstrapper) RestartControlPlaneNode(cfg config.ClusterConfig, n config.Node) error {
+	start := time.Now()
+	klog.Infof("RestartControlPlaneNode: %s", n.Name)
+	defer func() {
+		klog.Infof("RestartControlPlaneNode complete in %s", time.Since(start))
+	}()
+
+	conf := bsutil.KubeadmYamlPath
+	if _, err := k.c.RunCmd(exec.Command("sudo", "cp", conf+".new", conf)); err != nil {
+		return errors.Wrap(err, "cp")
+	}
+
+	baseCmd := fmt.Sprintf("%s init", bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion))
+	cmds := []string{
+		fmt.Sprintf("%s phase certs all --config %s", baseCmd, conf),
+		fmt.Sprintf("%s phase kubeconfig all --config %s", baseCmd, conf),
+		fmt.Sprintf("%s phase kubelet-start --config %s", baseCmd, conf),
+		fmt.Sprintf("%s phase control-plane all --config %s", baseCmd, conf),
+		fmt.Sprintf("%s phase etcd local --config %s", baseCmd, conf),
+	}
+	for _, c := range cmds {
+		if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", c)); err != nil {
+			return errors.Wrap(err, "run")
+		}
+	}
+
+	if config.IsHA(cfg) {
+		return k.installKubeVIP(cfg, n)
+	}
+	return nil
+}
+
+// GenerateToken creates a token and returns the appropriate kubeadm join command for the node to run, or the already existing token
+func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig, n config.Node) (string, error) {
+	if err := k.prepareKubeletSkew(cc, n); err != nil {
+		return "", errors.Wrap(err, "kubelet version skew")
+	}
+
 	// Take that generated token and use it to get a kubeadm join command
 	tokenCmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s token create --print-join-command --ttl=0", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion)))
 	r, err := k.c.RunCmd(tokenCmd)
@@ -749,7 +813,8 @@ func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
 	}
 
 	joinCmd := r.Stdout.String()
-	joinCmd = strings.Replace(joinCmd, "kubeadm", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion), 1)
+	// The node joins with the kubeadm of the version it runs
+	joinCmd = strings.Replace(joinCmd, "kubeadm", bsutil.InvokeKubeadm(config.NodeKubernetesVersion(cc, n)), 1)
 	joinCmd = fmt.Sprintf("%s --ignore-preflight-errors=all", strings.TrimSpace(joinCmd))
 	if cc.KubernetesConfig.CRISocket != "" {
 		joinCmd = fmt.Sprintf("%s --cri-socket %s", joinCmd, cc.KubernetesConfig.CRISocket)
@@ -758,6 +823,27 @@ func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
 	return joinCmd, nil
 }
 
+// prepareKubeletSkew lets a node which runs an older minor version than the cluster read its kubelet configuration when joining
+func (k *Bootstrapper) prepareKubeletSkew(cc config.ClusterConfig, n config.Node) error {
+	cv, err := util.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing cluster version")
+	}
+	nv, err := util.ParseKubernetesVersion(config.NodeKubernetesVersion(cc, n))
+	if err != nil {
+		return errors.Wrap(err, "parsing node version")
+	}
+	if nv.Major == cv.Major && nv.Minor == cv.Minor {
+		return nil
+	}
+
+	kubectl := fmt.Sprintf("sudo KUBECONFIG=%s %s", path.Join(vmpath.GuestPersistentDir, "kubeconfig"), kubectlPath(cc))
+	if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", bsutil.KubeletConfigMapCmd(cv, nv, kubectl))); err != nil {
+		return errors.Wrapf(err, "creating %s", bsutil.KubeletConfigMapName(nv))
+	}
+	return nil
+}
+
 // DeleteCluster removes the components that were started earlier
 func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
 	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c, Socket: k8s.CRISocket})
@@ -881,6 +967,19 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		files = append(files, assets.NewMemoryAssetTarget(kubeadmCfg, bsutil.KubeadmYamlPath+".new", "0640"))
 	}
 
+	if n.ControlPlane && config.IsHA(cfg) {
+		kubeVIP, err := k.kubeVIPManifest(cfg, n)
+		if err != nil {
+			return errors.Wrap(err, "kube-vip")
+		}
+		files = append(files, kubeVIP)
+	}
+
+	// The API server reads the audit policy of the idle auto-stop when it starts
+	if n.ControlPlane && cfg.AutoStopIdle > 0 {
+		files = append(files, assets.NewMemoryAssetTarget(bsutil.IdleStopAuditPolicy(), bsutil.IdleStopAuditPolicyPath, "0644"))
+	}
+
 	// Installs compatibility shims for non-systemd environments
 	kubeletPath := path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubelet")
 	shims, err := sm.GenerateInitShim("kubelet", kubeletPath, bsutil.KubeletSystemdConfFile)
@@ -898,13 +997,44 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		return errors.Wrap(err, "control plane")
 	}
 
-	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cp.IP)); err != nil {
+	// The control plane of a highly available cluster is reached through its virtual IP
+	cpIP := cp.IP
+	if config.IsHA(cfg) {
+		cpIP = cfg.KubernetesConfig.APIServerHAVIP
+	}
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cpIP)); err != nil {
 		return errors.Wrap(err, "host alias")
 	}
 
 	return nil
 }
 
+// kubeVIPManifest returns the kube-vip static pod for a control plane node, announcing the virtual IP on the interface which holds the node IP
+func (k *Bootstrapper) kubeVIPManifest(cfg config.ClusterConfig, n config.Node) (assets.CopyableFile, error) {
+	iface := "eth0"
+	rr, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("ip -o -4 addr show to %s | awk '{print $2}'", n.IP)))
+	if err == nil && strings.TrimSpace(rr.Stdout.String()) != "" {
+		iface = strings.TrimSpace(rr.Stdout.String())
+	} else {
+		klog.Warningf("unable to find the interface of %s, assuming %s: %v", n.IP, iface, err)
+	}
+
+	manifest, err := bsutil.NewKubeVIPManifest(cfg, iface)
+	if err != nil {
+		return nil, err
+	}
+	return assets.NewMemoryAssetTarget(manifest, bsutil.KubeVIPManifestPath, "0600"), nil
+}
+
+// installKubeVIP writes the kube-vip static pod, which kubeadm reset removes along with the other manifests
+func (k *Bootstrapper) installKubeVIP(cfg config.ClusterConfig, n config.Node) error {
+	f, err := k.kubeVIPManifest(cfg, n)
+	if err != nil {
+		return errors.Wrap(err, "kube-vip")
+	}
+	return bsutil.CopyFiles(k.c, []assets.CopyableFile{f})
+}
+
 // kubectlPath returns the path to the kubelet
 func kubectlPath(cfg config.ClusterConfig) string {
 	return path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubectl")
@@ -936,6 +1066,47 @@ func (k *Bootstrapper) applyNodeLabels(cfg config.ClusterConfig) error {
 	return nil
 }
 
+// LabelAndTaintNode applies the labels and taints requested for a node
+func (k *Bootstrapper) LabelAndTaintNode(cfg config.ClusterConfig, n config.Node) error {
+	name := bsutil.KubeNodeName(cfg, n)
+	kubeconfig := fmt.Sprintf("--kubeconfig=%s", path.Join(vmpath.GuestPersistentDir, "kubeconfig"))
+
+	var cmds [][]string
+	if len(n.Labels) > 0 {
+		keys := make([]string, 0, len(n.Labels))
+		for key := range n.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		args := []string{kubectlPath(cfg), "label", "nodes", name}
+		for _, key := range keys {
+			args = append(args, key+"="+n.Labels[key])
+		}
+		cmds = append(cmds, append(args, "--overwrite", kubeconfig))
+	}
+	if len(n.Taints) > 0 {
+		args := append([]string{kubectlPath(cfg), "taint", "nodes", name}, n.Taints...)
+		cmds = append(cmds, append(args, "--overwrite", kubeconfig))
+	}
+
+	for _, args := range cmds {
+		ctx, cancel := context.WithTimeout(context.Background(), applyTimeoutSeconds*time.Second)
+		// example:
+		// sudo /var/lib/minikube/binaries/<version>/kubectl taint nodes minikube-m02 gpu=true:NoSchedule --overwrite --kubeconfig=/var/lib/minikube/kubeconfig
+		_, err := k.c.RunCmd(exec.CommandContext(ctx, "sudo", args...))
+		timedOut := ctx.Err() == context.DeadlineExceeded
+		cancel()
+		if err != nil {
+			if timedOut {
+				return errors.Wrapf(err, "timeout running kubectl %s", args[1])
+			}
+			return errors.Wrapf(err, "kubectl %s", args[1])
+		}
+	}
+	return nil
+}
+
 // elevateKubeSystemPrivileges gives the kube-system service account cluster admin privileges to work with RBAC.
 func (k *Bootstrapper) elevateKubeSystemPrivileges(cfg config.ClusterConfig) error {
 	start := time.Now()
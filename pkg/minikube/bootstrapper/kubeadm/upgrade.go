@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"os/exec"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	kconst "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+// kubectl runs a kubectl command against the cluster using the in-VM admin kubeconfig
+func (k *Bootstrapper) kubectl(cfg config.ClusterConfig, args ...string) error {
+	args = append([]string{"KUBECONFIG=" + path.Join(vmpath.GuestPersistentDir, "kubeconfig"), kubectlPath(cfg)}, args...)
+	_, err := k.c.RunCmd(exec.Command("sudo", args...))
+	return err
+}
+
+// DrainNode evicts all workloads from a node and marks it unschedulable
+func (k *Bootstrapper) DrainNode(cfg config.ClusterConfig, n config.Node) error {
+	version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing Kubernetes version")
+	}
+	if err := k.kubectl(cfg, bsutil.DrainArgs(version, bsutil.KubeNodeName(cfg, n))...); err != nil {
+		return errors.Wrap(err, "drain")
+	}
+	return nil
+}
+
+// UncordonNode marks a node schedulable again
+func (k *Bootstrapper) UncordonNode(cfg config.ClusterConfig, n config.Node) error {
+	if err := k.kubectl(cfg, "uncordon", bsutil.KubeNodeName(cfg, n)); err != nil {
+		return errors.Wrap(err, "uncordon")
+	}
+	return nil
+}
+
+// UpgradeControlPlane upgrades a running control plane from the version recorded for the node to the cluster version.
+// It must run before UpdateCluster, which would replace the old kubelet the control plane is upgraded with.
+func (k *Bootstrapper) UpgradeControlPlane(cfg config.ClusterConfig, n config.Node) error {
+	start := time.Now()
+	klog.Infof("UpgradeControlPlane: %s -> %s", n.KubernetesVersion, cfg.KubernetesConfig.KubernetesVersion)
+	defer func() {
+		klog.Infof("UpgradeControlPlane complete in %s", time.Since(start))
+	}()
+
+	version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing Kubernetes version")
+	}
+
+	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: k.c, Socket: cfg.KubernetesConfig.CRISocket})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	// As with kubeadm, the old kubelet runs the control plane until it is upgraded: a newer kubelet is outside the supported skew
+	sm := sysinit.New(k.c)
+	if err := sm.Start("kubelet"); err != nil {
+		return errors.Wrap(err, "starting kubelet")
+	}
+
+	// The binaries of each version are kept apart, so the new kubeadm is installed without stopping the old kubelet
+	if err := bsutil.TransferBinaries(cfg.KubernetesConfig, k.c, nil); err != nil {
+		return errors.Wrap(err, "downloading binaries")
+	}
+	kubeadmCfg, err := bsutil.GenerateKubeadmYAML(cfg, n, cr)
+	if err != nil {
+		return errors.Wrap(err, "generating kubeadm cfg")
+	}
+	conf := bsutil.KubeadmYamlPath
+	if err := bsutil.CopyFiles(k.c, []assets.CopyableFile{assets.NewMemoryAssetTarget(kubeadmCfg, conf+".new", "0640")}); err != nil {
+		return errors.Wrap(err, "copy kubeadm cfg")
+	}
+
+	hostname, _, port, err := driver.ControlPlaneEndpoint(&cfg, &n, cfg.Driver)
+	if err != nil {
+		return errors.Wrap(err, "control plane")
+	}
+	if _, err := kubeconfig.UpdateEndpoint(cfg.Name, hostname, port, kubeconfig.PathFor(cfg.Name), bootstrapper.ClusterCACert(cfg.KubernetesConfig), kubeconfig.NewExtension()); err != nil {
+		klog.Warningf("unable to update kubeconfig: %v", err)
+	}
+
+	client, err := k.client(hostname, port)
+	if err != nil {
+		return errors.Wrap(err, "getting k8s client")
+	}
+
+	old := cfg
+	old.KubernetesConfig.KubernetesVersion = n.KubernetesVersion
+	if err := kverify.WaitForHealthyAPIServer(cr, k, old, k.c, client, time.Now(), hostname, port, kconst.DefaultControlPlaneTimeout); err != nil {
+		return errors.Wrap(err, "apiserver health")
+	}
+
+	// Draining is best effort: the upgrade restarts the control plane whether or not workloads were evicted
+	if err := k.DrainNode(cfg, n); err != nil {
+		klog.Warningf("unable to drain %s, continuing anyway: %v", bsutil.KubeNodeName(cfg, n), err)
+	}
+
+	if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", bsutil.UpgradeApplyCmd(version, conf+".new"))); err != nil {
+		return errors.Wrap(err, "kubeadm upgrade apply")
+	}
+
+	if _, err := k.c.RunCmd(exec.Command("sudo", "cp", conf+".new", conf)); err != nil {
+		return errors.Wrap(err, "cp")
+	}
+
+	// Only the upgraded control plane is run by the new kubelet
+	if err := k.UpdateNode(cfg, n, cr); err != nil {
+		return errors.Wrap(err, "updating kubelet")
+	}
+	if err := sm.Restart("kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+
+	return k.UncordonNode(cfg, n)
+}
+
+// UpgradeNode upgrades the kubelet configuration of a node whose binaries were already swapped by UpdateNode
+func (k *Bootstrapper) UpgradeNode(cfg config.ClusterConfig, n config.Node) error {
+	start := time.Now()
+	klog.Infof("UpgradeNode: %s %s -> %s", n.Name, n.KubernetesVersion, cfg.KubernetesConfig.KubernetesVersion)
+	defer func() {
+		klog.Infof("UpgradeNode complete in %s", time.Since(start))
+	}()
+
+	version, err := util.ParseKubernetesVersion(cfg.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing Kubernetes version")
+	}
+
+	if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", bsutil.UpgradeNodeCmd(version))); err != nil {
+		return errors.Wrap(err, "kubeadm upgrade node")
+	}
+
+	if err := sysinit.New(k.c).Restart("kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+	return nil
+}
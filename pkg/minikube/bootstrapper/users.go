@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+// userNameRe matches the names of users, which name their certificate files
+var userNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// ErrUserNotFound is returned when a user has no certificate in its cluster
+var ErrUserNotFound = errors.New("user not found")
+
+// User is a client identity whose certificate is signed by the CA of a cluster
+type User struct {
+	Name     string    `json:"name"`
+	Groups   []string  `json:"groups"`
+	Serial   string    `json:"serial"`
+	NotAfter time.Time `json:"notAfter"`
+	Revoked  bool      `json:"revoked"`
+}
+
+// UserCert returns the path of the client certificate of a user of a cluster
+func UserCert(clusterName, name string) string {
+	return filepath.Join(localpath.Profile(clusterName), "users", name+".crt")
+}
+
+// UserKey returns the path of the client key of a user of a cluster
+func UserKey(clusterName, name string) string {
+	return filepath.Join(localpath.Profile(clusterName), "users", name+".key")
+}
+
+// denylistPath returns the path of the users whose certificates were revoked
+func denylistPath(clusterName string) string {
+	return filepath.Join(localpath.Profile(clusterName), "revoked-users.json")
+}
+
+// AddUser signs a client certificate for a user in groups with the CA of a cluster.
+// The certificate is valid for ttl, or as long as the other certificates of the cluster if ttl is 0.
+func AddUser(k8s config.KubernetesConfig, name string, groups []string, ttl time.Duration) (User, error) {
+	if !userNameRe.MatchString(name) {
+		return User{}, fmt.Errorf("invalid user name %q: it must start with a letter or digit, and contain only letters, digits, '.', '_' and '-'", name)
+	}
+	certPath := UserCert(k8s.ClusterName, name)
+	if canRead(certPath) {
+		return User{}, fmt.Errorf("user %q already exists", name)
+	}
+
+	opts := certOptions(k8s)
+	opts.Groups = groups
+	if ttl > 0 {
+		opts.Validity = ttl
+	}
+	ccs := caCertPaths(k8s)
+	klog.Infof("generating the client cert of user %s in groups %v: %s", name, groups, certPath)
+	if err := util.GenerateSignedCert(certPath, UserKey(k8s.ClusterName, name), name, nil, nil, ccs.caCert, ccs.caKey, opts); err != nil {
+		return User{}, errors.Wrapf(err, "generate signed cert for %q", name)
+	}
+	return readUser(certPath)
+}
+
+// Users returns the users of a cluster, sorted by name: those with a certificate, and those which were revoked
+func Users(clusterName string) ([]User, error) {
+	users, err := revokedUsers(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := filepath.Glob(UserCert(clusterName, "*"))
+	if err != nil {
+		return nil, errors.Wrap(err, "glob")
+	}
+	for _, c := range certs {
+		u, err := readUser(c)
+		if err != nil {
+			return nil, err
+		}
+		// a certificate which was revoked, but then restored, is already listed from the denylist
+		if isRevoked(users, u.Serial) {
+			klog.Warningf("the certificate of user %s was revoked: %s", u.Name, c)
+			continue
+		}
+		users = append(users, u)
+	}
+	sort.SliceStable(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+	return users, nil
+}
+
+// RevokeUser deletes the certificate and key of a user, and records its serial in the denylist of the cluster
+func RevokeUser(clusterName, name string) (User, error) {
+	certPath := UserCert(clusterName, name)
+	if !canRead(certPath) {
+		return User{}, errors.Wrap(ErrUserNotFound, name)
+	}
+	u, err := readUser(certPath)
+	if err != nil {
+		return u, err
+	}
+	u.Revoked = true
+
+	revoked, err := revokedUsers(clusterName)
+	if err != nil {
+		return u, err
+	}
+	data, err := json.MarshalIndent(append(revoked, u), "", "    ")
+	if err != nil {
+		return u, errors.Wrap(err, "marshal")
+	}
+	if err := lock.WriteFile(denylistPath(clusterName), data, 0644); err != nil {
+		return u, errors.Wrap(err, "writing denylist")
+	}
+
+	for _, p := range []string{certPath, UserKey(clusterName, name)} {
+		klog.Infof("removing %s", p)
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return u, errors.Wrapf(err, "remove %s", p)
+		}
+	}
+	return u, nil
+}
+
+// isRevoked returns whether the serial of a certificate is in a denylist
+func isRevoked(revoked []User, serial string) bool {
+	for _, u := range revoked {
+		if strings.EqualFold(u.Serial, serial) {
+			return true
+		}
+	}
+	return false
+}
+
+// revokedUsers returns the users in the denylist of a cluster
+func revokedUsers(clusterName string) ([]User, error) {
+	data, err := ioutil.ReadFile(denylistPath(clusterName))
+	if os.IsNotExist(err) {
+		return []User{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading denylist")
+	}
+	users := []User{}
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal %s", denylistPath(clusterName))
+	}
+	return users, nil
+}
+
+// readUser returns the user a client certificate was signed for
+func readUser(certPath string) (User, error) {
+	data, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return User{}, errors.Wrapf(err, "reading %s", certPath)
+	}
+	c, err := parseCert(data)
+	if err != nil {
+		return User{}, errors.Wrapf(err, "parse %s", certPath)
+	}
+	groups := c.Subject.Organization
+	if groups == nil {
+		groups = []string{}
+	}
+	return User{
+		Name:     c.Subject.CommonName,
+		Groups:   groups,
+		Serial:   fmt.Sprintf("%x", c.SerialNumber),
+		NotAfter: c.NotAfter,
+	}, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestUsers(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+
+	if _, err := generateSharedCACerts(); err != nil {
+		t.Fatalf("generateSharedCACerts: %v", err)
+	}
+	k8s := config.KubernetesConfig{ClusterName: "p1"}
+
+	alice, err := AddUser(k8s, "alice", []string{"dev", "ops"}, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	if d := time.Until(alice.NotAfter); d > 2*time.Hour || d < time.Hour {
+		t.Errorf("the certificate of alice expires in %s, want 2h", d)
+	}
+	if _, err := AddUser(k8s, "bob", nil, 0); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	if _, err := AddUser(k8s, "alice", nil, 0); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("AddUser() of an existing user = %v, want an error", err)
+	}
+	if _, err := AddUser(k8s, "../alice", nil, 0); err == nil {
+		t.Errorf("AddUser() of an invalid name should have returned an error")
+	}
+
+	revoked, err := RevokeUser("p1", "alice")
+	if err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+	if revoked.Serial != alice.Serial {
+		t.Errorf("RevokeUser() revoked serial %s, want %s", revoked.Serial, alice.Serial)
+	}
+	if _, err := RevokeUser("p1", "alice"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("RevokeUser() of a revoked user = %v, want %v", err, ErrUserNotFound)
+	}
+	if !isRevoked([]User{revoked}, strings.ToUpper(alice.Serial)) {
+		t.Errorf("isRevoked(%s) = false, want true", alice.Serial)
+	}
+
+	// a revoked certificate which is restored is listed once, as revoked
+	if _, err := AddUser(k8s, "carol", nil, 0); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	crt, err := ioutil.ReadFile(UserCert("p1", "carol"))
+	if err != nil {
+		t.Fatalf("read cert: %v", err)
+	}
+	if _, err := RevokeUser("p1", "carol"); err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+	if err := ioutil.WriteFile(UserCert("p1", "carol"), crt, 0644); err != nil {
+		t.Fatalf("restore cert: %v", err)
+	}
+
+	users, err := Users("p1")
+	if err != nil {
+		t.Fatalf("Users: %v", err)
+	}
+	got := []string{}
+	for _, u := range users {
+		got = append(got, fmt.Sprintf("%s %v %v", u.Name, u.Groups, u.Revoked))
+	}
+	want := []string{"alice [dev ops] true", "bob [] false", "carol [] true"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Users() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle creates and extracts offline bundles, which hold everything needed to start a cluster without network access
+package bundle
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// manifestFile is the file within a bundle describing the bundle
+const manifestFile = "bundle.json"
+
+// Manifest describes the cluster an offline bundle was created for
+type Manifest struct {
+	MinikubeVersion   string
+	Driver            string
+	ContainerRuntime  string
+	KubernetesVersion string
+	BaseImage         string   `json:",omitempty"` // kicbase image, only for KIC
+	Addons            []string `json:",omitempty"`
+	Images            []string `json:",omitempty"` // images to load into every node from the image cache
+	Files             []string // bundled files, relative to the minikube home
+}
+
+// Supported returns whether offline bundles are supported for a driver
+func Supported(drvName string) bool {
+	// loading the kicbase image from the image cache is not implemented for podman yet
+	return drvName != driver.Podman && !driver.IsSSH(drvName)
+}
+
+// Cache downloads everything the cluster described by the manifest needs into the caches of the minikube home,
+// and records the cached files and images in the manifest
+func Cache(m *Manifest) error {
+	if !Supported(m.Driver) {
+		return fmt.Errorf("the %s driver does not support offline bundles", m.Driver)
+	}
+	var files []string
+
+	switch {
+	case driver.IsVM(m.Driver):
+		url, err := download.ISO(download.DefaultISOURLs(), false)
+		if err != nil {
+			return errors.Wrap(err, "iso")
+		}
+		files = append(files, filepath.FromSlash(strings.TrimPrefix(download.LocalISOResource(url), "file://")))
+	case driver.IsKIC(m.Driver):
+		m.BaseImage = kic.BaseImage
+		if err := image.SaveToDir([]string{m.BaseImage}, constants.ImageCacheDir); err != nil {
+			return errors.Wrap(err, "base image")
+		}
+		files = append(files, image.CachePath(constants.ImageCacheDir, m.BaseImage))
+	}
+
+	if download.PreloadExists(m.KubernetesVersion, m.ContainerRuntime, true) {
+		if err := download.Preload(m.KubernetesVersion, m.ContainerRuntime, true); err != nil {
+			return errors.Wrap(err, "preload")
+		}
+		files = append(files, download.TarballPath(m.KubernetesVersion, m.ContainerRuntime), download.PreloadChecksumPath(m.KubernetesVersion, m.ContainerRuntime))
+	} else {
+		imgs, err := bootstrapper.GetCachedImageList("", m.KubernetesVersion, bootstrapper.Kubeadm)
+		if err != nil {
+			return errors.Wrap(err, "kubernetes images")
+		}
+		m.Images = append(m.Images, imgs...)
+	}
+
+	if err := machine.CacheBinariesForBootstrapper(m.KubernetesVersion, bootstrapper.Kubeadm); err != nil {
+		return errors.Wrap(err, "binaries")
+	}
+	for _, bin := range bootstrapper.GetCachedBinaryList(bootstrapper.Kubeadm) {
+		files = append(files, localpath.MakeMiniPath("cache", "linux", m.KubernetesVersion, bin))
+	}
+	kubectl := "kubectl"
+	if runtime.GOOS == "windows" {
+		kubectl = "kubectl.exe"
+	}
+	p, err := download.Binary(kubectl, m.KubernetesVersion, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return errors.Wrap(err, "kubectl")
+	}
+	files = append(files, p)
+
+	// kindnet is the default CNI for multi-node clusters and for KIC with other runtimes than docker
+	m.Images = append(m.Images, images.KindNet(""))
+	addonImgs, err := addonImages(m)
+	if err != nil {
+		return err
+	}
+	m.Images = append(m.Images, addonImgs...)
+	m.Images = dedupe(m.Images)
+	if err := image.SaveToDir(m.Images, constants.ImageCacheDir); err != nil {
+		return errors.Wrap(err, "images")
+	}
+	for _, img := range m.Images {
+		files = append(files, image.CachePath(constants.ImageCacheDir, img))
+	}
+
+	m.Files = nil
+	for _, f := range dedupe(files) {
+		rel, err := filepath.Rel(localpath.MiniPath(), f)
+		if err != nil {
+			return errors.Wrapf(err, "relative path of %s", f)
+		}
+		m.Files = append(m.Files, filepath.ToSlash(rel))
+	}
+	return nil
+}
+
+// addonImages returns the images of the addons of the manifest, and of the addons enabled by default
+func addonImages(m *Manifest) ([]string, error) {
+	enabled := map[string]bool{}
+	for _, name := range m.Addons {
+		if _, ok := assets.Addons[name]; !ok {
+			return nil, fmt.Errorf("unknown addon %q", name)
+		}
+		enabled[name] = true
+	}
+	var imgs []string
+	for name, addon := range assets.Addons {
+		if !enabled[name] && !addon.IsEnabled(&config.ClusterConfig{}) {
+			continue
+		}
+		ai, err := addon.Images(config.KubernetesConfig{KubernetesVersion: m.KubernetesVersion, ContainerRuntime: m.ContainerRuntime})
+		if err != nil {
+			return nil, errors.Wrapf(err, "images of addon %s", name)
+		}
+		imgs = append(imgs, ai...)
+	}
+	return imgs, nil
+}
+
+// dedupe returns the sorted unique entries of a list
+func dedupe(list []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Create writes a bundle holding the manifest and its files to dst
+func Create(dst string, m *Manifest) (err error) {
+	f, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	tw := tar.NewWriter(f)
+	data, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "marshal manifest")
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestFile, Mode: 0o644, Size: int64(len(data))}); err != nil {
+		return errors.Wrap(err, "manifest header")
+	}
+	if _, err := tw.Write(data); err != nil {
+		return errors.Wrap(err, "write manifest")
+	}
+	for _, name := range m.Files {
+		if err := addFile(tw, name); err != nil {
+			return errors.Wrapf(err, "add %s", name)
+		}
+	}
+	return tw.Close()
+}
+
+// addFile adds a file of the minikube home to a bundle
+func addFile(tw *tar.Writer, name string) error {
+	src, err := os.Open(filepath.Join(localpath.MiniPath(), filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	fi, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, src)
+	return err
+}
+
+// validPath returns whether a bundle entry may be extracted: only relative paths within the cache are
+func validPath(name string) bool {
+	if strings.Contains(name, "\\") || path.IsAbs(name) {
+		return false
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." {
+			return false
+		}
+	}
+	return strings.HasPrefix(path.Clean(name), "cache/")
+}
+
+// ReadManifest returns the manifest of the bundle at src, without extracting it
+func ReadManifest(src string) (*Manifest, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, errors.Wrap(err, "open")
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "read bundle")
+		}
+		if hdr.Name == manifestFile {
+			return readManifest(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s is not a minikube bundle: %s is missing", src, manifestFile)
+}
+
+// readManifest decodes a bundle manifest
+func readManifest(r io.Reader) (*Manifest, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read manifest")
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrap(err, "unmarshal manifest")
+	}
+	return m, nil
+}
+
+// Extract populates the caches of the minikube home from the bundle at src, and returns its manifest
+func Extract(src string) (*Manifest, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, errors.Wrap(err, "open")
+	}
+	defer f.Close()
+
+	var m *Manifest
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "read bundle")
+		}
+		if hdr.Name == manifestFile {
+			if m, err = readManifest(tr); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			klog.Infof("skipping %s in bundle: not a regular file", hdr.Name)
+			continue
+		}
+		if !validPath(hdr.Name) {
+			return nil, fmt.Errorf("invalid path in bundle: %s", hdr.Name)
+		}
+		if err := extractFile(tr, path.Clean(hdr.Name), hdr.FileInfo().Mode()); err != nil {
+			return nil, errors.Wrapf(err, "extract %s", hdr.Name)
+		}
+	}
+	if m == nil {
+		return nil, fmt.Errorf("%s is not a minikube bundle: %s is missing", src, manifestFile)
+	}
+	return m, nil
+}
+
+// extractFile atomically writes a bundle entry into the minikube home
+func extractFile(r io.Reader, name string, mode os.FileMode) error {
+	dst := filepath.Join(localpath.MiniPath(), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp := dst + ".extract"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	klog.Infof("extracted %s from bundle", dst)
+	return os.Rename(tmp, dst)
+}
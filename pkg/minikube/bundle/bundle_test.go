@@ -0,0 +1,215 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestValidPath(t *testing.T) {
+	for name, want := range map[string]bool{
+		"cache/iso/minikube-v1.17.0.iso":           true,
+		"cache/linux/v1.20.2/kubelet":              true,
+		"bundle.json":                              false,
+		"cache":                                    false,
+		"config/config.json":                       false,
+		"/cache/iso/minikube.iso":                  false,
+		"cache/../machines/minikube/id_rsa":        false,
+		"cache/../../.ssh/authorized_keys":         false,
+		"cache\\..\\..\\.ssh\\authorized_keys":     false,
+		"cache/images/k8s.gcr.io/pause_3.2/../../": false,
+	} {
+		if got := validPath(name); got != want {
+			t.Errorf("validPath(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestCreateExtract(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmpDir, "src"))
+
+	files := map[string]string{
+		"cache/iso/minikube-v1.17.0.iso":    "iso",
+		"cache/linux/v1.20.2/kubelet":       "kubelet",
+		"cache/images/k8s.gcr.io/pause_3.2": "pause",
+	}
+	m := &Manifest{
+		MinikubeVersion:   "v1.17.0",
+		Driver:            "kvm2",
+		ContainerRuntime:  "containerd",
+		KubernetesVersion: "v1.20.2",
+		Images:            []string{"k8s.gcr.io/pause:3.2"},
+	}
+	for name, contents := range files {
+		p := localpath.MakeMiniPath(filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		m.Files = append(m.Files, name)
+	}
+
+	archive := filepath.Join(tmpDir, "bundle.tar")
+	if err := Create(archive, m); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmpDir, "dst"))
+	read, err := ReadManifest(archive)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if diff := cmp.Diff(m, read); diff != "" {
+		t.Errorf("ReadManifest mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := os.Stat(localpath.MiniPath()); !os.IsNotExist(err) {
+		t.Errorf("ReadManifest wrote to the minikube home: %v", err)
+	}
+
+	got, err := Extract(archive)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if diff := cmp.Diff(m, got); diff != "" {
+		t.Errorf("Extract manifest mismatch (-want +got):\n%s", diff)
+	}
+	for name, want := range files {
+		contents, err := ioutil.ReadFile(localpath.MakeMiniPath(filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(contents) != want {
+			t.Errorf("%s = %q, want %q", name, contents, want)
+		}
+	}
+}
+
+func TestExtractInvalid(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmpDir, "home"))
+
+	tests := []struct {
+		name    string
+		entries map[string]string
+		err     string
+	}{
+		{"no manifest", map[string]string{"cache/iso/minikube.iso": "iso"}, "is not a minikube bundle"},
+		{"traversal", map[string]string{manifestFile: "{}", "cache/../../evil": "evil"}, "invalid path in bundle"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			archive := filepath.Join(tmpDir, "bundle.tar")
+			f, err := os.Create(archive)
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			tw := tar.NewWriter(f)
+			for name, contents := range tc.entries {
+				if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(contents)), Typeflag: tar.TypeReg}); err != nil {
+					t.Fatalf("header: %v", err)
+				}
+				if _, err := tw.Write([]byte(contents)); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+			}
+			tw.Close()
+			f.Close()
+
+			_, err = Extract(archive)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Extract() error = %v, want %q", err, tc.err)
+			}
+			if _, err := os.Stat(filepath.Join(tmpDir, "evil")); err == nil {
+				t.Errorf("Extract() wrote outside of the minikube home")
+			}
+		})
+	}
+}
+
+func TestAddonImages(t *testing.T) {
+	addon, ok := assets.Addons["metrics-server"]
+	if !ok {
+		t.Skip("metrics-server addon not found")
+	}
+	imgs, err := addon.Images(config.KubernetesConfig{})
+	if err != nil {
+		t.Fatalf("Images: %v", err)
+	}
+	if len(imgs) == 0 {
+		t.Fatalf("Images() returned no images for metrics-server")
+	}
+	for _, img := range imgs {
+		if strings.ContainsAny(img, "{}\"' ") {
+			t.Errorf("Images() returned an unevaluated or unquoted image %q", img)
+		}
+	}
+}
+
+func TestOfflineDownload(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmpDir)
+	download.SetOffline(true)
+	defer download.SetOffline(false)
+
+	if _, err := download.Binary("kubelet", "v1.20.2", "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Errorf("Binary() while offline = %v, want an offline error", err)
+	}
+	if download.PreloadExists("v1.20.2", "docker", true) {
+		t.Errorf("PreloadExists() while offline and without a local preload = true, want false")
+	}
+
+	p := localpath.MakeMiniPath("cache", "linux", "v1.20.2", "kubelet")
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(p, []byte("kubelet"), 0o755); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := download.Binary("kubelet", "v1.20.2", "linux", "amd64"); err != nil {
+		t.Errorf("Binary() while offline with a cached binary = %v, want no error", err)
+	}
+}
@@ -184,6 +184,47 @@ func (e *execRunner) Copy(f assets.CopyableFile) error {
 	return writeFile(dst, f, os.FileMode(perms))
 }
 
+// CopyFrom copies a file to a path on the host
+func (e *execRunner) CopyFrom(src string, dst string) error {
+	klog.Infof("cp: %s --> %s", src, dst)
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer w.Close()
+
+	// like SSHRunner, leave no partial file behind
+	fail := func(err error) error {
+		w.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	if e.sudo {
+		// stream through sudo, as the source may only be readable by root
+		var errb bytes.Buffer
+		cmd := exec.Command("sudo", "cat", src)
+		cmd.Stdout = w
+		cmd.Stderr = &errb
+		if err := cmd.Run(); err != nil {
+			return fail(errors.Wrapf(err, "sudo cat %s, stderr: %s", src, errb.String()))
+		}
+		return w.Close()
+	}
+
+	r, err := os.Open(src)
+	if err != nil {
+		return fail(errors.Wrap(err, "open"))
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(w, r); err != nil {
+		return fail(errors.Wrap(err, "copy"))
+	}
+	return w.Close()
+}
+
 // Remove removes a file
 func (e *execRunner) Remove(f assets.CopyableFile) error {
 	dst := filepath.Join(f.GetTargetDir(), f.GetTargetName())
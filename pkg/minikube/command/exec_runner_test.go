@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecRunnerCopyFrom(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	src := filepath.Join(tempDir, "src")
+	if err := ioutil.WriteFile(src, []byte("contents"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	runner := NewExecRunner(false)
+
+	dst := filepath.Join(tempDir, "dst")
+	if err := runner.CopyFrom(src, dst); err != nil {
+		t.Fatalf("CopyFrom: %v", err)
+	}
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(got) != "contents" {
+		t.Errorf("CopyFrom() copied %q, want %q", got, "contents")
+	}
+
+	missing := filepath.Join(tempDir, "missing")
+	if err := runner.CopyFrom(filepath.Join(tempDir, "nonexistent"), missing); err == nil {
+		t.Fatalf("CopyFrom() of a nonexistent file should have returned an error")
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("CopyFrom() left %s behind after failing: %v", missing, err)
+	}
+}
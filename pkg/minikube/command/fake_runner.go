@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os/exec"
 	"strings"
 	"time"
@@ -142,6 +143,15 @@ func (f *FakeCommandRunner) Copy(file assets.CopyableFile) error {
 	return nil
 }
 
+// CopyFrom writes the stored contents of src to dst on the host
+func (f *FakeCommandRunner) CopyFrom(src string, dst string) error {
+	contents, ok := f.fileMap.Load(src)
+	if !ok {
+		return fmt.Errorf("unavailable file: %s", src)
+	}
+	return ioutil.WriteFile(dst, []byte(contents.(string)), 0644)
+}
+
 // Remove removes the filename, file contents key value pair from the stored map
 func (f *FakeCommandRunner) Remove(file assets.CopyableFile) error {
 	f.fileMap.Delete(file.GetSourcePath())
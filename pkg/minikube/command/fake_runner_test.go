@@ -17,7 +17,10 @@ limitations under the License.
 package command
 
 import (
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"k8s.io/minikube/pkg/minikube/assets"
@@ -75,6 +78,36 @@ func TestFakeRunnerFile(t *testing.T) {
 		}
 	})
 
+	t.Run("CopyFromFile", func(t *testing.T) {
+		expectedFileContents := "remote contents"
+		fileName := "/etc/remote"
+		fakeCommandRunner.SetFileToContents(map[string]string{fileName: expectedFileContents})
+
+		dir, err := ioutil.TempDir("", "copyfrom")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		dst := filepath.Join(dir, "local")
+		if err := fakeCommandRunner.CopyFrom(fileName, dst); err != nil {
+			t.Fatal(err)
+		}
+
+		retrievedFileContents, err := ioutil.ReadFile(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expectedFileContents != string(retrievedFileContents) {
+			t.Errorf("expected %q, retrieved %q", expectedFileContents, retrievedFileContents)
+		}
+
+		if err := fakeCommandRunner.CopyFrom("/missing", dst); err == nil {
+			t.Errorf("expected an error copying a missing file")
+		}
+	})
+
 	t.Run("RunCmd", func(t *testing.T) {
 		expectedOutput := "123"
 		command := &exec.Cmd{Args: []string{cmdArg}}
@@ -265,6 +265,50 @@ func copyToDocker(src string, dest string) error {
 	return nil
 }
 
+// CopyFrom copies a file from the container to the host
+func (k *kicRunner) CopyFrom(src string, dst string) error {
+	fullSource := fmt.Sprintf("%s:%s", k.nameOrID, src)
+	klog.Infof("%s: %s --> %s", k.ociBin, fullSource, dst)
+	if k.ociBin == oci.Podman {
+		return copyFromPodman(fullSource, dst)
+	}
+	return copyFromDocker(fullSource, dst)
+}
+
+// Podman cp command doesn't match docker and doesn't have -a
+func copyFromPodman(src string, dest string) error {
+	if runtime.GOOS == "linux" {
+		cmd := oci.PrefixCmd(exec.Command(oci.Podman, "cp", src, dest))
+		klog.Infof("Run: %v", cmd)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "podman copy %s into %s, output: %s", src, dest, string(out))
+		}
+	} else {
+		file, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		parts := strings.Split(src, ":")
+		container := parts[0]
+		path := parts[1]
+		cmd := exec.Command(oci.Podman, "exec", container, "cat", path)
+		cmd.Stdout = file
+		klog.Infof("Run: %v", cmd)
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "podman copy %s into %s", src, dest)
+		}
+	}
+	return nil
+}
+
+func copyFromDocker(src string, dest string) error {
+	if out, err := oci.PrefixCmd(exec.Command(oci.Docker, "cp", src, dest)).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "docker copy %s into %s, output: %s", src, dest, string(out))
+	}
+	return nil
+}
+
 // Remove removes a file
 func (k *kicRunner) Remove(f assets.CopyableFile) error {
 	dst := path.Join(f.GetTargetDir(), f.GetTargetName())
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path"
 	"sync"
@@ -367,3 +368,38 @@ func (s *SSHRunner) Copy(f assets.CopyableFile) error {
 	}
 	return g.Wait()
 }
+
+// CopyFrom copies a file from the remote over SSH.
+func (s *SSHRunner) CopyFrom(src string, dst string) error {
+	klog.Infof("scp %s <-- %s", dst, src)
+
+	sess, err := s.session()
+	if err != nil {
+		return errors.Wrap(err, "NewSession")
+	}
+	defer func() {
+		if err := sess.Close(); err != nil {
+			if err != io.EOF {
+				klog.Errorf("session close: %v", err)
+			}
+		}
+	}()
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer w.Close()
+
+	var errb bytes.Buffer
+	sess.Stdout = w
+	sess.Stderr = &errb
+
+	cmd := fmt.Sprintf("sudo cat %s", shellquote.Join(src))
+	if err := sess.Run(cmd); err != nil {
+		w.Close()
+		os.Remove(dst)
+		return fmt.Errorf("%s: %v\nstderr: %s", cmd, err, errb.String())
+	}
+	return w.Close()
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// taintEffects are the effects a Kubernetes taint may have
+var taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// ParseNodeLabels parses a list of key=value labels
+func ParseNodeLabels(labels []string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	m := map[string]string{}
+	for _, l := range labels {
+		kv := strings.SplitN(l, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid label %q: must be key=value", l)
+		}
+		if errs := validation.IsQualifiedName(kv[0]); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %q: %s", kv[0], strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(kv[1]); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label value %q: %s", kv[1], strings.Join(errs, "; "))
+		}
+		m[kv[0]] = kv[1]
+	}
+	return m, nil
+}
+
+// ValidateNodeTaint checks that a taint is of the form key[=value]:effect
+func ValidateNodeTaint(taint string) error {
+	i := strings.LastIndex(taint, ":")
+	if i < 0 {
+		return fmt.Errorf("invalid taint %q: must be key[=value]:effect", taint)
+	}
+
+	effect := taint[i+1:]
+	if !ContainsParam(taintEffects, effect) {
+		return fmt.Errorf("invalid taint %q: effect must be one of %s", taint, strings.Join(taintEffects, ", "))
+	}
+
+	kv := strings.SplitN(taint[:i], "=", 2)
+	if errs := validation.IsQualifiedName(kv[0]); len(errs) > 0 {
+		return fmt.Errorf("invalid taint key %q: %s", kv[0], strings.Join(errs, "; "))
+	}
+	if len(kv) == 2 {
+		if errs := validation.IsValidLabelValue(kv[1]); len(errs) > 0 {
+			return fmt.Errorf("invalid taint value %q: %s", kv[1], strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
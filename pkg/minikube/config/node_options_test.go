@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNodeLabels(t *testing.T) {
+	tests := []struct {
+		labels   []string
+		expected map[string]string
+		err      bool
+	}{
+		{labels: nil, expected: nil},
+		{labels: []string{"pool=gpu", "example.com/tier=big-memory"}, expected: map[string]string{"pool": "gpu", "example.com/tier": "big-memory"}},
+		{labels: []string{"empty="}, expected: map[string]string{"empty": ""}},
+		{labels: []string{"pool"}, err: true},
+		{labels: []string{"-pool=gpu"}, err: true},
+		{labels: []string{"pool=not valid"}, err: true},
+	}
+	for _, test := range tests {
+		got, err := ParseNodeLabels(test.labels)
+		if (err != nil) != test.err {
+			t.Fatalf("ParseNodeLabels(%v) error = %v, expected error: %v", test.labels, err, test.err)
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("ParseNodeLabels(%v) = %v, expected %v", test.labels, got, test.expected)
+		}
+	}
+}
+
+func TestValidateNodeTaint(t *testing.T) {
+	tests := []struct {
+		taint string
+		err   bool
+	}{
+		{taint: "gpu=true:NoSchedule"},
+		{taint: "example.com/dedicated:PreferNoSchedule"},
+		{taint: "maintenance=:NoExecute"},
+		{taint: "gpu=true", err: true},
+		{taint: "gpu=true:NoScheduling", err: true},
+		{taint: ":NoSchedule", err: true},
+		{taint: "gpu=not valid:NoSchedule", err: true},
+	}
+	for _, test := range tests {
+		err := ValidateNodeTaint(test.taint)
+		if (err != nil) != test.err {
+			t.Errorf("ValidateNodeTaint(%q) error = %v, expected error: %v", test.taint, err, test.err)
+		}
+	}
+}
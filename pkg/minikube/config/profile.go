@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
@@ -120,8 +121,14 @@ func CreateEmptyProfile(name string, miniHome ...string) error {
 	return SaveProfile(name, cfg, miniHome...)
 }
 
+// saveMu serializes changes to cluster configs, as the nodes of a cluster may be started in parallel
+var saveMu sync.Mutex
+
 // SaveNode saves a node to a cluster
 func SaveNode(cfg *ClusterConfig, node *Node) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+
 	update := false
 	for i, n := range cfg.Nodes {
 		if n.Name == node.Name {
@@ -135,11 +142,18 @@ func SaveNode(cfg *ClusterConfig, node *Node) error {
 		cfg.Nodes = append(cfg.Nodes, *node)
 	}
 
-	return SaveProfile(viper.GetString(ProfileName), cfg)
+	return saveProfile(viper.GetString(ProfileName), cfg)
 }
 
 // SaveProfile creates an profile out of the cfg and stores in $MINIKUBE_HOME/profiles/<profilename>/config.json
 func SaveProfile(name string, cfg *ClusterConfig, miniHome ...string) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	return saveProfile(name, cfg, miniHome...)
+}
+
+// saveProfile is SaveProfile for callers which hold saveMu
+func saveProfile(name string, cfg *ClusterConfig, miniHome ...string) error {
 	data, err := json.MarshalIndent(cfg, "", "    ")
 	if err != nil {
 		return err
@@ -291,8 +305,73 @@ func ProfileFolderPath(profile string, miniHome ...string) string {
 // MachineName returns the name of the machine, as seen by the hypervisor given the cluster and node names
 func MachineName(cc ClusterConfig, n Node) string {
 	// For single node cluster, default to back to old naming
-	if len(cc.Nodes) == 1 || n.ControlPlane {
+	if len(cc.Nodes) == 1 || IsPrimaryControlPlane(cc, n) {
 		return cc.Name
 	}
 	return fmt.Sprintf("%s-%s", cc.Name, n.Name)
 }
+
+// IsPrimaryControlPlane returns whether the node is the first control plane of the cluster, which was initialized by kubeadm init
+func IsPrimaryControlPlane(cc ClusterConfig, n Node) bool {
+	if !n.ControlPlane {
+		return false
+	}
+	for _, cp := range cc.Nodes {
+		if cp.ControlPlane {
+			return cp.Name == n.Name
+		}
+	}
+	// a cluster without nodes is being created with this node
+	return true
+}
+
+// NodeKubernetesVersion returns the Kubernetes version a node runs once started: its own when pinned, the cluster version otherwise
+func NodeKubernetesVersion(cc ClusterConfig, n Node) string {
+	if n.PinnedVersion && n.KubernetesVersion != "" {
+		return n.KubernetesVersion
+	}
+	return cc.KubernetesConfig.KubernetesVersion
+}
+
+// NodeCPUs returns the number of CPUs of the node, which defaults to the one of the cluster
+func NodeCPUs(cc ClusterConfig, n Node) int {
+	if n.CPUs > 0 {
+		return n.CPUs
+	}
+	return cc.CPUs
+}
+
+// NodeMemory returns the memory of the node in MB, which defaults to the one of the cluster
+func NodeMemory(cc ClusterConfig, n Node) int {
+	if n.Memory > 0 {
+		return n.Memory
+	}
+	return cc.Memory
+}
+
+// NodeDiskSize returns the disk size of the node in MB, which defaults to the one of the cluster
+func NodeDiskSize(cc ClusterConfig, n Node) int {
+	if n.DiskSize > 0 {
+		return n.DiskSize
+	}
+	return cc.DiskSize
+}
+
+// HAControlPlanes is the number of control plane nodes of a highly available cluster
+const HAControlPlanes = 3
+
+// IsHA returns whether the cluster has a highly available control plane behind a virtual IP
+func IsHA(cc ClusterConfig) bool {
+	return cc.KubernetesConfig.APIServerHAVIP != ""
+}
+
+// ControlPlanes returns the control plane nodes of the cluster, the primary first
+func ControlPlanes(cc ClusterConfig) []Node {
+	cps := []Node{}
+	for _, n := range cc.Nodes {
+		if n.ControlPlane {
+			cps = append(cps, n)
+		}
+	}
+	return cps
+}
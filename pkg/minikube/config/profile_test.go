@@ -339,3 +339,37 @@ func TestGetPrimaryControlPlane(t *testing.T) {
 		}()
 	}
 }
+
+func TestControlPlanes(t *testing.T) {
+	cc := ClusterConfig{
+		Name: "p1",
+		Nodes: []Node{
+			{Name: "", ControlPlane: true, Worker: true},
+			{Name: "m02", ControlPlane: true, Worker: true},
+			{Name: "m03", Worker: true},
+		},
+	}
+
+	var tests = []struct {
+		node     Node
+		expected string
+		primary  bool
+	}{
+		{cc.Nodes[0], "p1", true},
+		{cc.Nodes[1], "p1-m02", false},
+		{cc.Nodes[2], "p1-m03", false},
+	}
+
+	for _, tc := range tests {
+		if got := MachineName(cc, tc.node); got != tc.expected {
+			t.Errorf("MachineName(%q) = %q, expected %q", tc.node.Name, got, tc.expected)
+		}
+		if got := IsPrimaryControlPlane(cc, tc.node); got != tc.primary {
+			t.Errorf("IsPrimaryControlPlane(%q) = %v, expected %v", tc.node.Name, got, tc.primary)
+		}
+	}
+
+	if got := len(ControlPlanes(cc)); got != 2 {
+		t.Errorf("ControlPlanes() returned %d nodes, expected 2", got)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+// RegistryAuth holds the credentials the nodes of a cluster use to pull from a private registry
+type RegistryAuth struct {
+	Server   string
+	Username string
+	Password string
+}
+
+// Host returns the host of the registry server, without scheme or path
+func (a RegistryAuth) Host() string {
+	h := a.Server
+	if i := strings.Index(h, "://"); i >= 0 {
+		h = h[i+3:]
+	}
+	return strings.SplitN(h, "/", 2)[0]
+}
+
+// IsDockerHub returns whether the registry server is Docker Hub, which clients name differently
+func (a RegistryAuth) IsDockerHub() bool {
+	switch a.Host() {
+	case "docker.io", "index.docker.io", "registry-1.docker.io":
+		return true
+	}
+	return false
+}
+
+// registryAuthFilePath returns the path of the registry credentials of a profile,
+// kept out of the cluster config as that one ends up in logs
+func registryAuthFilePath(profile string, miniHome ...string) string {
+	return filepath.Join(ProfileFolderPath(profile, miniHome...), "registry-auth.json")
+}
+
+// LoadRegistryAuths returns the registry credentials of a profile, sorted by server
+func LoadRegistryAuths(profile string, miniHome ...string) ([]RegistryAuth, error) {
+	data, err := ioutil.ReadFile(registryAuthFilePath(profile, miniHome...))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read registry credentials")
+	}
+	auths := []RegistryAuth{}
+	if err := json.Unmarshal(data, &auths); err != nil {
+		return nil, errors.Wrap(err, "unmarshal registry credentials")
+	}
+	return auths, nil
+}
+
+// SaveRegistryAuth stores the credentials of a registry in a profile, replacing any previous ones for the same host
+func SaveRegistryAuth(profile string, auth RegistryAuth, miniHome ...string) error {
+	auths, err := LoadRegistryAuths(profile, miniHome...)
+	if err != nil {
+		return err
+	}
+	updated := []RegistryAuth{auth}
+	for _, a := range auths {
+		if a.Host() != auth.Host() {
+			updated = append(updated, a)
+		}
+	}
+	sort.Slice(updated, func(i, j int) bool { return updated[i].Server < updated[j].Server })
+
+	data, err := json.MarshalIndent(updated, "", "    ")
+	if err != nil {
+		return err
+	}
+	path := registryAuthFilePath(profile, miniHome...)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return lock.WriteFile(path, data, 0600)
+}
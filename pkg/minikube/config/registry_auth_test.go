@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegistryAuthHost(t *testing.T) {
+	var tests = []struct {
+		server string
+		host   string
+		hub    bool
+	}{
+		{"registry.example.com", "registry.example.com", false},
+		{"https://registry.example.com:5000/v2/", "registry.example.com:5000", false},
+		{"docker.io", "docker.io", true},
+		{"https://index.docker.io/v1/", "index.docker.io", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.server, func(t *testing.T) {
+			a := RegistryAuth{Server: tc.server}
+			if got := a.Host(); got != tc.host {
+				t.Errorf("Host() = %q, want %q", got, tc.host)
+			}
+			if got := a.IsDockerHub(); got != tc.hub {
+				t.Errorf("IsDockerHub() = %v, want %v", got, tc.hub)
+			}
+		})
+	}
+}
+
+func TestSaveRegistryAuth(t *testing.T) {
+	miniHome, err := ioutil.TempDir("", "registry-auth")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(miniHome)
+
+	auths, err := LoadRegistryAuths("p1", miniHome)
+	if err != nil || auths != nil {
+		t.Fatalf("LoadRegistryAuths() = %v, %v, want no credentials", auths, err)
+	}
+
+	for _, a := range []RegistryAuth{
+		{Server: "registry.example.com", Username: "old", Password: "old"},
+		{Server: "docker.io", Username: "hub", Password: "hub"},
+		{Server: "https://registry.example.com/", Username: "new", Password: "new"},
+	} {
+		if err := SaveRegistryAuth("p1", a, miniHome); err != nil {
+			t.Fatalf("SaveRegistryAuth(%s): %v", a.Server, err)
+		}
+	}
+
+	auths, err = LoadRegistryAuths("p1", miniHome)
+	if err != nil {
+		t.Fatalf("LoadRegistryAuths: %v", err)
+	}
+	want := []RegistryAuth{
+		{Server: "docker.io", Username: "hub", Password: "hub"},
+		{Server: "https://registry.example.com/", Username: "new", Password: "new"},
+	}
+	if diff := cmp.Diff(want, auths); diff != "" {
+		t.Errorf("registry credentials diff (-want +got):\n%s", diff)
+	}
+
+	fi, err := os.Stat(registryAuthFilePath("p1", miniHome))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("registry credentials mode = %v, want 0600", fi.Mode().Perm())
+	}
+}
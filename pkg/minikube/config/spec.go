@@ -0,0 +1,268 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// SpecAPIVersion is the version of the cluster definition format
+	SpecAPIVersion = "minikube.sigs.k8s.io/v1alpha1"
+	// SpecKind is the kind of a cluster definition
+	SpecKind = "Cluster"
+	// RoleControlPlane is the role of a node running the Kubernetes control plane
+	RoleControlPlane = "control-plane"
+	// RoleWorker is the role of a node which only runs workloads
+	RoleWorker = "worker"
+)
+
+// ClusterSpec is a declarative definition of a cluster, as used by `minikube start --config`
+type ClusterSpec struct {
+	APIVersion         string         `json:"apiVersion" yaml:"apiVersion"`
+	Kind               string         `json:"kind" yaml:"kind"`
+	Name               string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Driver             string         `json:"driver,omitempty" yaml:"driver,omitempty"`
+	CPUs               int            `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	Memory             string         `json:"memory,omitempty" yaml:"memory,omitempty"`     // format: <number>[<unit>], where unit = b, k, m or g
+	DiskSize           string         `json:"diskSize,omitempty" yaml:"diskSize,omitempty"` // format: <number>[<unit>], where unit = b, k, m or g
+	BaseImage          string         `json:"baseImage,omitempty" yaml:"baseImage,omitempty"`
+	Network            string         `json:"network,omitempty" yaml:"network,omitempty"`
+	InsecureRegistries []string       `json:"insecureRegistries,omitempty" yaml:"insecureRegistries,omitempty"`
+	RegistryMirrors    []string       `json:"registryMirrors,omitempty" yaml:"registryMirrors,omitempty"`
+	Kubernetes         KubernetesSpec `json:"kubernetes,omitempty" yaml:"kubernetes,omitempty"`
+	Addons             []string       `json:"addons,omitempty" yaml:"addons,omitempty"`
+	Nodes              []NodeSpec     `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Mounts             []MountSpec    `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+	Ports              []string       `json:"ports,omitempty" yaml:"ports,omitempty"` // Only used by the docker and podman driver
+}
+
+// KubernetesSpec is the Kubernetes part of a cluster definition
+type KubernetesSpec struct {
+	Version          string   `json:"version,omitempty" yaml:"version,omitempty"`
+	ContainerRuntime string   `json:"containerRuntime,omitempty" yaml:"containerRuntime,omitempty"`
+	CNI              string   `json:"cni,omitempty" yaml:"cni,omitempty"`
+	NetworkPlugin    string   `json:"networkPlugin,omitempty" yaml:"networkPlugin,omitempty"`
+	FeatureGates     string   `json:"featureGates,omitempty" yaml:"featureGates,omitempty"`
+	ServiceCIDR      string   `json:"serviceCIDR,omitempty" yaml:"serviceCIDR,omitempty"`
+	DNSDomain        string   `json:"dnsDomain,omitempty" yaml:"dnsDomain,omitempty"`
+	APIServerName    string   `json:"apiServerName,omitempty" yaml:"apiServerName,omitempty"`
+	APIServerNames   []string `json:"apiServerNames,omitempty" yaml:"apiServerNames,omitempty"`
+	APIServerPort    int      `json:"apiServerPort,omitempty" yaml:"apiServerPort,omitempty"`
+	ImageRepository  string   `json:"imageRepository,omitempty" yaml:"imageRepository,omitempty"`
+	ExtraConfig      []string `json:"extraConfig,omitempty" yaml:"extraConfig,omitempty"` // Each entry is formatted as component.key=value
+}
+
+// NodeSpec is a node of a cluster definition
+type NodeSpec struct {
+	Role string `json:"role" yaml:"role"` // control-plane or worker
+}
+
+// MountSpec is a host directory mounted into the cluster
+type MountSpec struct {
+	HostPath  string `json:"hostPath" yaml:"hostPath"`
+	GuestPath string `json:"guestPath" yaml:"guestPath"`
+}
+
+// String returns the mount in the format of the --mount-string flag
+func (m MountSpec) String() string {
+	return m.HostPath + ":" + m.GuestPath
+}
+
+// LoadSpec reads and validates a cluster definition from a YAML or JSON file
+func LoadSpec(file string) (*ClusterSpec, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading cluster definition")
+	}
+	return ParseSpec(data)
+}
+
+// ParseSpec parses and validates a cluster definition in YAML or JSON
+func ParseSpec(data []byte) (*ClusterSpec, error) {
+	s := &ClusterSpec{}
+	// JSON is a subset of YAML, so both formats are handled by the YAML parser
+	if err := yaml.UnmarshalStrict(data, s); err != nil {
+		return nil, errors.Wrap(err, "parsing cluster definition")
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks a cluster definition, returning an error which names the first invalid field
+func (s *ClusterSpec) Validate() error {
+	if s.APIVersion != SpecAPIVersion {
+		return fmt.Errorf("apiVersion: unsupported version %q, expected %q", s.APIVersion, SpecAPIVersion)
+	}
+	if s.Kind != SpecKind {
+		return fmt.Errorf("kind: unsupported kind %q, expected %q", s.Kind, SpecKind)
+	}
+	if s.Name != "" && !ProfileNameValid(s.Name) {
+		return fmt.Errorf("name: %q is not a valid profile name. Only alphanumeric and dashes '-' are permitted, starting with alphanumeric", s.Name)
+	}
+	if s.CPUs < 0 {
+		return fmt.Errorf("cpus: must not be negative, got %d", s.CPUs)
+	}
+	if s.Memory != "" {
+		if _, err := util.CalculateSizeInMB(s.Memory); err != nil {
+			return fmt.Errorf("memory: %v", err)
+		}
+	}
+	if s.DiskSize != "" {
+		if _, err := util.CalculateSizeInMB(s.DiskSize); err != nil {
+			return fmt.Errorf("diskSize: %v", err)
+		}
+	}
+	if s.Kubernetes.APIServerPort < 0 || s.Kubernetes.APIServerPort > 65535 {
+		return fmt.Errorf("kubernetes.apiServerPort: %d is not a valid port", s.Kubernetes.APIServerPort)
+	}
+	for i, e := range s.Kubernetes.ExtraConfig {
+		var es ExtraOptionSlice
+		if err := es.Set(e); err != nil {
+			return fmt.Errorf("kubernetes.extraConfig[%d]: %v", i, err)
+		}
+	}
+
+	cps := 0
+	for i, n := range s.Nodes {
+		switch n.Role {
+		case RoleControlPlane:
+			if i != cps {
+				return fmt.Errorf("nodes[%d].role: %s nodes must be listed before worker nodes", i, RoleControlPlane)
+			}
+			cps++
+		case RoleWorker:
+			if i == 0 {
+				return fmt.Errorf("nodes[0].role: the first node must be a %s node", RoleControlPlane)
+			}
+		default:
+			return fmt.Errorf("nodes[%d].role: unknown role %q, expected %q or %q", i, n.Role, RoleControlPlane, RoleWorker)
+		}
+	}
+	if len(s.Mounts) > 1 {
+		return fmt.Errorf("mounts: only one mount is supported, got %d", len(s.Mounts))
+	}
+	for i, m := range s.Mounts {
+		if m.HostPath == "" {
+			return fmt.Errorf("mounts[%d].hostPath: must not be empty", i)
+		}
+		if !path.IsAbs(m.GuestPath) {
+			return fmt.Errorf("mounts[%d].guestPath: %q must be an absolute path", i, m.GuestPath)
+		}
+	}
+	return nil
+}
+
+// HA returns whether the cluster definition asks for a highly available control plane
+func (s ClusterSpec) HA() bool {
+	return s.ControlPlanes() > 1
+}
+
+// ControlPlanes returns the number of control plane nodes of the cluster definition
+func (s ClusterSpec) ControlPlanes() int {
+	cps := 0
+	for _, n := range s.Nodes {
+		if n.Role == RoleControlPlane {
+			cps++
+		}
+	}
+	return cps
+}
+
+// SpecFromConfig returns the cluster definition of a saved cluster config
+func SpecFromConfig(cc ClusterConfig) ClusterSpec {
+	k8s := cc.KubernetesConfig
+	s := ClusterSpec{
+		APIVersion:         SpecAPIVersion,
+		Kind:               SpecKind,
+		Name:               cc.Name,
+		Driver:             cc.Driver,
+		CPUs:               cc.CPUs,
+		BaseImage:          cc.KicBaseImage,
+		Network:            cc.Network,
+		InsecureRegistries: cc.InsecureRegistry,
+		RegistryMirrors:    cc.RegistryMirror,
+		Ports:              cc.ExposedPorts,
+		Kubernetes: KubernetesSpec{
+			Version:          k8s.KubernetesVersion,
+			ContainerRuntime: k8s.ContainerRuntime,
+			CNI:              k8s.CNI,
+			NetworkPlugin:    k8s.NetworkPlugin,
+			FeatureGates:     k8s.FeatureGates,
+			ServiceCIDR:      k8s.ServiceCIDR,
+			DNSDomain:        k8s.DNSDomain,
+			APIServerName:    k8s.APIServerName,
+			APIServerNames:   k8s.APIServerNames,
+			APIServerPort:    k8s.NodePort,
+			ImageRepository:  k8s.ImageRepository,
+		},
+	}
+	if cc.Memory > 0 {
+		s.Memory = fmt.Sprintf("%dmb", cc.Memory)
+	}
+	if cc.DiskSize > 0 {
+		s.DiskSize = fmt.Sprintf("%dmb", cc.DiskSize)
+	}
+	for _, e := range k8s.ExtraOptions {
+		s.Kubernetes.ExtraConfig = append(s.Kubernetes.ExtraConfig, e.String())
+	}
+
+	for name, enabled := range cc.Addons {
+		if enabled {
+			s.Addons = append(s.Addons, name)
+		}
+	}
+	sort.Strings(s.Addons)
+
+	for _, n := range cc.Nodes {
+		role := RoleWorker
+		if n.ControlPlane {
+			role = RoleControlPlane
+		}
+		s.Nodes = append(s.Nodes, NodeSpec{Role: role})
+	}
+
+	if cc.Mount {
+		if idx := strings.LastIndex(cc.MountString, ":"); idx > 0 {
+			s.Mounts = []MountSpec{{HostPath: cc.MountString[:idx], GuestPath: cc.MountString[idx+1:]}}
+		}
+	}
+	return s
+}
+
+// Marshal returns the cluster definition in the given format, either yaml or json
+func (s ClusterSpec) Marshal(format string) ([]byte, error) {
+	switch format {
+	case "yaml":
+		return yaml.Marshal(s)
+	case "json":
+		return json.MarshalIndent(s, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported format %q, expected yaml or json", format)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSpec(t *testing.T) {
+	yamlSpec := `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: Cluster
+name: dev
+driver: docker
+cpus: 4
+memory: 4g
+kubernetes:
+  version: v1.20.2
+  containerRuntime: containerd
+  extraConfig:
+  - kubelet.max-pods=100
+addons: [ingress]
+nodes:
+- role: control-plane
+- role: worker
+mounts:
+- hostPath: /home/me/src
+  guestPath: /src
+`
+	jsonSpec := `{"apiVersion": "minikube.sigs.k8s.io/v1alpha1", "kind": "Cluster", "name": "dev", "driver": "docker", "cpus": 4, "memory": "4g",
+"kubernetes": {"version": "v1.20.2", "containerRuntime": "containerd", "extraConfig": ["kubelet.max-pods=100"]},
+"addons": ["ingress"], "nodes": [{"role": "control-plane"}, {"role": "worker"}], "mounts": [{"hostPath": "/home/me/src", "guestPath": "/src"}]}`
+
+	want := &ClusterSpec{
+		APIVersion: SpecAPIVersion,
+		Kind:       SpecKind,
+		Name:       "dev",
+		Driver:     "docker",
+		CPUs:       4,
+		Memory:     "4g",
+		Kubernetes: KubernetesSpec{
+			Version:          "v1.20.2",
+			ContainerRuntime: "containerd",
+			ExtraConfig:      []string{"kubelet.max-pods=100"},
+		},
+		Addons: []string{"ingress"},
+		Nodes:  []NodeSpec{{Role: RoleControlPlane}, {Role: RoleWorker}},
+		Mounts: []MountSpec{{HostPath: "/home/me/src", GuestPath: "/src"}},
+	}
+
+	for name, data := range map[string]string{"yaml": yamlSpec, "json": jsonSpec} {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseSpec([]byte(data))
+			if err != nil {
+				t.Fatalf("ParseSpec: %v", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ParseSpec mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	header := "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: Cluster\n"
+	tests := []struct {
+		description string
+		spec        string
+		err         string
+	}{
+		{"wrong version", "apiVersion: v1\nkind: Cluster\n", "apiVersion"},
+		{"wrong kind", "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: Pod\n", "kind"},
+		{"unknown field", header + "cpu: 2\n", "cpu"},
+		{"invalid name", header + "name: -dev\n", "name"},
+		{"invalid memory", header + "memory: lots\n", "memory"},
+		{"invalid extra config", header + "kubernetes:\n  extraConfig: [max-pods]\n", "kubernetes.extraConfig[0]"},
+		{"worker first", header + "nodes:\n- role: worker\n", "nodes[0].role"},
+		{"control plane after worker", header + "nodes:\n- role: control-plane\n- role: worker\n- role: control-plane\n", "nodes[2].role"},
+		{"unknown role", header + "nodes:\n- role: control-plane\n- role: etcd\n", "nodes[1].role"},
+		{"relative guest path", header + "mounts:\n- hostPath: /src\n  guestPath: src\n", "mounts[0].guestPath"},
+		{"two mounts", header + "mounts:\n- {hostPath: /a, guestPath: /a}\n- {hostPath: /b, guestPath: /b}\n", "mounts"},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := ParseSpec([]byte(test.spec))
+			if err == nil {
+				t.Fatalf("ParseSpec expected an error")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("ParseSpec error = %q, expected it to mention %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestSpecControlPlanes(t *testing.T) {
+	header := "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: Cluster\nnodes:\n"
+	for _, cps := range []int{1, 2, 3, 4} {
+		spec := header + strings.Repeat("- role: control-plane\n", cps) + "- role: worker\n"
+		s, err := ParseSpec([]byte(spec))
+		if err != nil {
+			t.Fatalf("ParseSpec with %d control planes: %v", cps, err)
+		}
+		if s.ControlPlanes() != cps {
+			t.Errorf("ControlPlanes() = %d, expected %d", s.ControlPlanes(), cps)
+		}
+		if s.HA() != (cps > 1) {
+			t.Errorf("HA() with %d control planes = %v", cps, s.HA())
+		}
+	}
+}
+
+func TestSpecFromConfig(t *testing.T) {
+	cc := ClusterConfig{
+		Name:         "dev",
+		Driver:       "docker",
+		CPUs:         4,
+		Memory:       4096,
+		DiskSize:     20000,
+		ExposedPorts: []string{"8080:80"},
+		KubernetesConfig: KubernetesConfig{
+			KubernetesVersion: "v1.20.2",
+			ContainerRuntime:  "containerd",
+			NodePort:          8443,
+			ExtraOptions:      ExtraOptionSlice{{Component: "kubelet", Key: "max-pods", Value: "100"}},
+		},
+		Nodes:       []Node{{Name: "", ControlPlane: true, Worker: true}, {Name: "m02", Worker: true}},
+		Addons:      map[string]bool{"ingress": true, "dashboard": false, "default-storageclass": true},
+		Mount:       true,
+		MountString: "/home/me/src:/src",
+	}
+
+	s := SpecFromConfig(cc)
+	want := ClusterSpec{
+		APIVersion: SpecAPIVersion,
+		Kind:       SpecKind,
+		Name:       "dev",
+		Driver:     "docker",
+		CPUs:       4,
+		Memory:     "4096mb",
+		DiskSize:   "20000mb",
+		Ports:      []string{"8080:80"},
+		Kubernetes: KubernetesSpec{
+			Version:          "v1.20.2",
+			ContainerRuntime: "containerd",
+			APIServerPort:    8443,
+			ExtraConfig:      []string{"kubelet.max-pods=100"},
+		},
+		Addons: []string{"default-storageclass", "ingress"},
+		Nodes:  []NodeSpec{{Role: RoleControlPlane}, {Role: RoleWorker}},
+		Mounts: []MountSpec{{HostPath: "/home/me/src", GuestPath: "/src"}},
+	}
+	if diff := cmp.Diff(want, s); diff != "" {
+		t.Errorf("SpecFromConfig mismatch (-want +got):\n%s", diff)
+	}
+
+	// An exported definition must be accepted by start
+	for _, format := range []string{"yaml", "json"} {
+		data, err := s.Marshal(format)
+		if err != nil {
+			t.Fatalf("Marshal(%s): %v", format, err)
+		}
+		got, err := ParseSpec(data)
+		if err != nil {
+			t.Fatalf("ParseSpec(%s): %v\n%s", format, err, data)
+		}
+		if diff := cmp.Diff(&s, got); diff != "" {
+			t.Errorf("%s round trip mismatch (-want +got):\n%s", format, diff)
+		}
+	}
+}
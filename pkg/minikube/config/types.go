@@ -35,6 +35,8 @@ type ClusterConfig struct {
 	Name                    string
 	KeepContext             bool   // used by start and profile command to or not to switch kubectl's current context
 	EmbedCerts              bool   // used by kubeconfig.Setup
+	ExecCredential          bool   // used by kubeconfig.Setup: the user runs the minikube kubeconfig credential plugin
+	KubeconfigPerProfile    bool   // used by kubeconfig.Setup: the context is written to a kubeconfig file in the profile directory
 	MinikubeISO             string // ISO used for VM-drivers.
 	KicBaseImage            string // base-image used for docker/podman drivers.
 	Memory                  int
@@ -79,6 +81,11 @@ type ClusterConfig struct {
 	ExposedPorts            []string // Only used by the docker and podman driver
 	Network                 string   // only used by docker driver
 	MultiNodeRequested      bool
+	Mount                   bool
+	MountString             string
+	OfflineImages           []string      // images loaded into every node from the image cache, as they can't be pulled
+	AutoStopIdle            time.Duration // the node stops itself once the API server has served no request from outside the cluster for this long
+	StopReason              string        // why the cluster was last stopped, cleared when it is started
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -99,6 +106,12 @@ type KubernetesConfig struct {
 	LoadBalancerStartIP string // currently only used by MetalLB addon
 	LoadBalancerEndIP   string // currently only used by MetalLB addon
 	CustomIngressCert   string // used by Ingress addon
+	APIServerHAVIP      string // virtual IP of the API server, only set for highly available clusters
+	CACertPath          string // CA supplied by the user to sign the certs of the cluster, instead of the shared minikubeCA
+	CAKeyPath           string // key of the CA supplied by the user
+	CertKeyAlgorithm    string // rsa or ecdsa, for the certs minikube signs
+	CertKeySize         int    // bits of RSA keys, or size of the ECDSA curve
+	CertValidity        time.Duration
 	ExtraOptions        ExtraOptionSlice
 
 	ShouldLoadCachedImages bool
@@ -120,6 +133,12 @@ type Node struct {
 	KubernetesVersion string
 	ControlPlane      bool
 	Worker            bool
+	PinnedVersion     bool              // the node keeps its KubernetesVersion rather than following the cluster version
+	CPUs              int               // overrides the CPUs of the cluster when set
+	Memory            int               // in MB, overrides the Memory of the cluster when set
+	DiskSize          int               // in MB, overrides the DiskSize of the cluster when set
+	Labels            map[string]string // extra Kubernetes labels of the node
+	Taints            []string          // Kubernetes taints of the node, as key[=value]:effect
 }
 
 // VersionedExtraOption holds information on flags to apply to a specific range
@@ -148,7 +167,7 @@ type VersionedExtraOption struct {
 }
 
 // ScheduledStopConfig contains information around scheduled stop
-// not yet used, will be used to show status of scheduled stop
+// it is cleared once the cluster is stopped or the scheduled stop is cancelled
 type ScheduledStopConfig struct {
 	InitiationTime int64
 	Duration       time.Duration
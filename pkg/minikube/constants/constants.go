@@ -84,6 +84,10 @@ const (
 	ScheduledStopEnvFile        = "/var/lib/minikube/scheduled-stop/environment"
 	ScheduledStopSystemdService = "minikube-scheduled-stop"
 
+	// idle stop constants
+	IdleStopEnvFile        = "/var/lib/minikube/idle-stop/environment"
+	IdleStopSystemdService = "minikube-idle-stop"
+
 	// MinikubeExistingPrefix is used to save the original environment when executing docker-env
 	MinikubeExistingPrefix = "MINIKUBE_EXISTING_"
 
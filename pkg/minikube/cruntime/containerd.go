@@ -97,7 +97,23 @@ oom_score = 0
     [plugins.cri.registry]
       [plugins.cri.registry.mirrors]
         [plugins.cri.registry.mirrors."docker.io"]
-          endpoint = ["https://registry-1.docker.io"]
+          endpoint = [{{ range .RegistryMirror }}"{{ . }}", {{ end }}"https://registry-1.docker.io"]
+{{- range .InsecureRegistry }}
+        [plugins.cri.registry.mirrors."{{ . }}"]
+          endpoint = ["https://{{ . }}", "http://{{ . }}"]
+{{- end }}
+{{- if or .InsecureRegistry .RegistryAuth }}
+      [plugins.cri.registry.configs]
+{{- range .InsecureRegistry }}
+        [plugins.cri.registry.configs."{{ . }}".tls]
+          insecure_skip_verify = true
+{{- end }}
+{{- range .RegistryAuth }}
+        [plugins.cri.registry.configs.{{ printf "%q" .Host }}.auth]
+          username = {{ printf "%q" .Username }}
+          password = {{ printf "%q" .Password }}
+{{- end }}
+{{- end }}
   [plugins.diff-service]
     default = ["walking"]
   [plugins.linux]
@@ -112,6 +128,16 @@ oom_score = 0
     mutation_threshold = 100
     schedule_delay = "0s"
     startup_delay = "100ms"
+`
+	// buildkitConfigFile is the path to the buildkitd configuration
+	buildkitConfigFile = "/etc/buildkit/buildkitd.toml"
+	// buildkitConfig builds straight into the containerd namespace used by the kubelet
+	buildkitConfig = `[worker.oci]
+  enabled = false
+
+[worker.containerd]
+  enabled = true
+  namespace = "k8s.io"
 `
 )
 
@@ -121,6 +147,10 @@ type Containerd struct {
 	Runner            CommandRunner
 	ImageRepository   string
 	KubernetesVersion semver.Version
+	RegistryMirror    []string
+	InsecureRegistry  []string
+	Env               []string
+	RegistryAuth      []config.RegistryAuth
 	Init              sysinit.Manager
 }
 
@@ -171,26 +201,59 @@ func (r *Containerd) Available() error {
 	return nil
 }
 
-// generateContainerdConfig sets up /etc/containerd/config.toml
-func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, forceSystemd bool) error {
-	cPath := containerdConfigFile
+// containerdAuth are the credentials of a registry endpoint, as containerd looks them up
+type containerdAuth struct {
+	Host     string
+	Username string
+	Password string
+}
+
+// containerdConfig renders /etc/containerd/config.toml
+func containerdConfig(imageRepository string, kv semver.Version, forceSystemd bool, mirrors []string, insecure []string, auths []config.RegistryAuth) ([]byte, error) {
 	t, err := template.New("containerd.config.toml").Parse(containerdConfigTemplate)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	pauseImage := images.Pause(kv, imageRepository)
 	opts := struct {
 		PodInfraContainerImage string
 		SystemdCgroup          bool
+		RegistryMirror         []string
+		InsecureRegistry       []string
+		RegistryAuth           []containerdAuth
 	}{
 		PodInfraContainerImage: pauseImage,
 		SystemdCgroup:          forceSystemd,
+		RegistryMirror:         mirrors,
+		InsecureRegistry:       registryHosts(insecure),
+	}
+	for _, a := range auths {
+		host := a.Host()
+		if a.IsDockerHub() {
+			host = "registry-1.docker.io"
+		}
+		opts.RegistryAuth = append(opts.RegistryAuth, containerdAuth{Host: host, Username: a.Username, Password: a.Password})
 	}
 	var b bytes.Buffer
 	if err := t.Execute(&b, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// generateContainerdConfig sets up /etc/containerd/config.toml, which only root can read if it holds registry credentials
+func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, forceSystemd bool, mirrors []string, insecure []string, auths []config.RegistryAuth) error {
+	cPath := containerdConfigFile
+	b, err := containerdConfig(imageRepository, kv, forceSystemd, mirrors, insecure, auths)
+	if err != nil {
 		return err
 	}
-	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -p %s && printf %%s \"%s\" | base64 -d | sudo tee %s", path.Dir(cPath), base64.StdEncoding.EncodeToString(b.Bytes()), cPath))
+	mode := "0644"
+	if len(auths) > 0 {
+		mode = "0600"
+	}
+	// set the mode before writing, so that the credentials are never readable by others
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -p %s && sudo touch %s && sudo chmod %s %s && printf %%s \"%s\" | base64 -d | sudo tee %s > /dev/null", path.Dir(cPath), cPath, mode, cPath, base64.StdEncoding.EncodeToString(b), cPath))
 	if _, err := cr.RunCmd(c); err != nil {
 		return errors.Wrap(err, "generate containerd cfg.")
 	}
@@ -207,12 +270,15 @@ func (r *Containerd) Enable(disOthers, forceSystemd bool) error {
 	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
 		return err
 	}
-	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, forceSystemd); err != nil {
+	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, forceSystemd, r.RegistryMirror, r.InsecureRegistry, r.RegistryAuth); err != nil {
 		return err
 	}
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
+	if err := r.Init.SetEnvironment("containerd", r.Env); err != nil {
+		return errors.Wrap(err, "set containerd environment")
+	}
 
 	// Otherwise, containerd will fail API requests with 'Unimplemented'
 	return r.Init.Restart("containerd")
@@ -242,6 +308,91 @@ func (r *Containerd) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this container runtime
+func (r *Containerd) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image from this runtime
+func (r *Containerd) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// PullImage pulls an image into this runtime
+func (r *Containerd) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// SaveImage saves an image from this runtime to an archive
+func (r *Containerd) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("sudo", "ctr", "-n=k8s.io", "images", "export", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "ctr images export")
+	}
+	return nil
+}
+
+// BuildImage builds an image into this runtime
+func (r *Containerd) BuildImage(src string, file string, tag string) error {
+	if err := r.enableBuildkit(); err != nil {
+		return errors.Wrap(err, "enable buildkit")
+	}
+
+	klog.Infof("Building image: %s", src)
+	dockerfile := path.Join(src, "Dockerfile")
+	if file != "" {
+		dockerfile = path.Join(src, file)
+	}
+	output := "type=image,unpack=true"
+	if tag != "" {
+		output = fmt.Sprintf("%s,name=%s", output, normalizeImageName(tag))
+	}
+	c := exec.Command("sudo", "buildctl", "build",
+		"--frontend", "dockerfile.v0",
+		"--local", fmt.Sprintf("context=%s", src),
+		"--local", fmt.Sprintf("dockerfile=%s", path.Dir(dockerfile)),
+		"--opt", fmt.Sprintf("filename=%s", path.Base(dockerfile)),
+		"--output", output)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "buildctl build")
+	}
+	return nil
+}
+
+// enableBuildkit idempotently starts buildkitd with a containerd worker in the kubelet namespace
+func (r *Containerd) enableBuildkit() error {
+	if r.Init.Active("buildkit") {
+		return nil
+	}
+	ma := assets.NewMemoryAsset([]byte(buildkitConfig), path.Dir(buildkitConfigFile), path.Base(buildkitConfigFile), "0644")
+	if err := r.Runner.Copy(ma); err != nil {
+		return errors.Wrap(err, "buildkitd config")
+	}
+	return r.Init.Start("buildkit")
+}
+
+// normalizeImageName returns the fully qualified name containerd uses for an image
+// Example:
+//  busybox -> docker.io/library/busybox:latest
+//  kicbase/stable:v0.0.1 -> docker.io/kicbase/stable:v0.0.1
+//  localhost:5000/app -> localhost:5000/app:latest
+func normalizeImageName(name string) string {
+	parts := strings.SplitN(name, "/", 2)
+	switch {
+	case len(parts) == 1:
+		name = "docker.io/library/" + name
+	case !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost":
+		name = "docker.io/" + name
+	}
+
+	last := name[strings.LastIndex(name, "/")+1:]
+	if !strings.ContainsAny(last, ":@") {
+		name += ":latest"
+	}
+	return name
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Containerd) CGroupDriver() (string, error) {
 	info, err := getCRIInfo(r.Runner)
@@ -376,17 +527,6 @@ func containerdImagesPreloaded(runner command.Runner, images []string) bool {
 	if err != nil {
 		return false
 	}
-	type crictlImages struct {
-		Images []struct {
-			ID          string      `json:"id"`
-			RepoTags    []string    `json:"repoTags"`
-			RepoDigests []string    `json:"repoDigests"`
-			Size        string      `json:"size"`
-			UID         interface{} `json:"uid"`
-			Username    string      `json:"username"`
-		} `json:"images"`
-	}
-
 	var jsonImages crictlImages
 	err = json.Unmarshal(rr.Stdout.Bytes(), &jsonImages)
 	if err != nil {
@@ -419,6 +559,21 @@ func containerdImagesPreloaded(runner command.Runner, images []string) bool {
 	return true
 }
 
+// ConfigureRegistryAuth regenerates the containerd configuration with the registry credentials, keeping its cgroup driver, and restarts containerd to read it
+func (r *Containerd) ConfigureRegistryAuth() error {
+	if len(r.RegistryAuth) == 0 {
+		return nil
+	}
+	driver, err := r.CGroupDriver()
+	if err != nil {
+		return errors.Wrap(err, "cgroup driver")
+	}
+	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, driver == "systemd", r.RegistryMirror, r.InsecureRegistry, r.RegistryAuth); err != nil {
+		return err
+	}
+	return r.Init.Restart("containerd")
+}
+
 // ImagesPreloaded returns true if all images have been preloaded
 func (r *Containerd) ImagesPreloaded(images []string) bool {
 	return containerdImagesPreloaded(r.Runner, images)
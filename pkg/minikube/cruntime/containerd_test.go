@@ -17,7 +17,11 @@ limitations under the License.
 package cruntime
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/blang/semver"
+	"k8s.io/minikube/pkg/minikube/config"
 )
 
 func TestAddRepoTagToImageName(t *testing.T) {
@@ -38,3 +42,79 @@ func TestAddRepoTagToImageName(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeImageName(t *testing.T) {
+	var tests = []struct {
+		image string
+		want  string
+	}{
+		{"busybox", "docker.io/library/busybox:latest"},
+		{"busybox:1.28", "docker.io/library/busybox:1.28"},
+		{"kicbase/stable:v0.0.15", "docker.io/kicbase/stable:v0.0.15"},
+		{"k8s.gcr.io/pause", "k8s.gcr.io/pause:latest"},
+		{"localhost/app", "localhost/app:latest"},
+		{"localhost:5000/app:v1", "localhost:5000/app:v1"},
+		{"docker.io/library/nginx@sha256:abcdef", "docker.io/library/nginx@sha256:abcdef"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.image, func(t *testing.T) {
+			got := normalizeImageName(tc.image)
+			if got != tc.want {
+				t.Errorf("normalizeImageName(%s) = %s, want %s", tc.image, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestContainerdConfig(t *testing.T) {
+	b, err := containerdConfig("", semver.MustParse("1.20.0"), false, []string{"https://mirror.example.com"}, []string{"registry.local:5000", "10.96.0.0/12"}, nil)
+	if err != nil {
+		t.Fatalf("containerdConfig: %v", err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		`endpoint = ["https://mirror.example.com", "https://registry-1.docker.io"]`,
+		`[plugins.cri.registry.mirrors."registry.local:5000"]`,
+		`endpoint = ["https://registry.local:5000", "http://registry.local:5000"]`,
+		`[plugins.cri.registry.configs."registry.local:5000".tls]`,
+		`insecure_skip_verify = true`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected config to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "10.96.0.0/12") {
+		t.Errorf("expected the insecure network to be skipped, got:\n%s", got)
+	}
+
+	b, err = containerdConfig("", semver.MustParse("1.20.0"), false, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("containerdConfig: %v", err)
+	}
+	if strings.Contains(string(b), "plugins.cri.registry.configs") {
+		t.Errorf("expected no registry configs, got:\n%s", b)
+	}
+}
+
+func TestContainerdConfigRegistryAuth(t *testing.T) {
+	auths := []config.RegistryAuth{
+		{Server: "https://registry.example.com/v2/", Username: "user", Password: `pa"ss`},
+		{Server: "docker.io", Username: "hub", Password: "secret"},
+	}
+	b, err := containerdConfig("", semver.MustParse("1.20.0"), false, nil, nil, auths)
+	if err != nil {
+		t.Fatalf("containerdConfig: %v", err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		`[plugins.cri.registry.configs]`,
+		`[plugins.cri.registry.configs."registry.example.com".auth]`,
+		`password = "pa\"ss"`,
+		`[plugins.cri.registry.configs."registry-1.docker.io".auth]`,
+		`username = "hub"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected config to contain %q, got:\n%s", want, got)
+		}
+	}
+}
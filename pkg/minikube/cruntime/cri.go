@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"net"
 	"os/exec"
 	"path"
 	"strings"
@@ -203,6 +204,61 @@ func stopCRIContainers(cr CommandRunner, ids []string) error {
 	return nil
 }
 
+// crictlImages maps to 'crictl images --output json'
+type crictlImages struct {
+	Images []struct {
+		ID          string      `json:"id"`
+		RepoTags    []string    `json:"repoTags"`
+		RepoDigests []string    `json:"repoDigests"`
+		Size        string      `json:"size"`
+		UID         interface{} `json:"uid"`
+		Username    string      `json:"username"`
+	} `json:"images"`
+}
+
+// listCRIImages returns the tags of all images known to the CRI
+func listCRIImages(cr CommandRunner) ([]string, error) {
+	rr, err := cr.RunCmd(exec.Command("sudo", "crictl", "images", "--output", "json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl images")
+	}
+
+	var jsonImages crictlImages
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &jsonImages); err != nil {
+		return nil, errors.Wrap(err, "unmarshal images")
+	}
+
+	var images []string
+	for _, img := range jsonImages.Images {
+		images = append(images, img.RepoTags...)
+	}
+	return images, nil
+}
+
+// removeCRIImage removes an image using crictl
+func removeCRIImage(cr CommandRunner, name string) error {
+	klog.Infof("Removing image: %s", name)
+
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "rmi", name)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crictl")
+	}
+	return nil
+}
+
+// pullCRIImage pulls an image using crictl
+func pullCRIImage(cr CommandRunner, name string) error {
+	klog.Infof("Pulling image: %s", name)
+
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "pull", name)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crictl")
+	}
+	return nil
+}
+
 // populateCRIConfig sets up /etc/crictl.yaml
 func populateCRIConfig(cr CommandRunner, socket string) error {
 	cPath := "/etc/crictl.yaml"
@@ -225,6 +281,19 @@ image-endpoint: unix://{{.Socket}}
 	return nil
 }
 
+// registryHosts returns the insecure registries which are hosts, as CRI runtimes can't trust whole networks
+func registryHosts(insecure []string) []string {
+	hosts := []string{}
+	for _, r := range insecure {
+		if _, _, err := net.ParseCIDR(r); err == nil {
+			klog.Infof("skipping insecure registry network %s: only supported by docker", r)
+			continue
+		}
+		hosts = append(hosts, r)
+	}
+	return hosts
+}
+
 // getCRIInfo returns current information
 func getCRIInfo(cr CommandRunner) (map[string]interface{}, error) {
 	args := []string{"crictl", "info"}
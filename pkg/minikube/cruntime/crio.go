@@ -17,9 +17,11 @@ limitations under the License.
 package cruntime
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
 	"os/exec"
 	"path"
 	"strings"
@@ -40,6 +42,11 @@ import (
 const (
 	// CRIOConfFile is the path to the CRI-O configuration
 	crioConfigFile = "/etc/crio/crio.conf"
+	// crioAuthFile is the global auth.json CRI-O pulls with
+	crioAuthFile = "/etc/crio/auth.json"
+	// crioRegistriesFile is the containers registries configuration holding mirrors and insecure registries.
+	// CRI-O 1.19 does not read drop-ins from registries.conf.d, so the file itself is replaced, and the original kept aside.
+	crioRegistriesFile = "/etc/containers/registries.conf"
 )
 
 // CRIO contains CRIO runtime state
@@ -48,6 +55,10 @@ type CRIO struct {
 	Runner            CommandRunner
 	ImageRepository   string
 	KubernetesVersion semver.Version
+	RegistryMirror    []string
+	InsecureRegistry  []string
+	Env               []string
+	RegistryAuth      []config.RegistryAuth
 	Init              sysinit.Manager
 }
 
@@ -63,6 +74,69 @@ func generateCRIOConfig(cr CommandRunner, imageRepository string, kv semver.Vers
 	return nil
 }
 
+// crioRegistriesConfig renders a V2 registries.conf for the mirrors of docker.io and the insecure registries, or "" if there are none
+func crioRegistriesConfig(mirrors []string, insecure []string) string {
+	var b strings.Builder
+	if len(mirrors) > 0 {
+		b.WriteString("\n[[registry]]\nprefix = \"docker.io\"\nlocation = \"docker.io\"\n")
+		for _, m := range mirrors {
+			u, err := url.Parse(m)
+			if err != nil || u.Host == "" {
+				klog.Warningf("skipping invalid registry mirror %q", m)
+				continue
+			}
+			fmt.Fprintf(&b, "\n[[registry.mirror]]\nlocation = %q\ninsecure = %t\n", u.Host, u.Scheme == "http")
+		}
+	}
+	for _, h := range registryHosts(insecure) {
+		fmt.Fprintf(&b, "\n[[registry]]\nlocation = %q\ninsecure = true\n", h)
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	// the V1 tables of the shipped file can not be mixed with V2 ones, so its search list is carried over
+	return "unqualified-search-registries = [\"docker.io\"]\n" + b.String()
+}
+
+// generateCRIORegistriesConfig sets up registries.conf, restoring the original when there is nothing to configure
+func generateCRIORegistriesConfig(cr CommandRunner, mirrors []string, insecure []string) error {
+	orig := crioRegistriesFile + ".orig"
+	conf := crioRegistriesConfig(mirrors, insecure)
+	if conf == "" {
+		c := exec.Command("/bin/bash", "-c", fmt.Sprintf("if sudo test -f %s; then sudo mv %s %s; fi", orig, orig, crioRegistriesFile))
+		if _, err := cr.RunCmd(c); err != nil {
+			return errors.Wrap(err, "restore crio registries")
+		}
+		return nil
+	}
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -p %s && (sudo test -f %s || sudo cp %s %s) && printf %%s \"%s\" | base64 -d | sudo tee %s > /dev/null",
+		path.Dir(crioRegistriesFile), orig, crioRegistriesFile, orig, base64.StdEncoding.EncodeToString([]byte(conf)), crioRegistriesFile))
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "generate crio registries")
+	}
+	return nil
+}
+
+// generateCRIOAuthConfig writes the registry credentials to the global auth.json, leaving it alone if there are none
+func generateCRIOAuthConfig(cr CommandRunner, auths []config.RegistryAuth) error {
+	if len(auths) == 0 {
+		return nil
+	}
+	b, err := dockerConfigJSON(auths)
+	if err != nil {
+		return err
+	}
+	ma := assets.NewMemoryAsset(b, path.Dir(crioAuthFile), path.Base(crioAuthFile), "0600")
+	if err := cr.Copy(ma); err != nil {
+		return errors.Wrap(err, "copy crio registry credentials")
+	}
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo sed -e 's|^.*global_auth_file = .*$|global_auth_file = \"%s\"|' -i %s", crioAuthFile, crioConfigFile))
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "set crio global auth file")
+	}
+	return nil
+}
+
 // Name is a human readable name for CRIO
 func (r *CRIO) Name() string {
 	return "CRI-O"
@@ -142,10 +216,20 @@ func (r *CRIO) Enable(disOthers, _ bool) error {
 	if err := generateCRIOConfig(r.Runner, r.ImageRepository, r.KubernetesVersion); err != nil {
 		return err
 	}
+	if err := generateCRIORegistriesConfig(r.Runner, r.RegistryMirror, r.InsecureRegistry); err != nil {
+		return err
+	}
+	if err := generateCRIOAuthConfig(r.Runner, r.RegistryAuth); err != nil {
+		return err
+	}
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
-	return r.Init.Start("crio")
+	if err := r.Init.SetEnvironment("crio", r.Env); err != nil {
+		return errors.Wrap(err, "set crio environment")
+	}
+	// restart, as crio only reads its configuration when starting
+	return r.Init.Restart("crio")
 }
 
 // Disable idempotently disables CRIO on a host
@@ -177,6 +261,50 @@ func (r *CRIO) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this container runtime
+func (r *CRIO) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// RemoveImage removes an image from this runtime
+func (r *CRIO) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// PullImage pulls an image into this runtime
+func (r *CRIO) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// SaveImage saves an image from this runtime to an archive
+func (r *CRIO) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("sudo", "podman", "save", "-o", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crio save image")
+	}
+	return nil
+}
+
+// BuildImage builds an image into this runtime
+func (r *CRIO) BuildImage(src string, file string, tag string) error {
+	klog.Infof("Building image: %s", src)
+	// podman shares its image store with CRI-O, so the result is immediately visible to the kubelet
+	args := []string{"podman", "build"}
+	if file != "" {
+		args = append(args, "-f", path.Join(src, file))
+	}
+	if tag != "" {
+		args = append(args, "-t", tag)
+	}
+	args = append(args, src)
+	c := exec.Command("sudo", args...)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crio build image")
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *CRIO) CGroupDriver() (string, error) {
 	c := exec.Command("crio", "config")
@@ -303,17 +431,6 @@ func crioImagesPreloaded(runner command.Runner, images []string) bool {
 	if err != nil {
 		return false
 	}
-	type crictlImages struct {
-		Images []struct {
-			ID          string      `json:"id"`
-			RepoTags    []string    `json:"repoTags"`
-			RepoDigests []string    `json:"repoDigests"`
-			Size        string      `json:"size"`
-			UID         interface{} `json:"uid"`
-			Username    string      `json:"username"`
-		} `json:"images"`
-	}
-
 	var jsonImages crictlImages
 	err = json.Unmarshal(rr.Stdout.Bytes(), &jsonImages)
 	if err != nil {
@@ -346,6 +463,17 @@ func crioImagesPreloaded(runner command.Runner, images []string) bool {
 	return true
 }
 
+// ConfigureRegistryAuth writes the registry credentials to the global auth.json and restarts CRI-O to read it
+func (r *CRIO) ConfigureRegistryAuth() error {
+	if len(r.RegistryAuth) == 0 {
+		return nil
+	}
+	if err := generateCRIOAuthConfig(r.Runner, r.RegistryAuth); err != nil {
+		return err
+	}
+	return r.Init.Restart("crio")
+}
+
 // ImagesPreloaded returns true if all images have been preloaded
 func (r *CRIO) ImagesPreloaded(images []string) bool {
 	return crioImagesPreloaded(r.Runner, images)
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCRIORegistriesConfig(t *testing.T) {
+	var tests = []struct {
+		description string
+		mirrors     []string
+		insecure    []string
+		want        string
+	}{
+		{"none", nil, nil, ""},
+		{"mirrors", []string{"https://mirror.example.com", "http://cache.local:5000"}, nil, `unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+prefix = "docker.io"
+location = "docker.io"
+
+[[registry.mirror]]
+location = "mirror.example.com"
+insecure = false
+
+[[registry.mirror]]
+location = "cache.local:5000"
+insecure = true
+`},
+		{"insecure", nil, []string{"registry.local:5000", "10.96.0.0/12"}, `unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+location = "registry.local:5000"
+insecure = true
+`},
+		{"both", []string{"https://mirror.example.com"}, []string{"registry.local:5000"}, `unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+prefix = "docker.io"
+location = "docker.io"
+
+[[registry.mirror]]
+location = "mirror.example.com"
+insecure = false
+
+[[registry]]
+location = "registry.local:5000"
+insecure = true
+`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := crioRegistriesConfig(tc.mirrors, tc.insecure)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("registries config diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package cruntime
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 
@@ -64,6 +66,8 @@ type CommandRunner interface {
 	WaitCmd(sc *command.StartedCmd) (*command.RunResult, error)
 	// Copy is a convenience method that runs a command to copy a file
 	Copy(assets.CopyableFile) error
+	// CopyFrom is a convenience method that runs a command to copy a file from the guest to the host
+	CopyFrom(string, string) error
 	// Remove is a convenience method that runs a command to remove a file
 	Remove(assets.CopyableFile) error
 }
@@ -97,6 +101,16 @@ type Manager interface {
 
 	// ImageExists takes image name and image sha checks if an it exists
 	ImageExists(string, string) bool
+	// ListImages returns a list of images managed by this container runtime
+	ListImages() ([]string, error)
+	// RemoveImage removes an image from the runtime on a host
+	RemoveImage(string) error
+	// PullImage pulls an image into the runtime on a host
+	PullImage(string) error
+	// SaveImage saves an image from the runtime to an archive on a host
+	SaveImage(string, string) error
+	// BuildImage builds an image from a context directory on a host
+	BuildImage(string, string, string) error
 
 	// ListContainers returns a list of managed by this container runtime
 	ListContainers(ListOptions) ([]string, error)
@@ -114,6 +128,8 @@ type Manager interface {
 	SystemLogCmd(int) string
 	// Preload preloads the container runtime with k8s images
 	Preload(config.KubernetesConfig) error
+	// ConfigureRegistryAuth writes the registry credentials into the runtime configuration, restarting it if needed
+	ConfigureRegistryAuth() error
 	// ImagesPreloaded returns true if all images have been preloaded
 	ImagesPreloaded([]string) bool
 }
@@ -130,6 +146,14 @@ type Config struct {
 	ImageRepository string
 	// KubernetesVersion Kubernetes version
 	KubernetesVersion semver.Version
+	// RegistryMirror are the mirrors of docker.io to pull images from
+	RegistryMirror []string
+	// InsecureRegistry are the registries to reach without TLS verification
+	InsecureRegistry []string
+	// Env is the environment of the runtime service, such as its proxy settings
+	Env []string
+	// RegistryAuth are the credentials to pull from private registries
+	RegistryAuth []config.RegistryAuth
 }
 
 // ListOptions are the options to use for listing containers
@@ -149,9 +173,10 @@ func New(c Config) (Manager, error) {
 	switch c.Type {
 	case "", "docker":
 		return &Docker{
-			Socket: c.Socket,
-			Runner: c.Runner,
-			Init:   sm,
+			Socket:       c.Socket,
+			Runner:       c.Runner,
+			RegistryAuth: c.RegistryAuth,
+			Init:         sm,
 		}, nil
 	case "crio", "cri-o":
 		return &CRIO{
@@ -159,6 +184,10 @@ func New(c Config) (Manager, error) {
 			Runner:            c.Runner,
 			ImageRepository:   c.ImageRepository,
 			KubernetesVersion: c.KubernetesVersion,
+			RegistryMirror:    c.RegistryMirror,
+			InsecureRegistry:  c.InsecureRegistry,
+			Env:               c.Env,
+			RegistryAuth:      c.RegistryAuth,
 			Init:              sm,
 		}, nil
 	case "containerd":
@@ -167,6 +196,10 @@ func New(c Config) (Manager, error) {
 			Runner:            c.Runner,
 			ImageRepository:   c.ImageRepository,
 			KubernetesVersion: c.KubernetesVersion,
+			RegistryMirror:    c.RegistryMirror,
+			InsecureRegistry:  c.InsecureRegistry,
+			Env:               c.Env,
+			RegistryAuth:      c.RegistryAuth,
 			Init:              sm,
 		}, nil
 	default:
@@ -180,6 +213,24 @@ func ContainerStatusCommand() string {
 	return "sudo `which crictl || echo crictl` ps -a || sudo docker ps -a"
 }
 
+// dockerConfigJSON renders the registry credentials in the docker config.json format, also read by the kubelet and CRI-O
+func dockerConfigJSON(auths []config.RegistryAuth) ([]byte, error) {
+	type authEntry struct {
+		Auth string `json:"auth"`
+	}
+	entries := map[string]authEntry{}
+	for _, a := range auths {
+		server := a.Host()
+		if a.IsDockerHub() {
+			server = "https://index.docker.io/v1/"
+		}
+		entries[server] = authEntry{Auth: base64.StdEncoding.EncodeToString([]byte(a.Username + ":" + a.Password))}
+	}
+	return json.MarshalIndent(struct {
+		Auths map[string]authEntry `json:"auths"`
+	}{Auths: entries}, "", "  ")
+}
+
 // disableOthers disables all other runtimes except for me.
 func disableOthers(me Manager, cr CommandRunner) error {
 	// valid values returned by manager.Name()
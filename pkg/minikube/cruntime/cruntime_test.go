@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
 )
 
 func TestName(t *testing.T) {
@@ -157,6 +158,7 @@ type FakeRunner struct {
 	cmds       []string
 	services   map[string]serviceState
 	containers map[string]string
+	images     map[string]string
 	t          *testing.T
 }
 
@@ -167,6 +169,7 @@ func NewFakeRunner(t *testing.T) *FakeRunner {
 		cmds:       []string{},
 		t:          t,
 		containers: map[string]string{},
+		images:     map[string]string{},
 	}
 }
 
@@ -229,6 +232,10 @@ func (f *FakeRunner) Copy(assets.CopyableFile) error {
 	return nil
 }
 
+func (f *FakeRunner) CopyFrom(string, string) error {
+	return nil
+}
+
 func (f *FakeRunner) Remove(assets.CopyableFile) error {
 	return nil
 }
@@ -285,12 +292,50 @@ func (f *FakeRunner) dockerInspect(args []string) (string, error) {
 	return "", nil
 }
 
+func (f *FakeRunner) dockerImages(args []string) (string, error) {
+	// images --format {{.Repository}}:{{.Tag}}
+	if args[1] == "--format" && args[2] == "{{.Repository}}:{{.Tag}}" {
+		names := []string{}
+		for name := range f.images {
+			names = append(names, name)
+		}
+		return strings.Join(names, "\n"), nil
+	}
+	return "", nil
+}
+
+// removeImage removes an image from the fake image store
+func (f *FakeRunner) removeImage(name string) (string, error) {
+	f.t.Logf("fake: Removing image %q", name)
+	if f.images[name] == "" {
+		return "", fmt.Errorf("no such image")
+	}
+	delete(f.images, name)
+	return "", nil
+}
+
+// pullImage adds an image to the fake image store
+func (f *FakeRunner) pullImage(name string) (string, error) {
+	f.t.Logf("fake: Pulling image %q", name)
+	f.images[name] = fmt.Sprintf("sha256:%x", len(f.images))
+	return "", nil
+}
+
 // docker is a fake implementation of docker
 func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 	switch cmd := args[0]; cmd {
 	case "ps":
 		return f.dockerPs(args)
 
+	case "images":
+		return f.dockerImages(args)
+
+	case "rmi":
+		return f.removeImage(args[1])
+
+	case "pull":
+		return f.pullImage(args[1])
+
 	case "stop":
 		return f.dockerStop(args)
 
@@ -408,6 +453,18 @@ func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 			}
 			delete(f.containers, id)
 		}
+	case "images":
+		if args[1] == "--output" && args[2] == "json" {
+			tags := []string{}
+			for name := range f.images {
+				tags = append(tags, fmt.Sprintf("%q", name))
+			}
+			return fmt.Sprintf(`{"images": [{"id": "sha256:0", "repoTags": [%s]}]}`, strings.Join(tags, ", ")), nil
+		}
+	case "rmi":
+		return f.removeImage(args[1])
+	case "pull":
+		return f.pullImage(args[1])
 	case "rm":
 		for _, id := range args[1:] {
 			f.t.Logf("fake crictl: Removing id %q", id)
@@ -613,7 +670,7 @@ func TestEnable(t *testing.T) {
 			map[string]serviceState{
 				"docker":        SvcExited,
 				"containerd":    SvcExited,
-				"crio":          SvcRunning,
+				"crio":          SvcRestarted,
 				"crio-shutdown": SvcExited,
 			}},
 	}
@@ -712,3 +769,93 @@ func TestContainerFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestImageFunctions(t *testing.T) {
+	var tests = []struct {
+		runtime string
+	}{
+		{"docker"},
+		{"crio"},
+		{"containerd"},
+	}
+
+	sortSlices := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			runner.images = map[string]string{
+				"k8s.gcr.io/pause:3.2":        "sha256:80d28bedfe5d",
+				"k8s.gcr.io/coredns:1.7.0":    "sha256:bfe3a36ebd25",
+				"gcr.io/k8s-minikube/busybox": "sha256:a77fe109c026",
+			}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+
+			got, err := cr.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			want := []string{"k8s.gcr.io/pause:3.2", "k8s.gcr.io/coredns:1.7.0", "gcr.io/k8s-minikube/busybox"}
+			if diff := cmp.Diff(got, want, sortSlices); diff != "" {
+				t.Errorf("ListImages() unexpected results, diff (-got + want): %s", diff)
+			}
+
+			// Pull an image and assert that it has appeared
+			if err := cr.PullImage("nginx:latest"); err != nil {
+				t.Fatalf("PullImage: %v", err)
+			}
+			got, err = cr.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			want = append(want, "nginx:latest")
+			if diff := cmp.Diff(got, want, sortSlices); diff != "" {
+				t.Errorf("ListImages() unexpected results, diff (-got + want): %s", diff)
+			}
+
+			// Remove the images and assert that they have disappeared
+			for _, img := range got {
+				if err := cr.RemoveImage(img); err != nil {
+					t.Errorf("RemoveImage(%s): %v", img, err)
+				}
+			}
+			got, err = cr.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			if len(got) > 0 {
+				t.Errorf("ListImages() = %v, want 0 items", got)
+			}
+
+			if err := cr.RemoveImage("missing"); err == nil {
+				t.Errorf("RemoveImage(missing) succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestDockerConfigJSON(t *testing.T) {
+	auths := []config.RegistryAuth{
+		{Server: "registry.example.com:5000", Username: "user", Password: "pass"},
+		{Server: "https://index.docker.io/v1/", Username: "hub", Password: "secret"},
+	}
+	got, err := dockerConfigJSON(auths)
+	if err != nil {
+		t.Fatalf("dockerConfigJSON: %v", err)
+	}
+	want := `{
+  "auths": {
+    "https://index.docker.io/v1/": {
+      "auth": "aHViOnNlY3JldA=="
+    },
+    "registry.example.com:5000": {
+      "auth": "dXNlcjpwYXNz"
+    }
+  }
+}`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("docker config diff (-want +got):\n%s", diff)
+	}
+}
@@ -38,6 +38,9 @@ import (
 // KubernetesContainerPrefix is the prefix of each Kubernetes container
 const KubernetesContainerPrefix = "k8s_"
 
+// kubeletRegistryAuthFile is the docker config.json the kubelet reads registry credentials from
+const kubeletRegistryAuthFile = "/var/lib/kubelet/config.json"
+
 // ErrISOFeature is the error returned when disk image is missing features
 type ErrISOFeature struct {
 	missing string
@@ -56,9 +59,10 @@ func (e *ErrISOFeature) Error() string {
 
 // Docker contains Docker runtime state
 type Docker struct {
-	Socket string
-	Runner CommandRunner
-	Init   sysinit.Manager
+	Socket       string
+	Runner       CommandRunner
+	RegistryAuth []config.RegistryAuth
+	Init         sysinit.Manager
 }
 
 // Name is a human readable name for Docker
@@ -108,6 +112,10 @@ func (r *Docker) Enable(disOthers, forceSystemd bool) error {
 		}
 	}
 
+	if err := r.ConfigureRegistryAuth(); err != nil {
+		return err
+	}
+
 	if forceSystemd {
 		if err := r.forceSystemd(); err != nil {
 			return err
@@ -123,6 +131,22 @@ func (r *Docker) Enable(disOthers, forceSystemd bool) error {
 	return r.Init.Start("docker")
 }
 
+// ConfigureRegistryAuth writes the registry credentials to the docker config.json the kubelet pulls with, leaving it alone if there are none
+func (r *Docker) ConfigureRegistryAuth() error {
+	if len(r.RegistryAuth) == 0 {
+		return nil
+	}
+	b, err := dockerConfigJSON(r.RegistryAuth)
+	if err != nil {
+		return err
+	}
+	ma := assets.NewMemoryAsset(b, path.Dir(kubeletRegistryAuthFile), path.Base(kubeletRegistryAuthFile), "0600")
+	if err := r.Runner.Copy(ma); err != nil {
+		return errors.Wrap(err, "copy kubelet registry credentials")
+	}
+	return nil
+}
+
 // Restart restarts Docker on a host
 func (r *Docker) Restart() error {
 	return r.Init.Restart("docker")
@@ -157,6 +181,72 @@ func (r *Docker) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this container runtime
+func (r *Docker) ListImages() ([]string, error) {
+	c := exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}")
+	rr, err := r.Runner.RunCmd(c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "docker images")
+	}
+	var images []string
+	for _, img := range strings.Split(rr.Stdout.String(), "\n") {
+		// skip dangling images, which have neither a repository nor a tag
+		if img == "" || strings.Contains(img, "<none>") {
+			continue
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// RemoveImage removes an image from this runtime
+func (r *Docker) RemoveImage(name string) error {
+	klog.Infof("Removing image: %s", name)
+	c := exec.Command("docker", "rmi", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "remove image docker.")
+	}
+	return nil
+}
+
+// PullImage pulls an image into this runtime
+func (r *Docker) PullImage(name string) error {
+	klog.Infof("Pulling image: %s", name)
+	c := exec.Command("docker", "pull", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "pull image docker.")
+	}
+	return nil
+}
+
+// SaveImage saves an image from this runtime to an archive
+func (r *Docker) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("docker", "save", "-o", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "saveimage docker.")
+	}
+	return nil
+}
+
+// BuildImage builds an image into this runtime
+func (r *Docker) BuildImage(src string, file string, tag string) error {
+	klog.Infof("Building image: %s", src)
+	args := []string{"build"}
+	if file != "" {
+		args = append(args, "-f", path.Join(src, file))
+	}
+	if tag != "" {
+		args = append(args, "-t", tag)
+	}
+	args = append(args, src)
+	c := exec.Command("docker", args...)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "buildimage docker.")
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Docker) CGroupDriver() (string, error) {
 	// Note: the server daemon has to be running, for this call to return successfully
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor runs preflight checks of the host a cluster is started on
+package doctor
+
+import (
+	"net/url"
+	"sync"
+
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	// Pass means nothing needs to be done
+	Pass Status = "pass"
+	// Warn means a start may work, but not as well as it could
+	Warn Status = "warn"
+	// Fail means a start is expected to fail
+	Fail Status = "fail"
+)
+
+// Result is the result of a check
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	// Reason is the ID of the reason.Kind describing the problem
+	Reason string `json:"reason,omitempty"`
+	// Advice is actionable text that the user should follow
+	Advice string `json:"advice,omitempty"`
+	// URL is a reference URL for more information
+	URL string `json:"url,omitempty"`
+
+	exitCode int
+}
+
+// Check is a single preflight check
+type Check struct {
+	Name string
+	Run  func() Result
+}
+
+// Config is what the checks are run against
+type Config struct {
+	// Driver is the driver to check, picked the way start does when empty
+	Driver string
+	// ClusterIP is the IP of an existing cluster, if any
+	ClusterIP string
+
+	MinCPUs           int
+	MinMemory         int // MiB
+	RecommendedMemory int // MiB
+	MinDisk           int // MiB free in the minikube home
+	RecommendedDisk   int // MiB free in the minikube home
+}
+
+// Checks returns the checks that apply to a config
+func Checks(cfg Config) []Check {
+	ds, rejects := selectDriver(cfg.Driver)
+	name := ds.Name
+
+	checks := []Check{
+		{Name: "driver", Run: func() Result { return driverResult(cfg.Driver, ds, rejects) }},
+		{Name: "cpus", Run: func() Result { return checkCPUs(cfg) }},
+		{Name: "memory", Run: func() Result { return checkMemory(cfg) }},
+		{Name: "disk", Run: func() Result { return checkDisk(cfg) }},
+	}
+	if driver.IsKIC(name) && ds.State.Healthy {
+		checks = append(checks, Check{Name: name, Run: func() Result { return checkDaemon(name, cfg) }})
+	}
+	// an existing cluster listens on these ports itself
+	if driver.BareMetal(name) && cfg.ClusterIP == "" {
+		checks = append(checks, Check{Name: "ports", Run: func() Result { return checkPorts(hostPorts) }})
+	}
+	checks = append(checks, Check{Name: "proxy", Run: func() Result { return checkProxy(name, cfg.ClusterIP) }})
+
+	eps := endpoints(name)
+	checks = append(checks, Check{Name: "dns", Run: func() Result { return checkDNS(eps) }})
+	for _, ep := range eps {
+		ep := ep
+		host := ep
+		if u, err := url.Parse(ep); err == nil {
+			host = u.Host
+		}
+		checks = append(checks, Check{Name: host, Run: func() Result { return checkEndpoint(ep) }})
+	}
+	return checks
+}
+
+// selectDriver returns the state of the driver to check, along with the drivers rejected when it is picked
+func selectDriver(name string) (registry.DriverState, []registry.DriverState) {
+	if name != "" {
+		if !driver.Supported(name) {
+			return registry.DriverState{Name: name}, nil
+		}
+		return driver.Status(name), nil
+	}
+	pick, _, rejects := driver.Suggest(driver.Choices(false))
+	return pick, rejects
+}
+
+// Run runs checks concurrently, and returns their results in order
+func Run(checks []Check) []Result {
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c Check) {
+			defer wg.Done()
+			r := c.Run()
+			r.Name = c.Name
+			results[i] = r
+		}(i, c)
+	}
+	wg.Wait()
+	return results
+}
+
+// ExitCode returns the exit code of the first failed result, or 0 if none failed
+func ExitCode(results []Result) int {
+	for _, r := range results {
+		if r.Status == Fail {
+			return r.exitCode
+		}
+	}
+	return 0
+}
+
+// pass returns a passing result
+func pass(format string, v out.V) Result {
+	return Result{Status: Pass, Message: out.Fmt(format, v)}
+}
+
+// problem returns a warning or failure described by a reason
+func problem(st Status, k reason.Kind, format string, v out.V) Result {
+	r := Result{Status: st, Message: out.Fmt(format, v), Reason: k.ID, URL: k.URL, exitCode: k.ExitCode}
+	if k.Advice != "" {
+		r.Advice = out.Fmt(k.Advice, v)
+	}
+	if r.exitCode == 0 {
+		r.exitCode = 1
+	}
+	return r
+}
+
+// warning returns a warning without a known reason
+func warning(format string, v out.V) Result {
+	return Result{Status: Warn, Message: out.Fmt(format, v)}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+var testConfig = Config{MinCPUs: 2, MinMemory: 1800, RecommendedMemory: 1900, MinDisk: 2000, RecommendedDisk: 20000}
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "a", Run: func() Result { return pass("fine", nil) }},
+		{Name: "b", Run: func() Result {
+			return problem(Fail, reason.RsrcInsufficientCores, "only {{.cpus}} CPUs", map[string]interface{}{"cpus": 1})
+		}},
+		{Name: "c", Run: func() Result { return warning("meh", nil) }},
+	}
+	results := Run(checks)
+
+	var got []string
+	for _, r := range results {
+		got = append(got, fmt.Sprintf("%s %s %s", r.Name, r.Status, r.Message))
+	}
+	want := []string{"a pass fine", "b fail only 1 CPUs", "c warn meh"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if code := ExitCode(results); code != reason.ExInsufficientCores {
+		t.Errorf("ExitCode() = %d, want %d", code, reason.ExInsufficientCores)
+	}
+	if code := ExitCode(results[2:]); code != 0 {
+		t.Errorf("ExitCode() of a warning = %d, want 0", code)
+	}
+
+	data, err := json.Marshal(results[1])
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	wantJSON := `{"name":"b","status":"fail","message":"only 1 CPUs","reason":"RSRC_INSUFFICIENT_CORES"}`
+	if string(data) != wantJSON {
+		t.Errorf("json = %s, want %s", data, wantJSON)
+	}
+}
+
+func TestDriverResult(t *testing.T) {
+	tests := []struct {
+		description string
+		requested   string
+		ds          registry.DriverState
+		status      Status
+		reason      string
+		advice      string
+	}{
+		{
+			description: "healthy",
+			ds:          registry.DriverState{Name: "docker", Priority: registry.HighlyPreferred, State: registry.State{Installed: true, Healthy: true, Running: true}},
+			status:      Pass,
+		},
+		{
+			description: "needs improvement",
+			ds:          registry.DriverState{Name: "docker", Priority: registry.HighlyPreferred, State: registry.State{Installed: true, Healthy: true, Running: true, NeedsImprovement: true, Fix: "enable cgroup v2"}},
+			status:      Warn,
+		},
+		{
+			description: "not installed",
+			requested:   "docker",
+			ds:          registry.DriverState{Name: "docker", State: registry.State{Error: fmt.Errorf("exec: \"docker\": executable file not found in $PATH"), Fix: "Install Docker"}},
+			status:      Fail,
+			reason:      "PROVIDER_DOCKER_NOT_FOUND",
+			advice:      "Install Docker",
+		},
+		{
+			description: "not running",
+			requested:   "docker",
+			ds:          registry.DriverState{Name: "docker", State: registry.State{Installed: true, Error: fmt.Errorf("daemon not running"), Fix: "Start the Docker service"}},
+			status:      Fail,
+			reason:      "PROVIDER_DOCKER_NOT_RUNNING",
+			advice:      "Start the Docker service",
+		},
+		{
+			description: "no default",
+			status:      Fail,
+			reason:      reason.DrvNotDetected.ID,
+		},
+		{
+			description: "unsupported",
+			requested:   "hyperkit-on-linux",
+			ds:          registry.DriverState{Name: "hyperkit-on-linux"},
+			status:      Fail,
+			reason:      reason.DrvUnsupportedOS.ID,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := driverResult(tc.requested, tc.ds, nil)
+			if got.Status != tc.status || got.Reason != tc.reason || got.Advice != tc.advice {
+				t.Errorf("driverResult() = %+v, want status %s, reason %q and advice %q", got, tc.status, tc.reason, tc.advice)
+			}
+		})
+	}
+}
+
+func TestResourceResults(t *testing.T) {
+	tests := []struct {
+		description string
+		got         Result
+		status      Status
+	}{
+		{"enough cpus", cpusResult(4, testConfig), Pass},
+		{"too few cpus", cpusResult(1, testConfig), Fail},
+		{"enough memory", memoryResult(8000, testConfig), Pass},
+		{"low memory", memoryResult(1850, testConfig), Warn},
+		{"too little memory", memoryResult(1000, testConfig), Fail},
+		{"enough disk", diskResult("/home", 50000, testConfig), Pass},
+		{"low disk", diskResult("/home", 5000, testConfig), Warn},
+		{"full disk", diskResult("/home", 100, testConfig), Fail},
+		{"healthy daemon", daemonResult("docker", oci.SysInfo{CPUs: 4, TotalMemory: 8 << 30}, "linux", testConfig), Pass},
+		{"daemon errors", daemonResult("docker", oci.SysInfo{CPUs: 4, TotalMemory: 8 << 30, Errors: []string{"bad"}}, "linux", testConfig), Warn},
+		{"daemon cpus", daemonResult("docker", oci.SysInfo{CPUs: 1, TotalMemory: 8 << 30}, "linux", testConfig), Fail},
+		{"daemon memory", daemonResult("podman", oci.SysInfo{CPUs: 4, TotalMemory: 1 << 30}, "linux", testConfig), Fail},
+	}
+	for _, tc := range tests {
+		if tc.got.Status != tc.status {
+			t.Errorf("%s: got %+v, want status %s", tc.description, tc.got, tc.status)
+		}
+	}
+}
+
+func TestCheckPorts(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	used := l.Addr().(*net.TCPAddr).Port
+
+	r := checkPorts([]int{used})
+	if r.Status != Fail || r.Reason != reason.IfPortInUse.ID {
+		t.Errorf("checkPorts(%d) = %+v, want a failure", used, r)
+	}
+	if !strings.Contains(r.Advice, fmt.Sprint(used)) {
+		t.Errorf("advice %q does not mention port %d", r.Advice, used)
+	}
+}
+
+func TestCheckProxy(t *testing.T) {
+	for _, k := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy"} {
+		defer func(k, v string) { os.Setenv(k, v) }(k, os.Getenv(k))
+		os.Unsetenv(k)
+	}
+
+	if r := checkProxy("docker", ""); r.Status != Pass {
+		t.Errorf("checkProxy() without a proxy = %+v, want a pass", r)
+	}
+
+	os.Setenv("HTTPS_PROXY", "localhost:3128")
+	if r := checkProxy("docker", ""); r.Status != Warn || r.Reason != reason.InetLocalProxy.ID {
+		t.Errorf("checkProxy() with a local proxy = %+v, want a %s warning", r, reason.InetLocalProxy.ID)
+	}
+	if r := checkProxy("none", ""); r.Status != Pass {
+		t.Errorf("checkProxy() with a local proxy and the none driver = %+v, want a pass", r)
+	}
+
+	os.Setenv("HTTPS_PROXY", "proxy.example.com:3128")
+	if r := checkProxy("docker", "192.168.49.2"); r.Status != Warn || r.Reason != reason.InetNoProxy.ID {
+		t.Errorf("checkProxy() without NO_PROXY = %+v, want a %s warning", r, reason.InetNoProxy.ID)
+	}
+	os.Setenv("NO_PROXY", "localhost,192.168.49.0/24")
+	if r := checkProxy("docker", "192.168.49.2"); r.Status != Pass {
+		t.Errorf("checkProxy() with NO_PROXY = %+v, want a pass", r)
+	}
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	if r := checkEndpoint(server.URL + "/v2/"); r.Status != Pass {
+		t.Errorf("checkEndpoint() of a registry = %+v, want a pass", r)
+	}
+	if r := checkEndpoint(server.URL + "/down"); r.Status != Fail {
+		t.Errorf("checkEndpoint() of an unavailable server = %+v, want a failure", r)
+	}
+
+	addr := server.Listener.Addr().String()
+	server.Close()
+	if r := checkEndpoint("http://" + addr + "/"); r.Status != Fail || r.Advice == "" {
+		t.Errorf("checkEndpoint() of an unreachable server = %+v, want a failure with advice", r)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/util"
+)
+
+// hostPorts are the ports the none driver listens on: the apiserver, etcd, the kubelet, the controller manager and the scheduler
+var hostPorts = []int{constants.APIServerPort, 2379, 2380, 10250, 10257, 10259}
+
+// driverResult returns the result of the health check of a driver
+func driverResult(requested string, ds registry.DriverState, rejects []registry.DriverState) Result {
+	name := ds.Name
+	st := ds.State
+	if requested == "" && name == "" {
+		var reasons []string
+		for _, r := range rejects {
+			reasons = append(reasons, fmt.Sprintf("%s (%s)", r.Name, r.Rejection))
+		}
+		return problem(Fail, reason.DrvNotDetected, "Unable to pick a default driver. Drivers considered: {{.rejects}}", out.V{"rejects": strings.Join(reasons, ", ")})
+	}
+	if !driver.Supported(name) {
+		return problem(Fail, reason.DrvUnsupportedOS, "The driver '{{.driver}}' is not supported on {{.os}}", out.V{"driver": name, "os": runtime.GOOS})
+	}
+
+	v := out.V{"driver": name, "error": st.Error, "fix": st.Fix}
+	kind := func(id string, code int) reason.Kind {
+		return reason.Kind{
+			ID:       fmt.Sprintf("PROVIDER_%s_%s", strings.ToUpper(name), id),
+			ExitCode: code,
+			Advice:   st.Fix,
+			URL:      st.Doc,
+		}
+	}
+
+	if ds.Priority == registry.Obsolete {
+		return problem(Fail, kind("OBSOLETE", reason.ExProviderUnsupported), "The '{{.driver}}' driver is obsolete", v)
+	}
+	if !st.Installed {
+		return problem(Fail, kind("NOT_FOUND", reason.ExProviderNotFound), "The '{{.driver}}' provider was not found: {{.error}}", v)
+	}
+	if st.Error != nil {
+		k := kind("ERROR", reason.ExProviderUnavailable)
+		if !st.Running {
+			k = kind("NOT_RUNNING", reason.ExProviderNotRunning)
+		}
+		if k.Advice == "" {
+			if ki := reason.MatchKnownIssue(k, st.Error, runtime.GOOS); ki != nil {
+				k = *ki
+			}
+		}
+		return problem(Fail, k, "The '{{.driver}}' provider is not healthy: {{.error}}", v)
+	}
+	if st.NeedsImprovement {
+		r := warning("For improved {{.driver}} performance, {{.fix}}", v)
+		r.URL = st.Doc
+		return r
+	}
+	return pass("The '{{.driver}}' driver is installed and healthy", v)
+}
+
+// checkCPUs checks the number of CPUs of the host
+func checkCPUs(cfg Config) Result {
+	info, cpuErr, _, _ := machine.LocalHostInfo()
+	if cpuErr != nil {
+		return warning("Unable to get the number of CPUs: {{.error}}", out.V{"error": cpuErr})
+	}
+	return cpusResult(info.CPUs, cfg)
+}
+
+// cpusResult returns the result of the CPU check of the host
+func cpusResult(cpus int, cfg Config) Result {
+	v := out.V{"cpus": cpus, "req": cfg.MinCPUs}
+	if cpus < cfg.MinCPUs {
+		return problem(Fail, reason.RsrcInsufficientCores, "The host only has {{.cpus}} CPUs, less than the required {{.req}} for Kubernetes", v)
+	}
+	return pass("The host has {{.cpus}} CPUs", v)
+}
+
+// checkMemory checks the memory of the host
+func checkMemory(cfg Config) Result {
+	info, _, memErr, _ := machine.LocalHostInfo()
+	if memErr != nil {
+		return warning("Unable to get the memory size: {{.error}}", out.V{"error": memErr})
+	}
+	return memoryResult(int(info.Memory), cfg)
+}
+
+// memoryResult returns the result of the memory check of the host
+func memoryResult(size int, cfg Config) Result {
+	v := out.V{"size": size, "req": cfg.MinMemory, "recommend": cfg.RecommendedMemory}
+	if size < cfg.MinMemory {
+		return problem(Fail, reason.RsrcInsufficientSysMemory, "The host only has {{.size}}MiB of memory, less than the required {{.req}}MiB for Kubernetes", v)
+	}
+	if size < cfg.RecommendedMemory {
+		return problem(Warn, reason.RsrcInsufficientSysMemory, "The host only has {{.size}}MiB of memory, less than the recommended {{.recommend}}MiB. Deployments may fail.", v)
+	}
+	return pass("The host has {{.size}}MiB of memory", v)
+}
+
+// checkDisk checks the free disk space of the minikube home, which holds the cache and the machines
+func checkDisk(cfg Config) Result {
+	dir := localpath.MiniPath()
+	// the minikube home is only created by the first start
+	for {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	d, err := disk.Usage(dir)
+	if err != nil {
+		return warning("Unable to get the free disk space of {{.dir}}: {{.error}}", out.V{"dir": dir, "error": err})
+	}
+	return diskResult(dir, int(util.ConvertUnsignedBytesToMB(d.Free)), cfg)
+}
+
+// diskResult returns the result of the disk check of a directory
+func diskResult(dir string, free int, cfg Config) Result {
+	v := out.V{"dir": dir, "free": free, "req": cfg.MinDisk, "recommend": cfg.RecommendedDisk}
+	if free < cfg.MinDisk {
+		return problem(Fail, reason.RsrcInsufficientStorage, "Only {{.free}}MiB is free in {{.dir}}, less than the required {{.req}}MiB. Free up space, or set MINIKUBE_HOME to a directory on a larger disk.", v)
+	}
+	if free < cfg.RecommendedDisk {
+		return problem(Warn, reason.RsrcInsufficientStorage, "Only {{.free}}MiB is free in {{.dir}}, less than the {{.recommend}}MiB a default cluster may use", v)
+	}
+	return pass("{{.free}}MiB is free in {{.dir}}", v)
+}
+
+// checkDaemon checks the resources and health of the daemon a kic driver runs containers with
+func checkDaemon(name string, cfg Config) Result {
+	si, err := oci.DaemonInfo(name)
+	if err != nil {
+		k := reason.Kind{ID: fmt.Sprintf("PROVIDER_%s_ERROR", strings.ToUpper(name)), ExitCode: reason.ExProviderUnavailable}
+		if ki := reason.MatchKnownIssue(k, err, runtime.GOOS); ki != nil {
+			k = *ki
+		}
+		return problem(Fail, k, "Unable to get the {{.driver}} system info: {{.error}}", out.V{"driver": name, "error": err})
+	}
+	return daemonResult(name, si, runtime.GOOS, cfg)
+}
+
+// daemonResult returns the result of the daemon check of a kic driver
+func daemonResult(name string, si oci.SysInfo, goos string, cfg Config) Result {
+	size := util.ConvertBytesToMB(si.TotalMemory)
+	v := out.V{"driver": name, "cpus": si.CPUs, "size": size, "reqcpus": cfg.MinCPUs, "req": cfg.MinMemory, "recommend": cfg.RecommendedMemory}
+	desktop := driver.IsDockerDesktop(name)
+
+	if si.CPUs < cfg.MinCPUs {
+		k := reason.RsrcInsufficientCores
+		if desktop {
+			k = reason.RsrcInsufficientWindowsDockerCores
+			if goos == "darwin" {
+				k = reason.RsrcInsufficientDarwinDockerCores
+			}
+		}
+		return problem(Fail, k, "{{.driver}} only has {{.cpus}} CPUs available, less than the required {{.reqcpus}} for Kubernetes", v)
+	}
+
+	k := reason.RsrcInsufficientContainerMemory
+	if desktop {
+		k = reason.RsrcInsufficientWindowsDockerMemory
+		if goos == "darwin" {
+			k = reason.RsrcInsufficientDarwinDockerMemory
+		}
+	}
+	if size < cfg.MinMemory {
+		return problem(Fail, k, "{{.driver}} only has {{.size}}MiB available, less than the required {{.req}}MiB for Kubernetes", v)
+	}
+	if len(si.Errors) > 0 {
+		return warning("{{.driver}} reports errors: {{.errors}}", out.V{"driver": name, "errors": strings.Join(si.Errors, "; ")})
+	}
+	if size < cfg.RecommendedMemory {
+		return problem(Warn, k, "{{.driver}} only has {{.size}}MiB available, less than the recommended {{.recommend}}MiB. Deployments may fail.", v)
+	}
+	return pass("{{.driver}} has {{.cpus}} CPUs and {{.size}}MiB of memory available", v)
+}
+
+// checkPorts checks that ports of the host are free to listen on
+func checkPorts(ports []int) Result {
+	for _, p := range ports {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", p))
+		if err != nil {
+			return problem(Fail, reason.IfPortInUse, "Port {{.port}} is not available: {{.error}}", out.V{"port": p, "error": err})
+		}
+		l.Close()
+	}
+	return pass("Ports {{.ports}} are available", out.V{"ports": strings.Trim(fmt.Sprint(ports), "[]")})
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/proxy"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+// timeout is how long a network check waits for an answer
+var timeout = 10 * time.Second
+
+// endpoints returns the locations a start with a driver downloads from: the artifact mirrors, the artifact storage and the image registries
+func endpoints(name string) []string {
+	eps := []string{}
+	for _, m := range download.Mirrors() {
+		if strings.HasPrefix(m, "http") {
+			eps = append(eps, m+"/")
+		}
+	}
+	eps = append(eps, "https://storage.googleapis.com/", fmt.Sprintf("https://%s/v2/", images.DefaultKubernetesRepo))
+	if driver.IsKIC(name) {
+		eps = append(eps, fmt.Sprintf("https://%s/v2/", strings.Split(kic.BaseImage, "/")[0]))
+	}
+	return eps
+}
+
+// checkProxy checks the proxy environment of the host
+func checkProxy(name string, clusterIP string) Result {
+	vars := []string{}
+	for _, k := range []string{"HTTP_PROXY", "HTTPS_PROXY"} {
+		v := proxy.GetEnv(k)
+		if v == "" {
+			continue
+		}
+		vars = append(vars, fmt.Sprintf("%s=%s", k, v))
+		local, err := proxy.IsLocalProxy(v)
+		if err != nil {
+			return problem(Fail, reason.Kind{ID: "INET_PROXY_PARSE", ExitCode: reason.ExInternetConfig}, "Error parsing {{.name}}={{.value}}, {{.error}}", out.V{"name": k, "value": v, "error": err})
+		}
+		if local && !driver.BareMetal(name) {
+			return problem(Warn, reason.InetLocalProxy, "{{.name}}={{.value}} points to the host itself, so it will not be passed to the cluster", out.V{"name": k, "value": v})
+		}
+	}
+	if len(vars) == 0 {
+		return pass("No proxy is configured", out.V{})
+	}
+	if clusterIP != "" && !proxy.IsIPExcluded(clusterIP) {
+		return problem(Warn, reason.InetNoProxy, "You appear to be using a proxy, but your NO_PROXY environment does not include the minikube IP ({{.ip}}).", out.V{"ip": clusterIP})
+	}
+	return pass("Using {{.proxy}}", out.V{"proxy": strings.Join(vars, ", ")})
+}
+
+// checkDNS checks that the hosts of the endpoints resolve
+func checkDNS(eps []string) Result {
+	hosts := []string{}
+	for _, ep := range eps {
+		u, err := url.Parse(ep)
+		if err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		_, err = net.DefaultResolver.LookupHost(ctx, u.Hostname())
+		cancel()
+		if err != nil {
+			st := Fail
+			// the proxy resolves names itself
+			if proxy.GetEnv("HTTPS_PROXY") != "" {
+				st = Warn
+			}
+			return problem(st, reason.InetDNS, "Unable to resolve {{.host}}: {{.error}}", out.V{"host": u.Hostname(), "error": err})
+		}
+		hosts = append(hosts, u.Hostname())
+	}
+	return pass("Resolved {{.hosts}}", out.V{"hosts": strings.Join(hosts, ", ")})
+}
+
+// checkEndpoint checks that a location can be reached, through the proxy if one is configured
+func checkEndpoint(location string) Result {
+	u, err := url.Parse(location)
+	if err != nil {
+		return problem(Fail, reason.InetUnreachable, "Invalid URL {{.url}}: {{.error}}", out.V{"url": location, "host": location, "error": err})
+	}
+	v := out.V{"url": location, "host": u.Host}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(location)
+	if err != nil {
+		k := reason.InetUnreachable
+		if ki := reason.MatchKnownIssue(k, err, runtime.GOOS); ki != nil {
+			k = *ki
+		}
+		v["error"] = err
+		return problem(Fail, k, "Unable to reach {{.host}}: {{.error}}", v)
+	}
+	resp.Body.Close()
+	// any answer but a server error means the location is reachable, as registries require authentication
+	if resp.StatusCode >= http.StatusInternalServerError {
+		v["status"] = resp.Status
+		return problem(Fail, reason.InetUnreachable, "{{.host}} answered {{.status}}", v)
+	}
+	return pass("{{.host}} is reachable", v)
+}
@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-getter"
 	"github.com/pkg/errors"
@@ -29,8 +31,19 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
+const (
+	// downloadAttempts is how many times a location is tried, resuming the partial download, before moving to the next one
+	downloadAttempts = 3
+)
+
 var (
 	mockMode = false
+	offline  = false
+
+	// retryDelay is how long to wait before resuming a download
+	retryDelay = 2 * time.Second
+	// clientError matches the HTTP client errors of the getter
+	clientError = regexp.MustCompile(`bad response code: 4\d\d`)
 )
 
 // EnableMock allows tests to selectively enable if downloads are mocked
@@ -38,26 +51,22 @@ func EnableMock(b bool) {
 	mockMode = b
 }
 
-// download is a well-configured atomic download function
+// SetOffline prevents any download, for caches which were populated beforehand such as from an offline bundle
+func SetOffline(b bool) {
+	offline = b
+}
+
+// IsOffline returns whether downloads are prevented
+func IsOffline() bool {
+	return offline
+}
+
+// download is a well-configured atomic download function.
+// It tries the artifact mirrors in order before src, and checksums are verified whichever location is used.
 func download(src string, dst string) error {
-	progress := getter.WithProgress(DefaultProgressBar)
-	if out.JSON {
-		progress = getter.WithProgress(DefaultJSONOutput)
+	if offline {
+		return fmt.Errorf("not downloading %s while offline, as it should have been cached", src)
 	}
-	tmpDst := dst + ".download"
-	client := &getter.Client{
-		Src:     src,
-		Dst:     tmpDst,
-		Dir:     false,
-		Mode:    getter.ClientModeFile,
-		Options: []getter.ClientOption{progress},
-		Getters: map[string]getter.Getter{
-			"file":  &getter.FileGetter{Copy: false},
-			"http":  &getter.HttpGetter{Netrc: false},
-			"https": &getter.HttpGetter{Netrc: false},
-		},
-	}
-
 	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
 		return errors.Wrap(err, "mkdir")
 	}
@@ -75,11 +84,71 @@ func download(src string, dst string) error {
 		return fmt.Errorf("unmocked download under test")
 	}
 
-	klog.Infof("Downloading: %s -> %s", src, dst)
-	if err := client.Get(); err != nil {
-		return errors.Wrapf(err, "getter: %+v", client)
+	// Mirrored artifacts are verified against the upstream checksum, or the one of the mirror if upstream is unreachable
+	mirrorChecksum := false
+	if c := checksumFile(src); c != "" && len(mirrors) > 0 && !exists(c) {
+		out.WarningT("The upstream checksum {{.url}} is unavailable, verifying {{.name}} against the checksum of the mirror instead", out.V{"url": c, "name": filepath.Base(dst)})
+		mirrorChecksum = true
+	}
+
+	var errs []string
+	for _, location := range locations(src, mirrorChecksum) {
+		err := downloadFrom(location, dst)
+		if err == nil {
+			return nil
+		}
+		klog.Warningf("Unable to download %s: %v", location, err)
+		errs = append(errs, err.Error())
+	}
+	return fmt.Errorf("unable to download %s: %s", src, strings.Join(errs, "; "))
+}
+
+// downloadFrom downloads a single location to dst.
+// A download interrupted by a dropped connection is resumed through HTTP Range requests, both on retry and on the next run,
+// as the partial download is kept until its checksum is verified.
+func downloadFrom(src string, dst string) error {
+	progress := getter.WithProgress(DefaultProgressBar)
+	if out.JSON {
+		progress = getter.WithProgress(DefaultJSONOutput)
+	}
+	tmpDst := dst + ".download"
+	client := &getter.Client{
+		Src:     src,
+		Dst:     tmpDst,
+		Dir:     false,
+		Mode:    getter.ClientModeFile,
+		Options: []getter.ClientOption{progress},
+		Getters: map[string]getter.Getter{
+			"file":  &getter.FileGetter{Copy: true},
+			"http":  &getter.HttpGetter{Netrc: false},
+			"https": &getter.HttpGetter{Netrc: false},
+		},
+	}
+
+	var err error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		if attempt > 1 {
+			klog.Infof("Resuming download of %s (attempt %d/%d) after: %v", src, attempt, downloadAttempts, err)
+			time.Sleep(retryDelay)
+		}
+		klog.Infof("Downloading: %s -> %s", src, dst)
+		if err = client.Get(); err == nil {
+			return os.Rename(tmpDst, dst)
+		}
+
+		var cerr *getter.ChecksumError
+		if errors.As(err, &cerr) {
+			// The partial download is corrupt, or stale: start over
+			klog.Warningf("removing %s: %v", tmpDst, err)
+			os.Remove(tmpDst)
+			continue
+		}
+		if clientError.MatchString(err.Error()) {
+			// Not found or forbidden at this location, which retrying won't fix
+			break
+		}
 	}
-	return os.Rename(tmpDst, dst)
+	return errors.Wrapf(err, "getter: %+v", client)
 }
 
 // withinUnitTset detects if we are in running within a unit-test
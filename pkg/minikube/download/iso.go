@@ -77,6 +77,20 @@ func localISOPath(u *url.URL) string {
 	return filepath.Join(localpath.MiniPath(), "cache", "iso", path.Base(u.Path))
 }
 
+// ISOCached returns whether an ISO URL is a local file, or was already downloaded
+func ISOCached(isoURL string) bool {
+	u, err := url.Parse(isoURL)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == fileScheme {
+		_, err := os.Stat(u.Path)
+		return err == nil
+	}
+	_, err = os.Stat(localISOPath(u))
+	return err == nil
+}
+
 // ISO downloads and returns the path to the downloaded ISO
 func ISO(urls []string, skipChecksum bool) (string, error) {
 	errs := map[string]string{}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// mirrors are the artifact mirrors downloads are tried from, in order, before the upstream location
+var mirrors []string
+
+// SetMirrors sets the artifact mirrors downloads are tried from, in order, before the upstream location.
+// A mirror is an http(s):// or file:// URL serving the artifacts under their upstream paths, without the upstream host,
+// such as <mirror>/minikube/iso/minikube-v1.17.0.iso or <mirror>/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet.
+// Entries may hold several comma separated mirrors.
+func SetMirrors(list []string) {
+	mirrors = nil
+	for _, entry := range list {
+		for _, m := range strings.Split(entry, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				mirrors = append(mirrors, strings.TrimSuffix(m, "/"))
+			}
+		}
+	}
+	if len(mirrors) > 0 {
+		klog.Infof("artifact mirrors: %v", mirrors)
+	}
+}
+
+// Mirrors returns the artifact mirrors downloads are tried from
+func Mirrors() []string {
+	return append([]string{}, mirrors...)
+}
+
+// mirrorURL rewrites an upstream URL to a mirror, keeping its path.
+// The checksum file of a "checksum=file:" query is left upstream, unless mirrorChecksum is set, so that mirrored artifacts
+// are verified against the upstream checksum.
+func mirrorURL(mirror string, src string, mirrorChecksum bool) (string, error) {
+	u, err := url.Parse(src)
+	if err != nil {
+		return "", errors.Wrapf(err, "parse %s", src)
+	}
+	m, err := url.Parse(mirror)
+	if err != nil {
+		return "", errors.Wrapf(err, "parse mirror %s", mirror)
+	}
+	m.Path = path.Join(m.Path, u.Path)
+	m.RawPath = ""
+
+	if u.RawQuery != "" {
+		q := u.Query()
+		if c := q.Get("checksum"); mirrorChecksum && strings.HasPrefix(c, "file:") {
+			cu, err := mirrorURL(mirror, strings.TrimPrefix(c, "file:"), false)
+			if err != nil {
+				return "", err
+			}
+			q.Set("checksum", "file:"+cu)
+		}
+		m.RawQuery = q.Encode()
+	}
+	return m.String(), nil
+}
+
+// checksumFile returns the URL of the checksum file of a "checksum=file:" query, if any
+func checksumFile(src string) string {
+	u, err := url.Parse(src)
+	if err != nil {
+		return ""
+	}
+	if c := u.Query().Get("checksum"); strings.HasPrefix(c, "file:") {
+		return strings.TrimPrefix(c, "file:")
+	}
+	return ""
+}
+
+// locations returns the URLs an artifact is downloaded from, in order: the mirrors, then upstream.
+// mirrorChecksum is passed to mirrorURL.
+func locations(src string, mirrorChecksum bool) []string {
+	if u, err := url.Parse(src); err == nil && u.Scheme == fileScheme {
+		return []string{src}
+	}
+	var urls []string
+	for _, m := range mirrors {
+		u, err := mirrorURL(m, src, mirrorChecksum)
+		if err != nil {
+			klog.Warningf("skipping mirror %s: %v", m, err)
+			continue
+		}
+		urls = append(urls, u)
+	}
+	return append(urls, src)
+}
+
+// exists returns whether an artifact is available at a location
+func exists(location string) bool {
+	u, err := url.Parse(location)
+	if err != nil {
+		klog.Warningf("%s is not a URL: %v", location, err)
+		return false
+	}
+	if u.Scheme == fileScheme {
+		_, err := os.Stat(u.Path)
+		return err == nil
+	}
+
+	resp, err := http.Head(location)
+	if err != nil {
+		klog.Warningf("%s fetch error: %v", location, err)
+		return false
+	}
+	resp.Body.Close()
+	// note: err won't be set if it's a 404
+	if resp.StatusCode != http.StatusOK {
+		klog.Warningf("%s status code: %d", location, resp.StatusCode)
+		return false
+	}
+	return true
+}
+
+// fetchMD5 returns the md5 checksum published in a file, as written by md5sum
+func fetchMD5(location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %s", location)
+	}
+
+	var data []byte
+	if u.Scheme == fileScheme {
+		data, err = ioutil.ReadFile(u.Path)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		resp, err := http.Get(location)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("bad response code: %d", resp.StatusCode)
+		}
+		data, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s is empty", location)
+	}
+	sum, err := hex.DecodeString(fields[0])
+	if err != nil || len(sum) != 16 {
+		return nil, fmt.Errorf("%s does not hold an md5 checksum", location)
+	}
+	return sum, nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMirrorURL(t *testing.T) {
+	tests := []struct {
+		mirror         string
+		src            string
+		mirrorChecksum bool
+		want           string
+	}{
+		{
+			mirror: "https://mirror.example.com/artifacts",
+			src:    "https://storage.googleapis.com/minikube-preloaded-volume-tarballs/preloaded.tar.lz4",
+			want:   "https://mirror.example.com/artifacts/minikube-preloaded-volume-tarballs/preloaded.tar.lz4",
+		},
+		{
+			mirror: "http://10.0.0.1:8080",
+			src:    "https://storage.googleapis.com/minikube/iso/minikube-v1.17.0.iso?checksum=file:https://storage.googleapis.com/minikube/iso/minikube-v1.17.0.iso.sha256",
+			want:   "http://10.0.0.1:8080/minikube/iso/minikube-v1.17.0.iso?checksum=file%3Ahttps%3A%2F%2Fstorage.googleapis.com%2Fminikube%2Fiso%2Fminikube-v1.17.0.iso.sha256",
+		},
+		{
+			mirror:         "http://10.0.0.1:8080",
+			src:            "https://storage.googleapis.com/minikube/iso/minikube-v1.17.0.iso?checksum=file:https://storage.googleapis.com/minikube/iso/minikube-v1.17.0.iso.sha256",
+			mirrorChecksum: true,
+			want:           "http://10.0.0.1:8080/minikube/iso/minikube-v1.17.0.iso?checksum=file%3Ahttp%3A%2F%2F10.0.0.1%3A8080%2Fminikube%2Fiso%2Fminikube-v1.17.0.iso.sha256",
+		},
+		{
+			mirror: "file:///srv/mirror",
+			src:    "https://github.com/kubernetes/minikube/releases/download/v1.17.0/docker-machine-driver-kvm2",
+			want:   "file:///srv/mirror/kubernetes/minikube/releases/download/v1.17.0/docker-machine-driver-kvm2",
+		},
+		{
+			mirror: "https://mirror.example.com",
+			src:    "https://storage.googleapis.com/minikube-preloaded-volume-tarballs/preloaded.tar.lz4?checksum=md5:0123",
+			want:   "https://mirror.example.com/minikube-preloaded-volume-tarballs/preloaded.tar.lz4?checksum=md5%3A0123",
+		},
+	}
+	for _, tc := range tests {
+		got, err := mirrorURL(tc.mirror, tc.src, tc.mirrorChecksum)
+		if err != nil {
+			t.Fatalf("mirrorURL(%s, %s, %v): %v", tc.mirror, tc.src, tc.mirrorChecksum, err)
+		}
+		if got != tc.want {
+			t.Errorf("mirrorURL(%s, %s, %v) = %s, want %s", tc.mirror, tc.src, tc.mirrorChecksum, got, tc.want)
+		}
+	}
+}
+
+func TestChecksumFile(t *testing.T) {
+	sha := "https://storage.googleapis.com/minikube/iso/minikube-v1.17.0.iso.sha256"
+	if got := checksumFile("https://storage.googleapis.com/minikube/iso/minikube-v1.17.0.iso?checksum=file:" + sha); got != sha {
+		t.Errorf("checksumFile() = %q, want %q", got, sha)
+	}
+	if got := checksumFile("https://storage.googleapis.com/preloaded.tar.lz4?checksum=md5:0123"); got != "" {
+		t.Errorf("checksumFile() of an inline checksum = %q, want none", got)
+	}
+}
+
+func TestLocations(t *testing.T) {
+	defer SetMirrors(nil)
+	src := "https://storage.googleapis.com/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet"
+
+	SetMirrors(nil)
+	if diff := cmp.Diff([]string{src}, locations(src, false)); diff != "" {
+		t.Errorf("locations without mirrors mismatch (-want +got):\n%s", diff)
+	}
+
+	SetMirrors([]string{"https://a.example.com/, file:///srv/b", "https://c.example.com"})
+	want := []string{
+		"https://a.example.com/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+		"file:///srv/b/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+		"https://c.example.com/kubernetes-release/release/v1.20.2/bin/linux/amd64/kubelet",
+		src,
+	}
+	if diff := cmp.Diff(want, locations(src, false)); diff != "" {
+		t.Errorf("locations mismatch (-want +got):\n%s", diff)
+	}
+
+	local := "file:///home/user/minikube.iso"
+	if diff := cmp.Diff([]string{local}, locations(local, false)); diff != "" {
+		t.Errorf("locations of a local file mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDownloadFromResume(t *testing.T) {
+	defer func(d time.Duration) { retryDelay = d }(retryDelay)
+	retryDelay = 0
+
+	content := bytes.Repeat([]byte("minikube"), 64*1024)
+	var dropped, ranged int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.Header.Get("Range") == "" && atomic.CompareAndSwapInt32(&dropped, 0, 1) {
+			// drop the connection half way through the first download
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			w.WriteHeader(http.StatusOK)
+			w.Write(content[:len(content)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		if r.Method == http.MethodGet && r.Header.Get("Range") != "" {
+			atomic.AddInt32(&ranged, 1)
+		}
+		http.ServeContent(w, r, "artifact", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	tmpDir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	dst := filepath.Join(tmpDir, "artifact")
+
+	src := fmt.Sprintf("%s/artifact?checksum=sha256:%x", server.URL, sha256.Sum256(content))
+	if err := downloadFrom(src, dst); err != nil {
+		t.Fatalf("downloadFrom: %v", err)
+	}
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("downloaded %d bytes, want %d", len(got), len(content))
+	}
+	if atomic.LoadInt32(&ranged) == 0 {
+		t.Errorf("the dropped download was not resumed with a Range request")
+	}
+}
+
+func TestDownloadFromCorruptPartial(t *testing.T) {
+	defer func(d time.Duration) { retryDelay = d }(retryDelay)
+	retryDelay = 0
+
+	content := []byte("the artifact")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "artifact", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	tmpDir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	dst := filepath.Join(tmpDir, "artifact")
+	// a stale partial download, which resuming would corrupt
+	if err := ioutil.WriteFile(dst+".download", []byte("stale"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	src := fmt.Sprintf("%s/artifact?checksum=md5:%x", server.URL, md5.Sum(content))
+	if err := downloadFrom(src, dst); err != nil {
+		t.Fatalf("downloadFrom: %v", err)
+	}
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("downloaded %q, want %q", got, content)
+	}
+}
+
+func TestDownloadFromNotFound(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&requests, 1)
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	tmpDir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	err = downloadFrom(server.URL+"/missing", filepath.Join(tmpDir, "missing"))
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("downloadFrom() error = %v, want a 404", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("downloadFrom() made %d requests for a missing artifact, want 1", n)
+	}
+}
+
+func TestFetchMD5(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sum := md5.Sum([]byte("preloaded images"))
+	tests := map[string]bool{
+		fmt.Sprintf("%x  preloaded.tar.lz4\n", sum): true,
+		fmt.Sprintf("%x", sum):                      true,
+		"not a checksum":                            false,
+		"":                                          false,
+	}
+	for contents, valid := range tests {
+		p := filepath.Join(tmpDir, "preloaded.tar.lz4.checksum")
+		if err := ioutil.WriteFile(p, []byte(contents), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		got, err := fetchMD5("file://" + filepath.ToSlash(p))
+		if !valid {
+			if err == nil {
+				t.Errorf("fetchMD5(%q) = %x, want an error", contents, got)
+			}
+			continue
+		}
+		if err != nil || !bytes.Equal(got, sum[:]) {
+			t.Errorf("fetchMD5(%q) = %x, %v; want %x", contents, got, err, sum)
+		}
+	}
+}
@@ -20,8 +20,8 @@ import (
 	"context"
 	"crypto/md5"
 	"fmt"
+	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -106,25 +106,22 @@ func PreloadExists(k8sVersion, containerRuntime string, forcePreload ...bool) bo
 		return true
 	}
 
-	url := remoteTarballURL(k8sVersion, containerRuntime)
-	resp, err := http.Head(url)
-	if err != nil {
-		klog.Warningf("%s fetch error: %v", url, err)
+	if offline {
+		klog.Infof("No local preload for %s while offline", targetPath)
 		return false
 	}
 
-	// note: err won't be set if it's a 404
-	if resp.StatusCode != 200 {
-		klog.Warningf("%s status code: %d", url, resp.StatusCode)
-		return false
+	for _, location := range locations(remoteTarballURL(k8sVersion, containerRuntime), false) {
+		if exists(location) {
+			klog.Infof("Found remote preload: %s", location)
+			return true
+		}
 	}
-
-	klog.Infof("Found remote preload: %s", url)
-	return true
+	return false
 }
 
 // Preload caches the preloaded images tarball on the host machine
-func Preload(k8sVersion, containerRuntime string) error {
+func Preload(k8sVersion, containerRuntime string, forcePreload ...bool) error {
 	targetPath := TarballPath(k8sVersion, containerRuntime)
 
 	if _, err := os.Stat(targetPath); err == nil {
@@ -133,7 +130,7 @@ func Preload(k8sVersion, containerRuntime string) error {
 	}
 
 	// Make sure we support this k8s version
-	if !PreloadExists(k8sVersion, containerRuntime) {
+	if !PreloadExists(k8sVersion, containerRuntime, forcePreload...) {
 		klog.Infof("Preloaded tarball for k8s version %s does not exist", k8sVersion)
 		return nil
 	}
@@ -141,34 +138,92 @@ func Preload(k8sVersion, containerRuntime string) error {
 	out.Step(style.FileDownload, "Downloading Kubernetes {{.version}} preload ...", out.V{"version": k8sVersion})
 	url := remoteTarballURL(k8sVersion, containerRuntime)
 
-	if err := download(url, targetPath); err != nil {
-		return errors.Wrapf(err, "download failed: %s", url)
-	}
-
 	if err := saveChecksumFile(k8sVersion, containerRuntime); err != nil {
 		return errors.Wrap(err, "saving checksum file")
 	}
+	checksum, err := ioutil.ReadFile(PreloadChecksumPath(k8sVersion, containerRuntime))
+	if err != nil {
+		return errors.Wrap(err, "reading checksum file")
+	}
+
+	// the checksum is verified by the getter too, which discards a corrupt partial download
+	if err := download(fmt.Sprintf("%s?checksum=md5:%x", url, checksum), targetPath); err != nil {
+		return errors.Wrapf(err, "download failed: %s", url)
+	}
 
 	if err := verifyChecksum(k8sVersion, containerRuntime, targetPath); err != nil {
+		os.Remove(targetPath)
 		return errors.Wrap(err, "verify")
 	}
 
 	return nil
 }
 
-func saveChecksumFile(k8sVersion, containerRuntime string) error {
-	klog.Infof("saving checksum for %s ...", TarballName(k8sVersion, containerRuntime))
+// SavePreload moves a locally built preload tarball into the cache along with its checksum,
+// so that it is used instead of the remote one
+func SavePreload(src, k8sVersion, containerRuntime string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return errors.Wrap(err, "open tarball")
+	}
+	h := md5.New()
+	_, err = io.Copy(h, f)
+	f.Close()
+	if err != nil {
+		return errors.Wrap(err, "reading tarball")
+	}
+
+	if err := os.MkdirAll(targetDir(), 0o755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if err := ioutil.WriteFile(PreloadChecksumPath(k8sVersion, containerRuntime), h.Sum(nil), 0o644); err != nil {
+		return errors.Wrap(err, "saving checksum file")
+	}
+	return os.Rename(src, TarballPath(k8sVersion, containerRuntime))
+}
+
+// upstreamPreloadChecksum returns the md5 checksum of a preload tarball from the attributes of the upstream bucket
+var upstreamPreloadChecksum = func(k8sVersion, containerRuntime string) ([]byte, error) {
 	ctx := context.Background()
 	client, err := storage.NewClient(ctx, option.WithoutAuthentication())
 	if err != nil {
-		return errors.Wrap(err, "getting storage client")
+		return nil, errors.Wrap(err, "getting storage client")
 	}
 	attrs, err := client.Bucket(PreloadBucket).Object(TarballName(k8sVersion, containerRuntime)).Attrs(ctx)
 	if err != nil {
-		return errors.Wrap(err, "getting storage object")
+		return nil, errors.Wrap(err, "getting storage object")
 	}
-	checksum := attrs.MD5
-	return ioutil.WriteFile(PreloadChecksumPath(k8sVersion, containerRuntime), checksum, 0o644)
+	return attrs.MD5, nil
+}
+
+// saveChecksumFile saves the checksum of a preload tarball, which is taken from upstream even when the tarball comes from a mirror.
+// The checksum published by a mirror is only used when upstream is unreachable.
+func saveChecksumFile(k8sVersion, containerRuntime string) error {
+	klog.Infof("saving checksum for %s ...", TarballName(k8sVersion, containerRuntime))
+	checksum, err := upstreamPreloadChecksum(k8sVersion, containerRuntime)
+	if err == nil {
+		return ioutil.WriteFile(PreloadChecksumPath(k8sVersion, containerRuntime), checksum, 0o644)
+	}
+	if len(mirrors) == 0 {
+		return err
+	}
+	klog.Warningf("unable to get the upstream checksum of %s: %v", TarballName(k8sVersion, containerRuntime), err)
+
+	// mirrors publish the checksum next to the tarball, as the bucket attributes are only available upstream
+	for _, m := range mirrors {
+		location, merr := mirrorURL(m, remoteTarballURL(k8sVersion, containerRuntime)+".checksum", false)
+		if merr != nil {
+			continue
+		}
+		checksum, merr := fetchMD5(location)
+		if merr != nil {
+			klog.Warningf("unable to get checksum from %s: %v", location, merr)
+			continue
+		}
+		out.WarningT("The upstream checksum of {{.name}} is unavailable, verifying it against the checksum of mirror {{.mirror}} instead", out.V{"name": TarballName(k8sVersion, containerRuntime), "mirror": m})
+		return ioutil.WriteFile(PreloadChecksumPath(k8sVersion, containerRuntime), checksum, 0o644)
+	}
+	return err
 }
 
 // verifyChecksum returns true if the checksum of the local binary matches
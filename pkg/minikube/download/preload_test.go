@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestSavePreload(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "preload")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmpDir)
+
+	src := filepath.Join(tmpDir, "built.tar.lz4")
+	if err := ioutil.WriteFile(src, []byte("preloaded images"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := SavePreload(src, "v1.20.2", "crio"); err != nil {
+		t.Fatalf("SavePreload: %v", err)
+	}
+
+	dst := TarballPath("v1.20.2", "cri-o")
+	if _, err := os.Stat(dst); err != nil {
+		t.Fatalf("tarball is not in the cache: %v", err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Errorf("tarball was not moved, stat %s: %v", src, err)
+	}
+	if err := verifyChecksum("v1.20.2", "cri-o", dst); err != nil {
+		t.Errorf("verifyChecksum: %v", err)
+	}
+	if !PreloadExists("v1.20.2", "cri-o", true) {
+		t.Errorf("PreloadExists() = false for a saved preload")
+	}
+}
+
+func TestPreloadMirror(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "preload")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, filepath.Join(tmpDir, "home"))
+	defer SetMirrors(nil)
+	mirror := filepath.Join(tmpDir, "mirror")
+	SetMirrors([]string{"file://" + filepath.ToSlash(mirror)})
+
+	mirrored, err := mirrorURL(mirrors[0], remoteTarballURL("v1.20.2", "containerd"), false)
+	if err != nil {
+		t.Fatalf("mirrorURL: %v", err)
+	}
+	if exists(mirrored) {
+		t.Errorf("exists(%s) = true, but the mirror is empty", mirrored)
+	}
+
+	tarball := filepath.Join(mirror, PreloadBucket, TarballName("v1.20.2", "containerd"))
+	if err := os.MkdirAll(filepath.Dir(tarball), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	contents := []byte("preloaded images")
+	if err := ioutil.WriteFile(tarball, contents, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	sum := md5.Sum(contents)
+	if err := ioutil.WriteFile(tarball+".checksum", []byte(fmt.Sprintf("%x  %s\n", sum, filepath.Base(tarball))), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if !PreloadExists("v1.20.2", "containerd", true) {
+		t.Errorf("PreloadExists() = false, but the mirror has the tarball")
+	}
+	if err := os.MkdirAll(targetDir(), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	// The upstream checksum is preferred over the one of the mirror
+	defer func(f func(string, string) ([]byte, error)) { upstreamPreloadChecksum = f }(upstreamPreloadChecksum)
+	upstreamPreloadChecksum = func(string, string) ([]byte, error) { return []byte("upstream"), nil }
+	if err := saveChecksumFile("v1.20.2", "containerd"); err != nil {
+		t.Fatalf("saveChecksumFile: %v", err)
+	}
+	if err := verifyChecksum("v1.20.2", "containerd", tarball); err == nil {
+		t.Errorf("verifyChecksum used the mirror checksum while upstream was reachable")
+	}
+
+	upstreamPreloadChecksum = func(string, string) ([]byte, error) { return nil, fmt.Errorf("unreachable") }
+	if err := saveChecksumFile("v1.20.2", "containerd"); err != nil {
+		t.Fatalf("saveChecksumFile: %v", err)
+	}
+	if err := verifyChecksum("v1.20.2", "containerd", tarball); err != nil {
+		t.Errorf("verifyChecksum against the mirror checksum: %v", err)
+	}
+}
@@ -180,6 +180,11 @@ func HasResourceLimits(name string) bool {
 	return name != None
 }
 
+// HasNodeResources returns true if driver can give each node of a cluster its own resource limits
+func HasNodeResources(name string) bool {
+	return IsKIC(name) || name == KVM2
+}
+
 // NeedsShutdown returns true if driver needs manual shutdown command before stopping.
 // Hyper-V requires special care to avoid ACPI and file locking issues
 // KIC also needs shutdown to avoid container getting stuck, https://github.com/kubernetes/minikube/issues/7657
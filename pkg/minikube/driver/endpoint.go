@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 
+	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -28,7 +29,21 @@ import (
 // ControlPlaneEndpoint returns the location where callers can reach this cluster
 func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName string) (string, net.IP, int, error) {
 	if NeedsPortForward(driverName) {
-		port, err := oci.ForwardedPort(cc.Driver, cc.Name, cp.Port)
+		// The virtual IP of a highly available cluster is not reachable from the host, so the port of the node is used,
+		// failing over to the port of another control plane while the node is stopped
+		port, err := oci.ForwardedPort(cc.Driver, config.MachineName(*cc, *cp), cp.Port)
+		if err != nil && config.IsHA(*cc) {
+			for _, n := range config.ControlPlanes(*cc) {
+				if n.Name == cp.Name {
+					continue
+				}
+				if p, perr := oci.ForwardedPort(cc.Driver, config.MachineName(*cc, n), n.Port); perr == nil {
+					klog.Infof("control plane %s is unreachable (%v), using control plane %s", cp.Name, err, n.Name)
+					port, err = p, nil
+					break
+				}
+			}
+		}
 		hostname := oci.DaemonHost(driverName)
 
 		ip := net.ParseIP(hostname)
@@ -43,14 +58,20 @@ func ControlPlaneEndpoint(cc *config.ClusterConfig, cp *config.Node, driverName
 		return hostname, ip, port, err
 	}
 
+	// The control plane of a highly available cluster is reached through its virtual IP, whichever node holds it
+	cpIP := cp.IP
+	if config.IsHA(*cc) {
+		cpIP = cc.KubernetesConfig.APIServerHAVIP
+	}
+
 	// https://github.com/kubernetes/minikube/issues/3878
-	hostname := cp.IP
+	hostname := cpIP
 	if cc.KubernetesConfig.APIServerName != constants.APIServerName {
 		hostname = cc.KubernetesConfig.APIServerName
 	}
-	ip := net.ParseIP(cp.IP)
+	ip := net.ParseIP(cpIP)
 	if ip == nil {
-		return hostname, ip, cp.Port, fmt.Errorf("failed to parse ip for %q", cp.IP)
+		return hostname, ip, cp.Port, fmt.Errorf("failed to parse ip for %q", cpIP)
 	}
 	return hostname, ip, cp.Port, nil
 }
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// registryAuths are the credentials of the profile, tried before the ones of the host when pulling images
+var registryAuths []config.RegistryAuth
+
+// UseRegistryAuths makes the registry credentials of a profile available to image pulls
+func UseRegistryAuths(auths []config.RegistryAuth) {
+	registryAuths = auths
+}
+
+// profileKeychain resolves the registry credentials set by UseRegistryAuths
+type profileKeychain struct{}
+
+// Resolve returns the credentials of the registry, anonymous when it has none
+func (profileKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	for _, a := range registryAuths {
+		host := a.Host()
+		if a.IsDockerHub() {
+			host = name.DefaultRegistry
+		}
+		if host == target.RegistryStr() {
+			return &authn.Basic{Username: a.Username, Password: a.Password}, nil
+		}
+	}
+	return authn.Anonymous, nil
+}
+
+// keychain returns the credentials to pull images with
+func keychain() authn.Keychain {
+	return authn.NewMultiKeychain(profileKeychain{}, authn.DefaultKeychain)
+}
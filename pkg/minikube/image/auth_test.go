@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestProfileKeychain(t *testing.T) {
+	defer UseRegistryAuths(nil)
+	UseRegistryAuths([]config.RegistryAuth{
+		{Server: "registry.example.com", Username: "user", Password: "pass"},
+		{Server: "docker.io", Username: "hub", Password: "secret"},
+	})
+
+	var tests = []struct {
+		image    string
+		username string
+	}{
+		{"registry.example.com/app:v1", "user"},
+		{"busybox", "hub"},
+		{"gcr.io/k8s-minikube/storage-provisioner:v4", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.image, func(t *testing.T) {
+			ref, err := name.ParseReference(tc.image)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			a, err := profileKeychain{}.Resolve(ref.Context())
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if tc.username == "" {
+				if a != authn.Anonymous {
+					t.Errorf("expected anonymous credentials, got %+v", a)
+				}
+				return
+			}
+			cfg, err := a.Authorization()
+			if err != nil {
+				t.Fatalf("Authorization: %v", err)
+			}
+			if cfg.Username != tc.username {
+				t.Errorf("username = %q, want %q", cfg.Username, tc.username)
+			}
+		})
+	}
+}
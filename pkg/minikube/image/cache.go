@@ -47,6 +47,11 @@ func DeleteFromCacheDir(images []string) error {
 	return cleanImageCacheDir()
 }
 
+// CachePath returns where an image is stored in an image cache directory
+func CachePath(cacheDir string, img string) string {
+	return localpath.SanitizeCacheDir(filepath.Join(cacheDir, img))
+}
+
 // SaveToDir will cache images on the host
 //
 // The cache directory currently caches images using the imagename_tag
@@ -57,8 +62,7 @@ func SaveToDir(images []string, cacheDir string) error {
 	for _, image := range images {
 		image := image
 		g.Go(func() error {
-			dst := filepath.Join(cacheDir, image)
-			dst = localpath.SanitizeCacheDir(dst)
+			dst := CachePath(cacheDir, image)
 			if err := saveToTarFile(image, dst); err != nil {
 				klog.Errorf("save image to file %q -> %q failed: %v", image, dst, err)
 				return errors.Wrapf(err, "caching image %q", dst)
@@ -28,7 +28,6 @@ import (
 	"time"
 
 	"github.com/docker/docker/client"
-	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/daemon"
@@ -37,6 +36,7 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/localpath"
 )
@@ -142,6 +142,9 @@ func WriteImageToDaemon(img string) error {
 	if err != nil {
 		return errors.Wrap(err, "parsing reference")
 	}
+	if download.IsOffline() {
+		return fmt.Errorf("not pulling %s while offline", img)
+	}
 	klog.V(3).Infof("Getting image %v", ref)
 	i, err := remote.Image(ref)
 	if err != nil {
@@ -176,8 +179,12 @@ func retrieveImage(ref name.Reference) (v1.Image, error) {
 		klog.Infof("daemon lookup for %+v: %v", ref, err)
 	}
 
+	if download.IsOffline() {
+		return nil, fmt.Errorf("%s is not cached while offline", ref.Name())
+	}
+
 	platform := defaultPlatform
-	img, err = remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithPlatform(platform))
+	img, err = remote.Image(ref, remote.WithAuthFromKeychain(keychain()), remote.WithPlatform(platform))
 	if err == nil {
 		return img, nil
 	}
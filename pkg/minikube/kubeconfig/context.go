@@ -24,7 +24,7 @@ import (
 
 // UnsetCurrentContext unsets the current-context from minikube to "" on minikube stop
 func UnsetCurrentContext(machineName string, configPath ...string) error {
-	fPath := PathFromEnv()
+	fPath := PathFor(machineName)
 	if configPath != nil {
 		fPath = configPath[0]
 	}
@@ -47,7 +47,7 @@ func UnsetCurrentContext(machineName string, configPath ...string) error {
 
 // SetCurrentContext sets the kubectl's current-context
 func SetCurrentContext(name string, configPath ...string) error {
-	fPath := PathFromEnv()
+	fPath := PathFor(name)
 	if configPath != nil {
 		fPath = configPath[0]
 	}
@@ -61,7 +61,7 @@ func SetCurrentContext(name string, configPath ...string) error {
 
 // DeleteContext deletes the specified machine's kubeconfig context
 func DeleteContext(machineName string, configPath ...string) error {
-	fPath := PathFromEnv()
+	fPath := PathFor(machineName)
 	if configPath != nil {
 		fPath = configPath[0]
 	}
@@ -88,3 +88,40 @@ func DeleteContext(machineName string, configPath ...string) error {
 	}
 	return nil
 }
+
+// DeleteUser deletes a context and its user, keeping the cluster which other contexts may use
+func DeleteUser(contextName string, configPath ...string) error {
+	fPath := PathFromEnv()
+	if configPath != nil {
+		fPath = configPath[0]
+	}
+	kcfg, err := readOrNew(fPath)
+	if err != nil {
+		return errors.Wrap(err, "Error getting kubeconfig status")
+	}
+
+	if !deleteUser(kcfg, contextName) {
+		klog.V(2).Infof("context %q not found in kubeconfig", contextName)
+		return nil
+	}
+
+	if err := writeToFile(kcfg, fPath); err != nil {
+		return errors.Wrap(err, "writing kubeconfig")
+	}
+	return nil
+}
+
+// deleteUser deletes a context and its user from a config, and returns whether the context was found
+func deleteUser(kcfg *api.Config, contextName string) bool {
+	ctx, ok := kcfg.Contexts[contextName]
+	if !ok {
+		return false
+	}
+	delete(kcfg.AuthInfos, ctx.AuthInfo)
+	delete(kcfg.Contexts, contextName)
+
+	if kcfg.CurrentContext == contextName {
+		kcfg.CurrentContext = ""
+	}
+	return true
+}
@@ -49,6 +49,43 @@ func TestDeleteContext(t *testing.T) {
 	}
 }
 
+func TestDeleteUser(t *testing.T) {
+	fn := tempFile(t, kubeConfigWithoutHTTPS)
+	defer os.Remove(fn)
+	cfg, err := readOrNew(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kcs := &Settings{ClusterName: "la-croix", UserName: "alice@la-croix", ContextName: "alice@la-croix", ClusterServerAddress: "https://192.168.10.100:8443"}
+	if err := PopulateFromSettings(kcs, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CurrentContext != "alice@la-croix" || cfg.Contexts["alice@la-croix"].AuthInfo != "alice@la-croix" {
+		t.Fatalf("PopulateFromSettings() did not add the alice@la-croix context: %+v", cfg)
+	}
+	if !deleteUser(cfg, "alice@la-croix") {
+		t.Fatalf("deleteUser() did not find the alice@la-croix context")
+	}
+	if deleteUser(cfg, "alice@la-croix") {
+		t.Errorf("deleteUser() found the alice@la-croix context twice")
+	}
+	if _, ok := cfg.Contexts["alice@la-croix"]; ok {
+		t.Errorf("context alice@la-croix was not deleted")
+	}
+	if _, ok := cfg.AuthInfos["alice@la-croix"]; ok {
+		t.Errorf("user alice@la-croix was not deleted")
+	}
+	if _, ok := cfg.Contexts["la-croix"]; !ok {
+		t.Errorf("context la-croix was deleted")
+	}
+	if _, ok := cfg.Clusters["la-croix"]; !ok {
+		t.Errorf("cluster la-croix was deleted")
+	}
+	if cfg.CurrentContext != "" {
+		t.Errorf("current context = %q, want none", cfg.CurrentContext)
+	}
+}
+
 func TestSetCurrentContext(t *testing.T) {
 	f, err := ioutil.TempFile("/tmp", "kubeconfig")
 	if err != nil {
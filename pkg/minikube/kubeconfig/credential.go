@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauth "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// ExecCredentialAPIVersion is the version of the ExecCredential the credential plugin returns
+const ExecCredentialAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+// ExecCredentialPlugin returns the credential plugin which runs minikube to get the client certificate of a profile,
+// or of one of its users if user is set
+func ExecCredentialPlugin(profile string, user string) (*api.ExecConfig, error) {
+	bin, err := os.Executable()
+	if err != nil {
+		return nil, errors.Wrap(err, "minikube executable")
+	}
+	args := []string{"kubeconfig", "credential", "--profile", profile}
+	if user != "" {
+		args = append(args, "--user", user)
+	}
+	ec := &api.ExecConfig{Command: bin, Args: args, APIVersion: ExecCredentialAPIVersion}
+	if home := os.Getenv(localpath.MinikubeHome); home != "" {
+		ec.Env = []api.ExecEnvVar{{Name: localpath.MinikubeHome, Value: home}}
+	}
+	return ec, nil
+}
+
+// ExecCredential returns the ExecCredential JSON of a client certificate and key, which expires with the certificate
+func ExecCredential(certPath string, keyPath string) ([]byte, error) {
+	cert, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading ClientCertificate %s", certPath)
+	}
+	key, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading ClientKey %s", keyPath)
+	}
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM certificate found in %s", certPath)
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %s", certPath)
+	}
+
+	ec := clientauth.ExecCredential{
+		TypeMeta: metav1.TypeMeta{APIVersion: ExecCredentialAPIVersion, Kind: "ExecCredential"},
+		Status: &clientauth.ExecCredentialStatus{
+			ExpirationTimestamp:   &metav1.Time{Time: c.NotAfter},
+			ClientCertificateData: string(cert),
+			ClientKeyData:         string(key),
+		},
+	}
+	return json.Marshal(ec)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	clientauth "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestExecCredential(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	certPath := filepath.Join(tmpDir, "client.crt")
+	keyPath := filepath.Join(tmpDir, "client.key")
+	if err := util.GenerateCACert(certPath, keyPath, "minikube-user"); err != nil {
+		t.Fatalf("GenerateCACert: %v", err)
+	}
+
+	data, err := ExecCredential(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("ExecCredential: %v", err)
+	}
+	var ec clientauth.ExecCredential
+	if err := json.Unmarshal(data, &ec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if ec.APIVersion != ExecCredentialAPIVersion || ec.Kind != "ExecCredential" {
+		t.Errorf("ExecCredential() is a %s %s, want a %s ExecCredential", ec.APIVersion, ec.Kind, ExecCredentialAPIVersion)
+	}
+	cert, _ := ioutil.ReadFile(certPath)
+	key, _ := ioutil.ReadFile(keyPath)
+	if ec.Status == nil || ec.Status.ClientCertificateData != string(cert) || ec.Status.ClientKeyData != string(key) {
+		t.Fatalf("ExecCredential() status = %+v, want the certificate and key", ec.Status)
+	}
+	if d := time.Until(ec.Status.ExpirationTimestamp.Time); d < 9*365*24*time.Hour {
+		t.Errorf("ExecCredential() expires in %s, want the expiry of the certificate", d)
+	}
+
+	if _, err := ExecCredential(filepath.Join(tmpDir, "missing.crt"), keyPath); err == nil {
+		t.Errorf("ExecCredential() of a missing certificate should have returned an error")
+	}
+}
+
+func TestExecCredentialPlugin(t *testing.T) {
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, "/home/minikube")
+
+	ec, err := ExecCredentialPlugin("p1", "alice")
+	if err != nil {
+		t.Fatalf("ExecCredentialPlugin: %v", err)
+	}
+	wantArgs := []string{"kubeconfig", "credential", "--profile", "p1", "--user", "alice"}
+	if diff := cmp.Diff(wantArgs, ec.Args); diff != "" {
+		t.Errorf("ExecCredentialPlugin() args mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]api.ExecEnvVar{{Name: localpath.MinikubeHome, Value: "/home/minikube"}}, ec.Env); diff != "" {
+		t.Errorf("ExecCredentialPlugin() env mismatch (-want +got):\n%s", diff)
+	}
+	if !filepath.IsAbs(ec.Command) {
+		t.Errorf("ExecCredentialPlugin() command = %q, want an absolute path", ec.Command)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	ca := filepath.Join(tmpDir, "ca.crt")
+	if err := ioutil.WriteFile(ca, []byte("ca"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg := api.NewConfig()
+	kcs := &Settings{ClusterName: "p1", ClusterServerAddress: "https://192.168.49.2:8443", CertificateAuthority: ca, ClientCertificate: "client.crt", ClientKey: "client.key", ExecCredential: ec}
+	if err := PopulateFromSettings(kcs, cfg); err != nil {
+		t.Fatalf("PopulateFromSettings: %v", err)
+	}
+	user := cfg.AuthInfos["p1"]
+	if user.Exec == nil || user.Exec.Command != ec.Command || user.ClientCertificate != "" || user.ClientKey != "" {
+		t.Errorf("PopulateFromSettings() user = %+v, want the exec credential plugin only", user)
+	}
+	if string(cfg.Clusters["p1"].CertificateAuthorityData) != "ca" {
+		t.Errorf("PopulateFromSettings() did not embed the certificate authority")
+	}
+}
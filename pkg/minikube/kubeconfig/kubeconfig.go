@@ -38,7 +38,7 @@ import (
 
 // VerifyEndpoint verifies the IP:port stored in kubeconfig.
 func VerifyEndpoint(contextName string, hostname string, port int, configPath ...string) error {
-	path := PathFromEnv()
+	path := PathFor(contextName)
 	if configPath != nil {
 		path = configPath[0]
 	}
@@ -75,9 +75,25 @@ func PathFromEnv() string {
 	return constants.KubeconfigPath
 }
 
+// ProfilePath returns the path of the kubeconfig file of a profile which has its own, instead of sharing the one from the environment
+func ProfilePath(profile string) string {
+	return filepath.Join(localpath.Profile(profile), "kubeconfig")
+}
+
+// PathFor returns the path of the kubeconfig file with the context of a profile: its own file if it has one, or else the one from the environment
+func PathFor(profile string) string {
+	if profile == "" {
+		return PathFromEnv()
+	}
+	if _, err := os.Stat(ProfilePath(profile)); err == nil {
+		return ProfilePath(profile)
+	}
+	return PathFromEnv()
+}
+
 // Endpoint returns the IP:port address stored for minikube in the kubeconfig specified
 func Endpoint(contextName string, configPath ...string) (string, int, error) {
-	path := PathFromEnv()
+	path := PathFor(contextName)
 	if configPath != nil {
 		path = configPath[0]
 	}
@@ -105,7 +121,8 @@ func Endpoint(contextName string, configPath ...string) (string, int, error) {
 }
 
 // UpdateEndpoint overwrites the IP stored in kubeconfig with the provided IP.
-func UpdateEndpoint(contextName string, hostname string, port int, confpath string, ext *Extension) (bool, error) {
+// If the cluster is missing from kubeconfig, it is recreated to trust caCert.
+func UpdateEndpoint(contextName string, hostname string, port int, confpath string, caCert string, ext *Extension) (bool, error) {
 	if hostname == "" {
 		return false, fmt.Errorf("empty ip")
 	}
@@ -127,13 +144,12 @@ func UpdateEndpoint(contextName string, hostname string, port int, confpath stri
 	if _, ok := cfg.Clusters[contextName]; !ok {
 		klog.Infof("%q context is missing from %s - will repair!", contextName, confpath)
 		lp := localpath.Profile(contextName)
-		gp := localpath.MiniPath()
 		kcs := &Settings{
 			ClusterName:          contextName,
 			ClusterServerAddress: address,
 			ClientCertificate:    path.Join(lp, "client.crt"),
 			ClientKey:            path.Join(lp, "client.key"),
-			CertificateAuthority: path.Join(gp, "ca.crt"),
+			CertificateAuthority: caCert,
 			KeepContext:          false,
 		}
 		if ext != nil {
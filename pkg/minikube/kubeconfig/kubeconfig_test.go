@@ -410,7 +410,7 @@ func TestUpdateIP(t *testing.T) {
 			t.Parallel()
 			configFilename := tempFile(t, test.existing)
 			defer os.Remove(configFilename)
-			statusActual, err := UpdateEndpoint("minikube", test.hostname, test.port, configFilename, nil)
+			statusActual, err := UpdateEndpoint("minikube", test.hostname, test.port, configFilename, "/home/la-croix/.minikube/ca.crt", nil)
 			if err != nil && !test.err {
 				t.Errorf("Got unexpected error: %v", err)
 			}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+)
+
+// Prune removes the contexts and clusters minikube added to a kubeconfig for profiles which no longer exist, and their users.
+// exists returns whether a profile exists. Prune returns the names of the removed contexts and clusters.
+func Prune(exists func(profile string) bool, configPath ...string) ([]string, error) {
+	fPath := PathFromEnv()
+	if configPath != nil {
+		fPath = configPath[0]
+	}
+	kcfg, err := readOrNew(fPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting kubeconfig status")
+	}
+
+	pruned := prune(kcfg, exists)
+	if len(pruned) == 0 {
+		klog.Infof("no stale minikube entries in %s", fPath)
+		return pruned, nil
+	}
+	if err := writeToFile(kcfg, fPath); err != nil {
+		return nil, errors.Wrap(err, "writing kubeconfig")
+	}
+	return pruned, nil
+}
+
+// prune removes the entries of profiles which no longer exist from a config, and returns their names.
+// The clusters minikube adds are named after their profile, and its contexts refer to them.
+func prune(kcfg *api.Config, exists func(profile string) bool) []string {
+	pruned := map[string]bool{}
+	users := map[string]bool{}
+
+	for name, ctx := range kcfg.Contexts {
+		if !isMinikube(ctx.Extensions, "context_info") || exists(ctx.Cluster) {
+			continue
+		}
+		klog.Infof("pruning context %q of deleted profile %q", name, ctx.Cluster)
+		users[ctx.AuthInfo] = true
+		delete(kcfg.Contexts, name)
+		if kcfg.CurrentContext == name {
+			kcfg.CurrentContext = ""
+		}
+		pruned[name] = true
+	}
+
+	for name, cluster := range kcfg.Clusters {
+		if !isMinikube(cluster.Extensions, "cluster_info") || exists(name) {
+			continue
+		}
+		klog.Infof("pruning cluster %q of deleted profile", name)
+		users[name] = true
+		delete(kcfg.Clusters, name)
+		pruned[name] = true
+	}
+
+	// keep the users which remaining contexts still refer to
+	for _, ctx := range kcfg.Contexts {
+		delete(users, ctx.AuthInfo)
+	}
+	for name := range users {
+		delete(kcfg.AuthInfos, name)
+	}
+
+	names := []string{}
+	for name := range pruned {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isMinikube returns whether the extensions of a kubeconfig entry contain the minikube extension
+func isMinikube(extensions map[string]runtime.Object, key string) bool {
+	var ext Extension
+	switch o := extensions[key].(type) {
+	case *Extension:
+		ext = *o
+	case *runtime.Unknown:
+		if err := json.Unmarshal(o.Raw, &ext); err != nil {
+			klog.Warningf("unable to parse the %s extension: %v", key, err)
+			return false
+		}
+	default:
+		return false
+	}
+	return ext.Provider == NewExtension().Provider
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+var kubeConfigWithStaleProfiles = []byte(`apiVersion: v1
+clusters:
+- cluster:
+    server: https://192.168.49.2:8443
+    extensions:
+    - extension:
+        provider: minikube.sigs.k8s.io
+        version: v1.17.0
+      name: cluster_info
+  name: live
+- cluster:
+    server: https://192.168.58.2:8443
+    extensions:
+    - extension:
+        provider: minikube.sigs.k8s.io
+        version: v1.17.0
+      name: cluster_info
+  name: gone
+- cluster:
+    server: https://gke.example.com
+  name: gke
+contexts:
+- context:
+    cluster: live
+    user: live
+    extensions:
+    - extension:
+        provider: minikube.sigs.k8s.io
+        version: v1.17.0
+      name: context_info
+  name: live
+- context:
+    cluster: gone
+    user: gone
+    extensions:
+    - extension:
+        provider: minikube.sigs.k8s.io
+        version: v1.17.0
+      name: context_info
+  name: gone
+- context:
+    cluster: gone
+    user: alice@gone
+    extensions:
+    - extension:
+        provider: minikube.sigs.k8s.io
+        version: v1.17.0
+      name: context_info
+  name: alice@gone
+- context:
+    cluster: gke
+    user: gke
+  name: gke
+current-context: gone
+kind: Config
+preferences: {}
+users:
+- name: live
+  user:
+    client-certificate: /home/la-croix/.minikube/profiles/live/client.crt
+- name: gone
+  user:
+    client-certificate: /home/la-croix/.minikube/profiles/gone/client.crt
+- name: alice@gone
+  user:
+    client-certificate: /home/la-croix/.minikube/profiles/gone/users/alice.crt
+- name: gke
+  user:
+    token: secret
+`)
+
+func TestPrune(t *testing.T) {
+	cfg, err := decode(kubeConfigWithStaleProfiles)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	exists := func(profile string) bool { return profile == "live" }
+
+	got := prune(cfg, exists)
+	if diff := cmp.Diff([]string{"alice@gone", "gone"}, got); diff != "" {
+		t.Errorf("prune() mismatch (-want +got):\n%s", diff)
+	}
+	left := map[string]bool{}
+	for name := range cfg.Clusters {
+		left["cluster "+name] = true
+	}
+	for name := range cfg.Contexts {
+		left["context "+name] = true
+	}
+	for name := range cfg.AuthInfos {
+		left["user "+name] = true
+	}
+	want := map[string]bool{"cluster live": true, "cluster gke": true, "context live": true, "context gke": true, "user live": true, "user gke": true}
+	if diff := cmp.Diff(want, left); diff != "" {
+		t.Errorf("entries left after prune() mismatch (-want +got):\n%s", diff)
+	}
+	if cfg.CurrentContext != "" {
+		t.Errorf("current context = %q, want none", cfg.CurrentContext)
+	}
+	if got := prune(cfg, exists); len(got) != 0 {
+		t.Errorf("prune() twice = %v, want nothing", got)
+	}
+}
+
+func TestPathFor(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmpDir)
+
+	if got := PathFor("p1"); got != PathFromEnv() {
+		t.Errorf("PathFor() of a profile without its own kubeconfig = %q, want %q", got, PathFromEnv())
+	}
+	own := ProfilePath("p1")
+	if filepath.Dir(own) != localpath.Profile("p1") {
+		t.Errorf("ProfilePath() = %q, want a file in %s", own, localpath.Profile("p1"))
+	}
+	if err := os.MkdirAll(filepath.Dir(own), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(own, nil, 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := PathFor("p1"); got != own {
+		t.Errorf("PathFor() of a profile with its own kubeconfig = %q, want %q", got, own)
+	}
+	if got := PathFor("p2"); got != PathFromEnv() {
+		t.Errorf("PathFor() of another profile = %q, want %q", got, PathFromEnv())
+	}
+}
@@ -37,6 +37,10 @@ type Settings struct {
 	// The name of the namespace for this context
 	Namespace string
 
+	// The name of the user and the context, which default to the name of the cluster
+	UserName    string
+	ContextName string
+
 	// ClusterServerAddress is the address of the Kubernetes cluster
 	ClusterServerAddress string
 
@@ -55,6 +59,10 @@ type Settings struct {
 	// Should the certificate files be embedded instead of referenced by path
 	EmbedCerts bool
 
+	// ExecCredential is the credential plugin which returns the client certificate and key, if set instead of ClientCertificate and ClientKey.
+	// The certificate authority is embedded, so that the entry keeps working if the profile is moved.
+	ExecCredential *api.ExecConfig
+
 	// Extension meta data for the cluster
 	ExtensionCluster *Extension
 
@@ -82,7 +90,7 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 	clusterName := cfg.ClusterName
 	cluster := api.NewCluster()
 	cluster.Server = cfg.ClusterServerAddress
-	if cfg.EmbedCerts {
+	if cfg.EmbedCerts || cfg.ExecCredential != nil {
 		cluster.CertificateAuthorityData, err = ioutil.ReadFile(cfg.CertificateAuthority)
 		if err != nil {
 			return errors.Wrapf(err, "reading CertificateAuthority %s", cfg.CertificateAuthority)
@@ -98,8 +106,14 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 
 	// user
 	userName := cfg.ClusterName
+	if cfg.UserName != "" {
+		userName = cfg.UserName
+	}
 	user := api.NewAuthInfo()
-	if cfg.EmbedCerts {
+	switch {
+	case cfg.ExecCredential != nil:
+		user.Exec = cfg.ExecCredential.DeepCopy()
+	case cfg.EmbedCerts:
 		user.ClientCertificateData, err = ioutil.ReadFile(cfg.ClientCertificate)
 		if err != nil {
 			return errors.Wrapf(err, "reading ClientCertificate %s", cfg.ClientCertificate)
@@ -108,7 +122,7 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 		if err != nil {
 			return errors.Wrapf(err, "reading ClientKey %s", cfg.ClientKey)
 		}
-	} else {
+	default:
 		user.ClientCertificate = cfg.ClientCertificate
 		user.ClientKey = cfg.ClientKey
 	}
@@ -116,6 +130,9 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 
 	// context
 	contextName := cfg.ClusterName
+	if cfg.ContextName != "" {
+		contextName = cfg.ContextName
+	}
 	context := api.NewContext()
 	context.Cluster = cfg.ClusterName
 	context.Namespace = cfg.Namespace
@@ -128,7 +145,7 @@ func PopulateFromSettings(cfg *Settings, apiCfg *api.Config) error {
 
 	// Only set current context to minikube if the user has not used the keepContext flag
 	if !cfg.KeepContext {
-		apiCfg.CurrentContext = cfg.ClusterName
+		apiCfg.CurrentContext = contextName
 	}
 
 	return nil
@@ -0,0 +1,232 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// buildRoot is where images should be built from within the guest VM
+var buildRoot = path.Join(vmpath.GuestPersistentDir, "build")
+
+// BuildImage builds an image from a local context directory or archive on the nodes of the given profile, and returns the machines it was built on.
+// Unless allNodes is set, the image is only built on nodeName, or the primary control plane if it is empty, which must be running.
+func BuildImage(src string, file string, tag string, allNodes bool, nodeName string, profile *config.Profile) ([]string, error) {
+	api, err := NewAPIClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "api")
+	}
+	defer api.Close()
+
+	var cc *config.ClusterConfig
+	target := ""
+	if !allNodes {
+		if cc, target, err = buildTarget(api, profile.Name, nodeName); err != nil {
+			return nil, err
+		}
+	}
+
+	archive := src
+	fi, err := os.Stat(src)
+	if err != nil {
+		return nil, errors.Wrap(err, "build context")
+	}
+	if fi.IsDir() {
+		archive, err = tarContext(src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "archiving build context %s", src)
+		}
+		defer os.Remove(archive)
+	}
+
+	// other nodes are left alone, so that one which is broken does not fail a build which is not meant for it
+	if target != "" {
+		h, err := api.Load(target)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load %s", target)
+		}
+		runner, err := CommandRunner(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "command runner %s", target)
+		}
+		if err := transferAndBuildImage(runner, cc.KubernetesConfig, archive, file, tag); err != nil {
+			return nil, err
+		}
+		return []string{target}, nil
+	}
+
+	var built []string
+	err = forEachRunningNode([]*config.Profile{profile}, func(cc *config.ClusterConfig, n config.Node, runner command.Runner) error {
+		if err := transferAndBuildImage(runner, cc.KubernetesConfig, archive, file, tag); err != nil {
+			return err
+		}
+		built = append(built, config.MachineName(*cc, n))
+		return nil
+	})
+	return built, err
+}
+
+// buildTarget returns the config of a cluster and the machine of the node an image is built on: nodeName, or the primary control plane if it is empty.
+// The node must exist and be running.
+func buildTarget(api libmachine.API, profile string, nodeName string) (*config.ClusterConfig, string, error) {
+	cc, err := config.Load(profile)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "loading profile")
+	}
+	var target *config.Node
+	if nodeName == "" {
+		cp, err := config.PrimaryControlPlane(cc)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "primary control plane")
+		}
+		target = &cp
+	}
+	for i, n := range cc.Nodes {
+		if nodeName != "" && (n.Name == nodeName || config.MachineName(*cc, n) == nodeName) {
+			target = &cc.Nodes[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, "", fmt.Errorf("node %q does not exist in cluster %q", nodeName, profile)
+	}
+
+	m := config.MachineName(*cc, *target)
+	st, err := Status(api, m)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "status %s", m)
+	}
+	if st != state.Running.String() {
+		return nil, "", fmt.Errorf("node %s is not running (state=%s)", m, st)
+	}
+	return cc, m, nil
+}
+
+// transferAndBuildImage transfers a build context archive to a node and builds it with the container runtime
+func transferAndBuildImage(runner command.Runner, k8s config.KubernetesConfig, archive string, file string, tag string) error {
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: runner})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	name := fmt.Sprintf("build.%d", time.Now().UnixNano())
+	dir := path.Join(buildRoot, name)
+	f, err := assets.NewFileAsset(archive, buildRoot, name+".tar", "0644")
+	if err != nil {
+		return errors.Wrapf(err, "creating copyable file asset: %s", archive)
+	}
+	if err := runner.Copy(f); err != nil {
+		return errors.Wrap(err, "transferring build context")
+	}
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-rf", dir, path.Join(buildRoot, name+".tar"))); err != nil {
+			klog.Warningf("unable to clean up build context %s: %v", dir, err)
+		}
+	}()
+
+	if _, err := runner.RunCmd(exec.Command("sudo", "mkdir", "-p", dir)); err != nil {
+		return errors.Wrapf(err, "mkdir %s", dir)
+	}
+	if _, err := runner.RunCmd(exec.Command("sudo", "tar", "-C", dir, "-xf", path.Join(buildRoot, name+".tar"))); err != nil {
+		return errors.Wrap(err, "extracting build context")
+	}
+
+	start := time.Now()
+	if err := r.BuildImage(dir, file, tag); err != nil {
+		return errors.Wrapf(err, "%s build %s", r.Name(), archive)
+	}
+	klog.Infof("Built %s from %s in %s", tag, archive, time.Since(start))
+	return nil
+}
+
+// tarContext writes the contents of a directory to a temporary tar archive and returns its path
+func tarContext(dir string) (string, error) {
+	f, err := ioutil.TempFile("", "build.*.tar")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		// tar archives always use forward slashes, even on Windows
+		hdr.Name = strings.ReplaceAll(rel, string(filepath.Separator), "/")
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := tw.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
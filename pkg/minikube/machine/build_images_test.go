@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTarContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "context")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"Dockerfile":   "FROM busybox\nCOPY app/run.sh /\n",
+		"app/run.sh":   "#!/bin/sh\necho hello\n",
+		"app/.keep.md": "",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	archive, err := tarContext(dir)
+	if err != nil {
+		t.Fatalf("tarContext: %v", err)
+	}
+	defer os.Remove(archive)
+
+	f, err := os.Open(archive)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	got := map[string]string{}
+	dirs := []string{}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			dirs = append(dirs, hdr.Name)
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got[hdr.Name] = string(b)
+	}
+
+	if diff := cmp.Diff(files, got); diff != "" {
+		t.Errorf("archive contents diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"app"}, dirs); diff != "" {
+		t.Errorf("archive directories diff (-want +got):\n%s", diff)
+	}
+}
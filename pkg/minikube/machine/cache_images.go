@@ -19,8 +19,10 @@ package machine
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -102,7 +104,7 @@ func LoadImages(cc *config.ClusterConfig, runner command.Runner, images []string
 				return nil
 			}
 			klog.Infof("%q needs transfer: %v", image, err)
-			return transferAndLoadImage(runner, cc.KubernetesConfig, image, cacheDir)
+			return transferAndLoadCachedImage(runner, cc.KubernetesConfig, image, cacheDir)
 		})
 	}
 	if err := g.Wait(); err != nil {
@@ -168,20 +170,147 @@ func CacheAndLoadImages(images []string) error {
 		return errors.Wrap(err, "save to dir")
 	}
 
+	profiles, _, err := config.ListProfiles() // need to load image to all profiles
+	if err != nil {
+		return errors.Wrap(err, "list profiles")
+	}
+
+	if err := DoLoadImages(images, profiles, constants.ImageCacheDir); err != nil {
+		// Live pushes are not considered a failure
+		klog.Warningf("Failed to load cached images: %v", err)
+	}
+	return nil
+}
+
+// DoLoadImages loads previously cached images into every running node of the given profiles
+func DoLoadImages(images []string, profiles []*config.Profile, cacheDir string) error {
+	if len(images) == 0 {
+		return nil
+	}
+	return forEachRunningNode(profiles, func(cc *config.ClusterConfig, _ config.Node, runner command.Runner) error {
+		return LoadImages(cc, runner, images, cacheDir)
+	})
+}
+
+// LoadArchives transfers and loads image archives into every running node of the given profiles
+func LoadArchives(archives []string, profiles []*config.Profile) error {
+	return forEachRunningNode(profiles, func(cc *config.ClusterConfig, _ config.Node, runner command.Runner) error {
+		for _, archive := range archives {
+			if err := transferAndLoadImage(runner, cc.KubernetesConfig, archive); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveImages removes images from every running node of the given profile
+func RemoveImages(images []string, profile *config.Profile) error {
+	return forEachRunningNode([]*config.Profile{profile}, func(cc *config.ClusterConfig, _ config.Node, runner command.Runner) error {
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			return errors.Wrap(err, "runtime")
+		}
+		for _, img := range images {
+			if err := cr.RemoveImage(img); err != nil {
+				return errors.Wrapf(err, "%s remove %s", cr.Name(), img)
+			}
+		}
+		return nil
+	})
+}
+
+// PullImages pulls images into every running node of the given profile
+func PullImages(images []string, profile *config.Profile) error {
+	return forEachRunningNode([]*config.Profile{profile}, func(cc *config.ClusterConfig, _ config.Node, runner command.Runner) error {
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			return errors.Wrap(err, "runtime")
+		}
+		for _, img := range images {
+			if err := cr.PullImage(img); err != nil {
+				return errors.Wrapf(err, "%s pull %s", cr.Name(), img)
+			}
+		}
+		return nil
+	})
+}
+
+// ListImages returns the images available on any running node of the given profile
+func ListImages(profile *config.Profile) ([]string, error) {
+	seen := map[string]bool{}
+	err := forEachRunningNode([]*config.Profile{profile}, func(cc *config.ClusterConfig, _ config.Node, runner command.Runner) error {
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			return errors.Wrap(err, "runtime")
+		}
+		images, err := cr.ListImages()
+		if err != nil {
+			return errors.Wrapf(err, "%s list images", cr.Name())
+		}
+		for _, img := range images {
+			seen[img] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	images := []string{}
+	for img := range seen {
+		images = append(images, img)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// SaveImage saves an image from the container runtime of a node into an archive on the host
+func SaveImage(cc *config.ClusterConfig, runner command.Runner, img string, dst string) error {
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	// the docker client writes the archive as the ssh user, so save into a directory it owns
+	rr, err := runner.RunCmd(exec.Command("mktemp", "-d"))
+	if err != nil {
+		return errors.Wrap(err, "mktemp")
+	}
+	dir := strings.TrimSpace(rr.Stdout.String())
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-rf", dir)); err != nil {
+			klog.Warningf("unable to remove %s: %v", dir, err)
+		}
+	}()
+	src := path.Join(dir, filepath.Base(localpath.SanitizeCacheDir(img)))
+	if err := cr.SaveImage(img, src); err != nil {
+		return errors.Wrapf(err, "%s save %s", cr.Name(), img)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrapf(err, "making directory: %s", filepath.Dir(dst))
+	}
+	if err := runner.CopyFrom(src, dst); err != nil {
+		return errors.Wrapf(err, "transferring %s", src)
+	}
+
+	klog.Infof("Saved %s to %s", img, dst)
+	return nil
+}
+
+// forEachRunningNode calls fn with a command runner for each running node of the given profiles
+func forEachRunningNode(profiles []*config.Profile, fn func(*config.ClusterConfig, config.Node, command.Runner) error) error {
 	api, err := NewAPIClient()
 	if err != nil {
 		return errors.Wrap(err, "api")
 	}
 	defer api.Close()
-	profiles, _, err := config.ListProfiles() // need to load image to all profiles
-	if err != nil {
-		return errors.Wrap(err, "list profiles")
-	}
 
 	succeeded := []string{}
 	failed := []string{}
 
-	for _, p := range profiles { // loading images to all running profiles
+	for _, p := range profiles {
 		pName := p.Name // capture the loop variable
 
 		c, err := config.Load(pName)
@@ -202,43 +331,53 @@ func CacheAndLoadImages(images []string) error {
 				continue
 			}
 
-			if status == state.Running.String() { // the not running hosts will load on next start
-				h, err := api.Load(m)
-				if err != nil {
-					klog.Warningf("Failed to load machine %q: %v", m, err)
-					failed = append(failed, m)
-					continue
-				}
-				cr, err := CommandRunner(h)
-				if err != nil {
-					return err
-				}
-				err = LoadImages(c, cr, images, constants.ImageCacheDir)
-				if err != nil {
-					failed = append(failed, m)
-					klog.Warningf("Failed to load cached images for profile %s. make sure the profile is running. %v", pName, err)
-					continue
-				}
-				succeeded = append(succeeded, m)
+			if status != state.Running.String() { // the not running hosts will catch up on next start
+				continue
+			}
+
+			h, err := api.Load(m)
+			if err != nil {
+				klog.Warningf("Failed to load machine %q: %v", m, err)
+				failed = append(failed, m)
+				continue
+			}
+			runner, err := CommandRunner(h)
+			if err != nil {
+				klog.Warningf("Failed to get command runner for %q: %v", m, err)
+				failed = append(failed, m)
+				continue
+			}
+			if err := fn(c, n, runner); err != nil {
+				klog.Warningf("Failed on %s for profile %s: %v", m, pName, err)
+				failed = append(failed, m)
+				continue
 			}
+			succeeded = append(succeeded, m)
 		}
 	}
 
-	klog.Infof("succeeded pushing to: %s", strings.Join(succeeded, " "))
-	klog.Infof("failed pushing to: %s", strings.Join(failed, " "))
-	// Live pushes are not considered a failure
+	klog.Infof("succeeded on: %s", strings.Join(succeeded, " "))
+	klog.Infof("failed on: %s", strings.Join(failed, " "))
+	if len(failed) > 0 {
+		return fmt.Errorf("failed on: %s", strings.Join(failed, " "))
+	}
 	return nil
 }
 
-// transferAndLoadImage transfers and loads a single image from the cache
-func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgName string, cacheDir string) error {
+// transferAndLoadCachedImage transfers and loads a single image from the cache
+func transferAndLoadCachedImage(cr command.Runner, k8s config.KubernetesConfig, imgName string, cacheDir string) error {
+	src := filepath.Join(cacheDir, imgName)
+	src = localpath.SanitizeCacheDir(src)
+	klog.Infof("Loading image from cache: %s", src)
+	return transferAndLoadImage(cr, k8s, src)
+}
+
+// transferAndLoadImage transfers and loads a single image archive from the host
+func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, src string) error {
 	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
 	if err != nil {
 		return errors.Wrap(err, "runtime")
 	}
-	src := filepath.Join(cacheDir, imgName)
-	src = localpath.SanitizeCacheDir(src)
-	klog.Infof("Loading image from cache: %s", src)
 	filename := filepath.Base(src)
 	if _, err := os.Stat(src); err != nil {
 		return err
@@ -260,6 +399,6 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgNam
 		return errors.Wrapf(err, "%s load %s", r.Name(), dst)
 	}
 
-	klog.Infof("Transferred and loaded %s from cache", src)
+	klog.Infof("Transferred and loaded %s", src)
 	return nil
 }
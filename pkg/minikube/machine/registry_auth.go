@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/util"
+)
+
+// ConfigureRegistryAuth writes the registry credentials of a profile into the container runtime of each of its running nodes
+func ConfigureRegistryAuth(profile *config.Profile, auths []config.RegistryAuth) error {
+	return forEachRunningNode([]*config.Profile{profile}, func(cc *config.ClusterConfig, n config.Node, runner command.Runner) error {
+		kv, err := util.ParseKubernetesVersion(config.NodeKubernetesVersion(*cc, n))
+		if err != nil {
+			return errors.Wrap(err, "kubernetes version")
+		}
+		cr, err := cruntime.New(cruntime.Config{
+			Type:              cc.KubernetesConfig.ContainerRuntime,
+			Runner:            runner,
+			ImageRepository:   cc.KubernetesConfig.ImageRepository,
+			KubernetesVersion: kv,
+			RegistryMirror:    cc.RegistryMirror,
+			InsecureRegistry:  cc.InsecureRegistry,
+			RegistryAuth:      auths,
+		})
+		if err != nil {
+			return errors.Wrap(err, "runtime")
+		}
+		if err := cr.ConfigureRegistryAuth(); err != nil {
+			return errors.Wrapf(err, "%s registry credentials", cr.Name())
+		}
+		return nil
+	})
+}
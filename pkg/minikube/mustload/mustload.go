@@ -96,6 +96,17 @@ func Running(name string) ClusterController {
 		exit.Error(reason.GuestStatus, "Unable to get machine status", err)
 	}
 
+	// A highly available cluster keeps running while any of its control planes does
+	if hs != state.Running.String() && config.IsHA(*cc) {
+		for _, n := range config.ControlPlanes(*cc) {
+			if s, err := machine.Status(api, config.MachineName(*cc, n)); err == nil && s == state.Running.String() {
+				klog.Infof("primary control plane is %s, using control plane %s", hs, n.Name)
+				cp, machineName, hs = n, config.MachineName(*cc, n), s
+				break
+			}
+		}
+	}
+
 	if hs == state.None.String() {
 		out.Step(style.Shrug, `The control plane node "{{.name}}" does not exist.`, out.V{"name": cp.Name})
 		exitTip("start", name, reason.ExGuestNotFound)
@@ -111,7 +122,7 @@ func Running(name string) ClusterController {
 		exitTip("start", name, reason.ExSvcUnavailable)
 	}
 
-	host, err := machine.LoadHost(api, name)
+	host, err := machine.LoadHost(api, machineName)
 	if err != nil {
 		exit.Error(reason.GuestLoadHost, "Unable to load host", err)
 	}
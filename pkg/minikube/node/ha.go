@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/util"
+)
+
+// StartSecondaryControlPlanes boots the control planes of a highly available cluster other than the primary one,
+// and restarts their static pods. etcd on the primary control plane can not serve without a quorum of its peers,
+// so they must be running before the primary control plane is waited for.
+func StartSecondaryControlPlanes(cc *config.ClusterConfig, delOnFail bool) error {
+	sv, err := util.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse Kubernetes version")
+	}
+
+	for _, n := range config.ControlPlanes(*cc) {
+		n := n
+		if config.IsPrimaryControlPlane(*cc, n) {
+			continue
+		}
+
+		// A running cluster being upgraded has its quorum already: the node is upgraded after the primary
+		upgrade, err := bsutil.NeedsUpgrade(*cc, n)
+		if err != nil {
+			return err
+		}
+		if upgrade {
+			klog.Infof("control plane %s will be upgraded, not restarting it early", n.Name)
+			continue
+		}
+
+		r, preExists, api, _, err := Provision(cc, &n, false, delOnFail)
+		if err != nil {
+			return errors.Wrapf(err, "provisioning %s", n.Name)
+		}
+		// A control plane which never finished joining has no etcd member to restore
+		if !preExists {
+			continue
+		}
+
+		bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, r)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get bootstrapper")
+		}
+
+		cr := configureRuntimes(r, *cc, sv)
+		if err := bs.SetupCerts(cc.KubernetesConfig, n); err != nil {
+			return errors.Wrap(err, "setting up certs")
+		}
+		if err := bs.UpdateNode(*cc, n, cr); err != nil {
+			return errors.Wrap(err, "update node")
+		}
+		if err := bs.RestartControlPlaneNode(*cc, n); err != nil {
+			return errors.Wrapf(err, "restarting control plane %s", n.Name)
+		}
+	}
+	return nil
+}
@@ -19,15 +19,20 @@ package node
 import (
 	"fmt"
 	"os/exec"
+	"sync"
 
+	"github.com/docker/machine/libmachine"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
 )
 
 // TODO: Share these between cluster and node packages
@@ -36,23 +41,101 @@ const (
 	createMount = "mount"
 )
 
+// DefaultParallelism is the number of nodes started at the same time by default
+const DefaultParallelism = 3
+
 // Add adds a new node config to an existing cluster.
 func Add(cc *config.ClusterConfig, n config.Node, delOnFail bool) error {
 	if err := config.SaveNode(cc, &n); err != nil {
 		return errors.Wrap(err, "save node")
 	}
+	return provisionAndStart(cc, &n, delOnFail)
+}
+
+// AddNodes adds worker nodes to an existing cluster, starting up to parallel of them at a time.
+// Control plane nodes must be added one by one with Add, as kubeadm joins them one at a time.
+// The failure of each node is reported as it happens, and its error returned by node name.
+func AddNodes(cc *config.ClusterConfig, nodes []config.Node, parallel int, delOnFail bool) map[string]error {
+	errs := map[string]error{}
+
+	// Nodes are saved upfront, so that the nodes of the cluster config are not reallocated while in use
+	for i := range nodes {
+		if err := config.SaveNode(cc, &nodes[i]); err != nil {
+			errs[nodes[i].Name] = errors.Wrap(err, "save node")
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if parallel < 1 {
+		parallel = 1
+	}
+	sem := make(chan struct{}, parallel)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n config.Node) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// Each node is started with its own copy of the cluster config, as starting a node updates it,
+			// and the node is merged back into the shared config once it has started
+			mu.Lock()
+			own := *cc
+			own.Nodes = append([]config.Node(nil), cc.Nodes...)
+			mu.Unlock()
+
+			name := config.MachineName(own, n)
+			if out.JSON {
+				register.PrintNodeStep(name, register.StartingNode, fmt.Sprintf("Starting node %s", name))
+			}
+			if err := provisionAndStart(&own, &n, delOnFail); err != nil {
+				klog.Errorf("node %s failed to start: %v", name, err)
+				if out.JSON {
+					register.PrintNodeError(name, err.Error())
+				} else {
+					out.FailureT("Node {{.name}} failed to start: {{.error}}", out.V{"name": name, "error": err})
+				}
+				mu.Lock()
+				errs[n.Name] = err
+				mu.Unlock()
+				return
+			}
+			mu.Lock()
+			err := config.SaveNode(cc, &n)
+			mu.Unlock()
+			if err != nil {
+				klog.Errorf("unable to save node %s: %v", name, err)
+			}
+			if out.JSON {
+				register.PrintNodeStep(name, register.Done, fmt.Sprintf("Node %s is ready", name))
+			}
+		}(n)
+	}
+	wg.Wait()
+	return errs
+}
 
-	r, p, m, h, err := Provision(cc, &n, false, delOnFail)
+// provisionAndStart provisions the machine of a node which is already saved in the cluster config, and joins it to the cluster
+func provisionAndStart(cc *config.ClusterConfig, n *config.Node, delOnFail bool) error {
+	r, p, m, h, err := Provision(cc, n, false, delOnFail)
 	if err != nil {
 		return err
 	}
+	if out.JSON {
+		name := config.MachineName(*cc, *n)
+		register.PrintNodeStep(name, register.PreparingKubernetes, fmt.Sprintf("Joining node %s to the cluster", name))
+	}
 	s := Starter{
 		Runner:         r,
 		PreExists:      p,
 		MachineAPI:     m,
 		Host:           h,
 		Cfg:            cc,
-		Node:           &n,
+		Node:           n,
 		ExistingAddons: nil,
 	}
 
@@ -93,6 +176,13 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 		klog.Infof("successfully scaled coredns replicas to 1")
 	}
 
+	// kubeadm reset removes the etcd member of a control plane, so that the remaining members keep their quorum
+	if n.ControlPlane {
+		if err := resetControlPlane(api, cc, *n); err != nil {
+			klog.Warningf("unable to reset control plane %s: %v", n.Name, err)
+		}
+	}
+
 	// kubectl delete
 	client, err := kapi.Client(cc.Name)
 	if err != nil {
@@ -113,6 +203,20 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 	return n, config.SaveProfile(viper.GetString(config.ProfileName), &cc)
 }
 
+// resetControlPlane runs kubeadm reset on a control plane node
+func resetControlPlane(api libmachine.API, cc config.ClusterConfig, n config.Node) error {
+	h, err := machine.LoadHost(api, config.MachineName(cc, n))
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	_, err = r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("%s reset --force", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion))))
+	return err
+}
+
 // Retrieve finds the node by name in the given cluster
 func Retrieve(cc config.ClusterConfig, name string) (*config.Node, int, error) {
 	if driver.BareMetal(cc.Driver) {
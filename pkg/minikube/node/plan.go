@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cni"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/image"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// Artifact is a file or image which starting a cluster downloads to the host, unless it is cached
+type Artifact struct {
+	Kind   string `json:"kind"` // iso, base-image, preload, image or binary
+	Name   string `json:"name"`
+	Cached bool   `json:"cached"`
+}
+
+// Artifacts returns what starting the primary control plane of a cluster downloads to the host, mirroring Provision
+func Artifacts(cc *config.ClusterConfig) ([]Artifact, error) {
+	as := []Artifact{}
+	if driver.IsVM(cc.Driver) && !driver.IsSSH(cc.Driver) && cc.MinikubeISO != "" {
+		as = append(as, Artifact{Kind: "iso", Name: cc.MinikubeISO, Cached: download.ISOCached(cc.MinikubeISO)})
+	}
+	if cc.Driver == driver.Docker {
+		cached := image.ExistsImageInDaemon(cc.KicBaseImage) || exists(image.CachePath(constants.ImageCacheDir, cc.KicBaseImage))
+		as = append(as, Artifact{Kind: "base-image", Name: cc.KicBaseImage, Cached: cached})
+	}
+	if driver.BareMetal(cc.Driver) {
+		return as, nil
+	}
+
+	k8s := cc.KubernetesConfig
+	// TODO: remove imageRepository check once #7695 is fixed
+	if k8s.ImageRepository == "" && download.PreloadExists(k8s.KubernetesVersion, k8s.ContainerRuntime) {
+		name := download.TarballName(k8s.KubernetesVersion, k8s.ContainerRuntime)
+		cached := exists(download.TarballPath(k8s.KubernetesVersion, k8s.ContainerRuntime))
+		// the binaries are preloaded as well
+		return append(as, Artifact{Kind: "preload", Name: name, Cached: cached}), nil
+	}
+
+	bs := viper.GetString(cmdcfg.Bootstrapper)
+	if viper.GetBool(cacheImages) {
+		imgs, err := bootstrapper.GetCachedImageList(k8s.ImageRepository, k8s.KubernetesVersion, bs)
+		if err != nil {
+			return nil, errors.Wrap(err, "kubernetes images")
+		}
+		for _, img := range imgs {
+			as = append(as, Artifact{Kind: "image", Name: img, Cached: exists(image.CachePath(constants.ImageCacheDir, img))})
+		}
+	}
+	for _, bin := range bootstrapper.GetCachedBinaryList(bs) {
+		p := localpath.MakeMiniPath("cache", "linux", k8s.KubernetesVersion, bin)
+		as = append(as, Artifact{Kind: "binary", Name: fmt.Sprintf("%s %s", bin, k8s.KubernetesVersion), Cached: exists(p)})
+	}
+	return as, nil
+}
+
+// Steps returns the steps starting a cluster runs, in order.
+// addons are the addons the start enables, and nodes the number of nodes of the cluster.
+func Steps(cc *config.ClusterConfig, existing bool, addons []string, nodes int) ([]string, error) {
+	k8s := cc.KubernetesConfig
+	steps := []string{}
+
+	switch {
+	case existing:
+		steps = append(steps, fmt.Sprintf("Restart the existing %s cluster %q", cc.Driver, cc.Name))
+	case driver.BareMetal(cc.Driver):
+		steps = append(steps, "Configure the local host environment")
+	case driver.IsSSH(cc.Driver):
+		steps = append(steps, fmt.Sprintf("Provision %s over SSH", cc.SSHIPAddress))
+	case driver.IsKIC(cc.Driver):
+		steps = append(steps, fmt.Sprintf("Create a %s container with %d CPUs and %dMB of memory", cc.Driver, cc.CPUs, cc.Memory))
+	default:
+		steps = append(steps, fmt.Sprintf("Create a %s VM with %d CPUs, %dMB of memory and a %dMB disk", cc.Driver, cc.CPUs, cc.Memory, cc.DiskSize))
+	}
+
+	steps = append(steps, fmt.Sprintf("Prepare Kubernetes %s on %s", k8s.KubernetesVersion, k8s.ContainerRuntime))
+	if existing {
+		steps = append(steps, "Reconfigure the control plane")
+	} else {
+		steps = append(steps, "Generate certificates and keys", "Boot up the control plane", "Configure RBAC rules")
+	}
+
+	cnm, err := cni.New(*cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "cni")
+	}
+	if _, ok := cnm.(cni.Disabled); !ok {
+		steps = append(steps, fmt.Sprintf("Configure %s (Container Networking Interface)", cnm))
+	}
+
+	steps = append(steps, "Verify Kubernetes components")
+	if len(addons) > 0 {
+		steps = append(steps, fmt.Sprintf("Enable addons: %s", strings.Join(addons, ", ")))
+	}
+	switch {
+	case nodes == 2:
+		steps = append(steps, "Start 1 more node")
+	case nodes > 2:
+		steps = append(steps, fmt.Sprintf("Start %d more nodes", nodes-1))
+	}
+
+	if cc.KeepContext {
+		steps = append(steps, fmt.Sprintf("Add the %q context to the kubeconfig", cc.Name))
+	} else {
+		steps = append(steps, fmt.Sprintf("Configure kubectl to use the %q context", cc.Name))
+	}
+	return steps, nil
+}
+
+// exists returns whether a file exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestArtifacts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer tests.RemoveTempDir(tempDir)
+	defer viper.Reset()
+
+	const version = "v1.20.0"
+	tarball := download.TarballPath(version, "docker")
+	if err := os.MkdirAll(filepath.Dir(tarball), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(tarball, []byte("preload"), 0644); err != nil {
+		t.Fatalf("write preload: %v", err)
+	}
+	kubelet := localpath.MakeMiniPath("cache", "linux", version, "kubelet")
+	if err := os.MkdirAll(filepath.Dir(kubelet), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(kubelet, []byte("kubelet"), 0755); err != nil {
+		t.Fatalf("write kubelet: %v", err)
+	}
+
+	imgs, err := images.Kubeadm("", version)
+	if err != nil {
+		t.Fatalf("images: %v", err)
+	}
+	imageArtifacts := []string{}
+	for _, img := range imgs {
+		imageArtifacts = append(imageArtifacts, "image "+img)
+	}
+	binaryArtifacts := []string{
+		fmt.Sprintf("binary kubelet %s cached", version),
+		fmt.Sprintf("binary kubeadm %s", version),
+		fmt.Sprintf("binary kubectl %s", version),
+	}
+
+	tests := []struct {
+		description     string
+		driver          string
+		imageRepository string
+		preload         bool
+		cacheImages     bool
+		want            []string
+	}{
+		{
+			description: "preload",
+			driver:      driver.VirtualBox,
+			preload:     true,
+			cacheImages: true,
+			want:        []string{fmt.Sprintf("preload %s cached", download.TarballName(version, "docker"))},
+		},
+		{
+			description: "no preload",
+			driver:      driver.VirtualBox,
+			cacheImages: true,
+			want:        append(append([]string{}, imageArtifacts...), binaryArtifacts...),
+		},
+		{
+			description:     "no preload for an image repository",
+			driver:          driver.VirtualBox,
+			imageRepository: "registry.example.com",
+			preload:         true,
+			want:            binaryArtifacts,
+		},
+		{
+			description: "no image cache",
+			driver:      driver.VirtualBox,
+			want:        binaryArtifacts,
+		},
+		{
+			description: "bare metal",
+			driver:      driver.None,
+			preload:     true,
+			cacheImages: true,
+			want:        []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			viper.Set("preload", test.preload)
+			viper.Set(cacheImages, test.cacheImages)
+			viper.Set(cmdcfg.Bootstrapper, "kubeadm")
+			cc := &config.ClusterConfig{
+				Driver: test.driver,
+				KubernetesConfig: config.KubernetesConfig{
+					KubernetesVersion: version,
+					ContainerRuntime:  "docker",
+					ImageRepository:   test.imageRepository,
+				},
+			}
+			as, err := Artifacts(cc)
+			if err != nil {
+				t.Fatalf("Artifacts: %v", err)
+			}
+			got := []string{}
+			for _, a := range as {
+				s := a.Kind + " " + a.Name
+				// the image cache is outside of the temporary home, so whether images are cached depends on the host
+				if a.Cached && a.Kind != "image" {
+					s += " cached"
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Artifacts() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSteps(t *testing.T) {
+	tests := []struct {
+		description string
+		cc          config.ClusterConfig
+		existing    bool
+		addons      []string
+		nodes       int
+		want        []string
+	}{
+		{
+			description: "new VM",
+			cc: config.ClusterConfig{
+				Name:             "minikube",
+				Driver:           driver.VirtualBox,
+				CPUs:             2,
+				Memory:           4000,
+				DiskSize:         20000,
+				KubernetesConfig: config.KubernetesConfig{KubernetesVersion: "v1.20.0", ContainerRuntime: "docker", CNI: "false"},
+			},
+			nodes: 1,
+			want: []string{
+				"Create a virtualbox VM with 2 CPUs, 4000MB of memory and a 20000MB disk",
+				"Prepare Kubernetes v1.20.0 on docker",
+				"Generate certificates and keys",
+				"Boot up the control plane",
+				"Configure RBAC rules",
+				"Verify Kubernetes components",
+				`Configure kubectl to use the "minikube" context`,
+			},
+		},
+		{
+			description: "new container with addons and nodes",
+			cc: config.ClusterConfig{
+				Name:             "p1",
+				Driver:           driver.Docker,
+				CPUs:             4,
+				Memory:           8000,
+				KeepContext:      true,
+				KubernetesConfig: config.KubernetesConfig{KubernetesVersion: "v1.20.0", ContainerRuntime: "containerd", CNI: "bridge"},
+			},
+			addons: []string{"dashboard", "ingress"},
+			nodes:  3,
+			want: []string{
+				"Create a docker container with 4 CPUs and 8000MB of memory",
+				"Prepare Kubernetes v1.20.0 on containerd",
+				"Generate certificates and keys",
+				"Boot up the control plane",
+				"Configure RBAC rules",
+				"Configure bridge CNI (Container Networking Interface)",
+				"Verify Kubernetes components",
+				"Enable addons: dashboard, ingress",
+				"Start 2 more nodes",
+				`Add the "p1" context to the kubeconfig`,
+			},
+		},
+		{
+			description: "new bare metal",
+			cc: config.ClusterConfig{
+				Name:             "minikube",
+				Driver:           driver.None,
+				KubernetesConfig: config.KubernetesConfig{KubernetesVersion: "v1.20.0", ContainerRuntime: "docker", CNI: "false"},
+			},
+			nodes: 1,
+			want: []string{
+				"Configure the local host environment",
+				"Prepare Kubernetes v1.20.0 on docker",
+				"Generate certificates and keys",
+				"Boot up the control plane",
+				"Configure RBAC rules",
+				"Verify Kubernetes components",
+				`Configure kubectl to use the "minikube" context`,
+			},
+		},
+		{
+			description: "existing",
+			cc: config.ClusterConfig{
+				Name:             "minikube",
+				Driver:           driver.VirtualBox,
+				KubernetesConfig: config.KubernetesConfig{KubernetesVersion: "v1.20.0", ContainerRuntime: "docker", CNI: "false"},
+			},
+			existing: true,
+			nodes:    2,
+			want: []string{
+				`Restart the existing virtualbox cluster "minikube"`,
+				"Prepare Kubernetes v1.20.0 on docker",
+				"Reconfigure the control plane",
+				"Verify Kubernetes components",
+				"Start 1 more node",
+				`Configure kubectl to use the "minikube" context`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := Steps(&test.cc, test.existing, test.addons, test.nodes)
+			if err != nil {
+				t.Fatalf("Steps: %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Steps() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -38,6 +38,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/cni"
@@ -56,6 +57,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/out/register"
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/schedule"
 	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/retry"
@@ -66,6 +68,8 @@ const waitTimeout = "wait-timeout"
 var (
 	kicGroup   errgroup.Group
 	cacheGroup errgroup.Group
+	// cacheMu orders the use of the groups above by nodes started in parallel
+	cacheMu sync.Mutex
 )
 
 // Starter is a struct with all the necessary information to start a node
@@ -82,16 +86,36 @@ type Starter struct {
 // Start spins up a guest and starts the Kubernetes node.
 func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	// wait for preloaded tarball to finish downloading before configuring runtimes
+	cacheMu.Lock()
 	waitCacheRequiredImages(&cacheGroup)
-
-	sv, err := util.ParseKubernetesVersion(starter.Node.KubernetesVersion)
+	cacheMu.Unlock()
+
+	// The node records the version it runs, which is older than the cluster version until it has been upgraded.
+	// A worker pinned to its own version is set up with that version instead of the cluster one.
+	k8sVersion := config.NodeKubernetesVersion(*starter.Cfg, *starter.Node)
+	nodeCfg := *starter.Cfg
+	nodeCfg.KubernetesConfig.KubernetesVersion = k8sVersion
+	sv, err := util.ParseKubernetesVersion(k8sVersion)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to parse Kubernetes version")
 	}
 
+	upgrade, err := bsutil.NeedsUpgrade(*starter.Cfg, *starter.Node)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to compare Kubernetes versions")
+	}
+	upgrade = upgrade && starter.PreExists
+
 	// configure the runtime (docker, containerd, crio)
-	cr := configureRuntimes(starter.Runner, *starter.Cfg, sv)
-	showVersionInfo(starter.Node.KubernetesVersion, cr)
+	cr := configureRuntimes(starter.Runner, nodeCfg, sv)
+	showVersionInfo(k8sVersion, cr)
+
+	// Images of an offline bundle can't be pulled, so every node loads them before bootstrapping
+	if len(starter.Cfg.OfflineImages) > 0 {
+		if err := machine.LoadImages(&nodeCfg, starter.Runner, starter.Cfg.OfflineImages, constants.ImageCacheDir); err != nil {
+			return nil, errors.Wrap(err, "loading offline images")
+		}
+	}
 
 	// Add "host.minikube.internal" DNS alias (intentionally non-fatal)
 	hostIP, err := cluster.HostIP(starter.Host, starter.Cfg.Name)
@@ -110,6 +134,24 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 			return nil, errors.Wrap(err, "Failed to setup kubeconfig")
 		}
 
+		if starter.PreExists {
+			if idle, ok := schedule.IdleStopped(starter.Runner); ok {
+				out.Step(style.Tip, "The cluster was last stopped by the idle auto-stop, as its API server was idle for {{.idle}}", out.V{"idle": idle})
+			}
+		}
+
+		// the control plane is upgraded before setupKubeAdm swaps the kubelet for the new version
+		if upgrade {
+			out.Step(style.Restarting, "Upgrading control plane node {{.name}} from Kubernetes {{.old}} to {{.new}} ...", out.V{"name": starter.Node.Name, "old": starter.Node.KubernetesVersion, "new": k8sVersion})
+			ubs, err := cluster.Bootstrapper(starter.MachineAPI, viper.GetString(cmdcfg.Bootstrapper), *starter.Cfg, starter.Runner)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to get bootstrapper")
+			}
+			if err := ubs.UpgradeControlPlane(*starter.Cfg, *starter.Node); err != nil {
+				return nil, errors.Wrap(err, "upgrading control plane")
+			}
+		}
+
 		// setup kubeadm (must come after setupKubeconfig)
 		bs = setupKubeAdm(starter.MachineAPI, *starter.Cfg, *starter.Node, starter.Runner)
 		err = bs.StartCluster(*starter.Cfg)
@@ -123,19 +165,36 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 		if err := kubeconfig.Update(kcs); err != nil {
 			return nil, errors.Wrap(err, "Failed kubeconfig update")
 		}
+
+		if !driver.BareMetal(starter.Cfg.Driver) {
+			if err := schedule.IdleStop(starter.Runner, *starter.Cfg, *starter.Node); err != nil {
+				out.WarningT("Unable to set up the idle auto-stop: {{.error}}", out.V{"error": err})
+			}
+		}
 	} else {
-		bs, err = cluster.Bootstrapper(starter.MachineAPI, viper.GetString(cmdcfg.Bootstrapper), *starter.Cfg, starter.Runner)
+		bs, err = cluster.Bootstrapper(starter.MachineAPI, viper.GetString(cmdcfg.Bootstrapper), nodeCfg, starter.Runner)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to get bootstrapper")
 		}
 
-		if err = bs.SetupCerts(starter.Cfg.KubernetesConfig, *starter.Node); err != nil {
+		if err = bs.SetupCerts(nodeCfg.KubernetesConfig, *starter.Node); err != nil {
 			return nil, errors.Wrap(err, "setting up certs")
 		}
 
-		if err := bs.UpdateNode(*starter.Cfg, *starter.Node, cr); err != nil {
+		if upgrade {
+			if err := upgradeWorker(starter, bs, cr); err != nil {
+				return nil, errors.Wrap(err, "upgrading node")
+			}
+		} else if err := bs.UpdateNode(nodeCfg, *starter.Node, cr); err != nil {
 			return nil, errors.Wrap(err, "update node")
 		}
+
+		// The other nodes follow the primary control plane, which is started first
+		if !driver.BareMetal(starter.Cfg.Driver) {
+			if err := schedule.IdleStop(starter.Runner, *starter.Cfg, *starter.Node); err != nil {
+				out.WarningT("Unable to set up the idle auto-stop: {{.error}}", out.V{"error": err})
+			}
+		}
 	}
 
 	var wg sync.WaitGroup
@@ -169,19 +228,25 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 		if starter.Cfg.Driver == driver.None && len(starter.Cfg.Nodes) == 1 {
 			prepareNone()
 		}
-	} else {
+	} else if !upgrade {
 		// Make sure to use the command runner for the control plane to generate the join token
 		cpBs, cpr, err := cluster.ControlPlaneBootstrapper(starter.MachineAPI, starter.Cfg, viper.GetString(cmdcfg.Bootstrapper))
 		if err != nil {
 			return nil, errors.Wrap(err, "getting control plane bootstrapper")
 		}
 
-		joinCmd, err := cpBs.GenerateToken(*starter.Cfg)
+		joinCmd, err := cpBs.GenerateToken(*starter.Cfg, *starter.Node)
 		if err != nil {
 			return nil, errors.Wrap(err, "generating join token")
 		}
 
-		if err = bs.JoinCluster(*starter.Cfg, *starter.Node, joinCmd); err != nil {
+		if starter.Node.ControlPlane {
+			if err := bootstrapper.CopySharedCerts(cpr, starter.Runner); err != nil {
+				return nil, errors.Wrap(err, "copying shared certs")
+			}
+		}
+
+		if err = bs.JoinCluster(nodeCfg, *starter.Node, joinCmd); err != nil {
 			return nil, errors.Wrap(err, "joining cluster")
 		}
 
@@ -196,15 +261,52 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	}
 
 	klog.Infof("Will wait %s for node up to ", viper.GetDuration(waitTimeout))
-	if err := bs.WaitForNode(*starter.Cfg, *starter.Node, viper.GetDuration(waitTimeout)); err != nil {
+	if err := bs.WaitForNode(nodeCfg, *starter.Node, viper.GetDuration(waitTimeout)); err != nil {
 		return nil, errors.Wrapf(err, "wait %s for node", viper.GetDuration(waitTimeout))
 	}
 
+	if len(starter.Node.Labels) > 0 || len(starter.Node.Taints) > 0 {
+		cpBs, _, err := cluster.ControlPlaneBootstrapper(starter.MachineAPI, starter.Cfg, viper.GetString(cmdcfg.Bootstrapper))
+		if err != nil {
+			return nil, errors.Wrap(err, "getting control plane bootstrapper")
+		}
+		if err := cpBs.LabelAndTaintNode(*starter.Cfg, *starter.Node); err != nil {
+			return nil, errors.Wrap(err, "labeling node")
+		}
+	}
+
 	klog.Infof("waiting for startup goroutines ...")
 	wg.Wait()
 
-	// Write enabled addons to the config before completion
-	return kcs, config.Write(viper.GetString(config.ProfileName), starter.Cfg)
+	// Record the version the node now runs, so that later upgrades know where they start from.
+	// Saving the node also writes enabled addons to the config before completion.
+	starter.Node.KubernetesVersion = k8sVersion
+	return kcs, config.SaveNode(starter.Cfg, starter.Node)
+}
+
+// upgradeWorker upgrades a worker node in place: it is drained, its binaries are swapped, kubeadm upgrades its kubelet configuration and it is uncordoned
+func upgradeWorker(starter Starter, bs bootstrapper.Bootstrapper, cr cruntime.Manager) error {
+	out.Step(style.Restarting, "Upgrading node {{.name}} from Kubernetes {{.old}} to {{.new}} ...", out.V{"name": starter.Node.Name, "old": starter.Node.KubernetesVersion, "new": starter.Cfg.KubernetesConfig.KubernetesVersion})
+
+	// drain and uncordon need the admin credentials of the control plane
+	cpBs, _, err := cluster.ControlPlaneBootstrapper(starter.MachineAPI, starter.Cfg, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		return errors.Wrap(err, "getting control plane bootstrapper")
+	}
+
+	if err := cpBs.DrainNode(*starter.Cfg, *starter.Node); err != nil {
+		klog.Warningf("unable to drain %s, continuing anyway: %v", starter.Node.Name, err)
+	}
+
+	if err := bs.UpdateNode(*starter.Cfg, *starter.Node, cr); err != nil {
+		return errors.Wrap(err, "update node")
+	}
+
+	if err := bs.UpgradeNode(*starter.Cfg, *starter.Node); err != nil {
+		return err
+	}
+
+	return cpBs.UncordonNode(*starter.Cfg, *starter.Node)
 }
 
 // Provision provisions the machine/container for the node
@@ -217,24 +319,65 @@ func Provision(cc *config.ClusterConfig, n *config.Node, apiServer bool, delOnFa
 		out.Step(style.ThumbsUp, "Starting node {{.name}} in cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
 	}
 
+	if err := beginDownloads(cc, n); err != nil {
+		return nil, false, nil, nil, err
+	}
+	return startMachine(cc, n, delOnFail)
+}
+
+// beginDownloads starts the downloads which a node needs and saves the config.
+// Nodes started in parallel take turns, as they share the download groups.
+func beginDownloads(cc *config.ClusterConfig, n *config.Node) error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
 	if driver.IsKIC(cc.Driver) {
 		beginDownloadKicBaseImage(&kicGroup, cc, viper.GetBool("download-only"))
 	}
 
+	k8sVersion := config.NodeKubernetesVersion(*cc, *n)
 	if !driver.BareMetal(cc.Driver) {
-		beginCacheKubernetesImages(&cacheGroup, cc.KubernetesConfig.ImageRepository, n.KubernetesVersion, cc.KubernetesConfig.ContainerRuntime)
+		beginCacheKubernetesImages(&cacheGroup, cc.KubernetesConfig.ImageRepository, k8sVersion, cc.KubernetesConfig.ContainerRuntime)
 	}
 
 	// Abstraction leakage alert: startHost requires the config to be saved, to satistfy pkg/provision/buildroot.
 	// Hence, SaveProfile must be called before startHost, and again afterwards when we know the IP.
 	if err := config.SaveProfile(viper.GetString(config.ProfileName), cc); err != nil {
-		return nil, false, nil, nil, errors.Wrap(err, "Failed to save config")
+		return errors.Wrap(err, "Failed to save config")
 	}
 
-	handleDownloadOnly(&cacheGroup, &kicGroup, n.KubernetesVersion)
+	handleDownloadOnly(&cacheGroup, &kicGroup, k8sVersion)
 	waitDownloadKicBaseImage(&kicGroup)
+	return nil
+}
 
-	return startMachine(cc, n, delOnFail)
+// runtimeProxyEnv returns the proxy environment of the container runtime, which must reach the nodes, services and pods directly
+func runtimeProxyEnv(cc config.ClusterConfig) []string {
+	excluded := []string{}
+	for _, n := range cc.Nodes {
+		if n.IP != "" {
+			excluded = append(excluded, n.IP)
+		}
+	}
+	// minikube networks are /24, and nodes added later get the next addresses
+	if cp, err := config.PrimaryControlPlane(&cc); err == nil {
+		if ip := net.ParseIP(cp.IP).To4(); ip != nil {
+			excluded = append(excluded, (&net.IPNet{IP: ip.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String())
+		}
+	}
+	if config.IsHA(cc) {
+		excluded = append(excluded, cc.KubernetesConfig.APIServerHAVIP)
+	}
+	if cc.KubernetesConfig.ServiceCIDR != "" {
+		excluded = append(excluded, cc.KubernetesConfig.ServiceCIDR)
+	}
+	cnm, err := cni.New(cc)
+	if err != nil {
+		klog.Warningf("unable to get the pod CIDR: %v", err)
+	} else if cidr := cnm.CIDR(); cidr != "" {
+		excluded = append(excluded, cidr)
+	}
+	return proxy.RuntimeEnv(cc.DockerEnv, excluded...)
 }
 
 // ConfigureRuntimes does what needs to happen to get a runtime going.
@@ -244,7 +387,15 @@ func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, k
 		Runner:            runner,
 		ImageRepository:   cc.KubernetesConfig.ImageRepository,
 		KubernetesVersion: kv,
+		RegistryMirror:    cc.RegistryMirror,
+		InsecureRegistry:  cc.InsecureRegistry,
+		Env:               runtimeProxyEnv(cc),
 	}
+	auths, err := config.LoadRegistryAuths(cc.Name)
+	if err != nil {
+		klog.Warningf("unable to load registry credentials: %v", err)
+	}
+	co.RegistryAuth = auths
 	cr, err := cruntime.New(co)
 	if err != nil {
 		exit.Error(reason.InternalRuntime, "Failed runtime", err)
@@ -322,15 +473,40 @@ func setupKubeconfig(h *host.Host, cc *config.ClusterConfig, n *config.Node, clu
 		ClusterServerAddress: addr,
 		ClientCertificate:    localpath.ClientCert(cc.Name),
 		ClientKey:            localpath.ClientKey(cc.Name),
-		CertificateAuthority: localpath.CACert(),
+		CertificateAuthority: bootstrapper.ClusterCACert(cc.KubernetesConfig),
 		KeepContext:          cc.KeepContext,
 		EmbedCerts:           cc.EmbedCerts,
 	}
+	if cc.ExecCredential {
+		kcs.ExecCredential, err = kubeconfig.ExecCredentialPlugin(cc.Name, "")
+		if err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "Failed to set up the credential plugin", err)
+		}
+	}
 
-	kcs.SetPath(kubeconfig.PathFromEnv())
+	kcs.SetPath(kubeconfigPath(cc))
 	return kcs
 }
 
+// kubeconfigPath returns the kubeconfig file to write the context of a cluster to, and moves the context out of the other file
+func kubeconfigPath(cc *config.ClusterConfig) string {
+	own := kubeconfig.ProfilePath(cc.Name)
+	if !cc.KubeconfigPerProfile {
+		if err := os.Remove(own); err == nil {
+			klog.Infof("removed %s: the context of %s moves to %s", own, cc.Name, kubeconfig.PathFromEnv())
+		}
+		return kubeconfig.PathFromEnv()
+	}
+
+	if _, _, err := kubeconfig.Endpoint(cc.Name, kubeconfig.PathFromEnv()); err == nil {
+		klog.Infof("moving the context of %s from %s to %s", cc.Name, kubeconfig.PathFromEnv(), own)
+		if err := kubeconfig.DeleteContext(cc.Name, kubeconfig.PathFromEnv()); err != nil {
+			klog.Warningf("unable to delete the context of %s from %s: %v", cc.Name, kubeconfig.PathFromEnv(), err)
+		}
+	}
+	return own
+}
+
 func apiServerURL(h host.Host, cc config.ClusterConfig, n config.Node) (string, error) {
 	hostname, _, port, err := driver.ControlPlaneEndpoint(&cc, &n, h.DriverName)
 	if err != nil {
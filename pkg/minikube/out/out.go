@@ -26,6 +26,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -62,6 +63,8 @@ var (
 	JSON = false
 	// spin is spinner showed at starting minikube
 	spin = spinner.New(spinner.CharSets[style.SpinnerCharacter], 100*time.Millisecond)
+	// outMu keeps lines whole, and the spinner consistent, when nodes are started in parallel
+	outMu sync.Mutex
 )
 
 // MaxLogEntries controls the number of log entries to show for each source
@@ -117,6 +120,8 @@ func String(format string, a ...interface{}) {
 		return
 	}
 	klog.Infof(format, a...)
+	outMu.Lock()
+	defer outMu.Unlock()
 	// if spin is active from a previous step, it will stop spinner displaying
 	if spin.Active() {
 		spin.Stop()
@@ -138,6 +143,8 @@ func spinnerString(format string, a ...interface{}) {
 	}
 
 	klog.Infof(format, a...)
+	outMu.Lock()
+	defer outMu.Unlock()
 	// if spin is active from a previous step, it will stop spinner displaying
 	if spin.Active() {
 		spin.Stop()
@@ -21,6 +21,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	guuid "github.com/google/uuid"
@@ -39,6 +40,9 @@ var (
 	GetUUID = randomID
 
 	eventFile *os.File
+
+	// writeMu keeps events whole when they are emitted concurrently
+	writeMu sync.Mutex
 )
 
 // SetOutputFile sets the writer to emit all events to
@@ -85,6 +89,8 @@ func printAsCloudEvent(log Log, data map[string]string) {
 		klog.Errorf("error marshalling event: %v", err)
 		return
 	}
+	writeMu.Lock()
+	defer writeMu.Unlock()
 	fmt.Fprintln(outputFile, string(bs))
 }
 
@@ -97,7 +103,9 @@ func printAndRecordCloudEvent(log Log, data map[string]string) {
 		klog.Errorf("error marshalling event: %v", err)
 		return
 	}
+	writeMu.Lock()
 	fmt.Fprintln(outputFile, string(bs))
+	writeMu.Unlock()
 
 	if eventFile != nil {
 		storeEvent(bs)
@@ -105,6 +113,8 @@ func printAndRecordCloudEvent(log Log, data map[string]string) {
 }
 
 func storeEvent(bs []byte) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
 	fmt.Fprintln(eventFile, string(bs))
 	if err := eventFile.Sync(); err != nil {
 		klog.Warningf("even file flush failed: %v", err)
@@ -28,6 +28,19 @@ func RecordStep(message string) {
 	recordCloudEvent(s, s.data)
 }
 
+// PrintNodeStep prints a NodeStep type in JSON format
+func PrintNodeStep(node string, step RegStep, message string) {
+	s := NewNodeStep(node, step, message)
+	printAndRecordCloudEvent(s, s.data)
+}
+
+// PrintNodeError prints an Error type for a node in JSON format
+func PrintNodeError(node string, err string) {
+	e := NewError(err)
+	e.data["node"] = node
+	printAndRecordCloudEvent(e, e.data)
+}
+
 // PrintInfo prints an Info type in JSON format
 func PrintInfo(message string) {
 	s := NewInfo(message)
@@ -124,3 +124,43 @@ func TestWarning(t *testing.T) {
 		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
 	}
 }
+
+func TestPrintNodeStep(t *testing.T) {
+	expected := `{"data":{"message":"Joining node m02","name":"Preparing Kubernetes","node":"m02"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.node.step"}`
+	expected += "\n"
+
+	buf := bytes.NewBuffer([]byte{})
+	SetOutputFile(buf)
+	defer func() { SetOutputFile(os.Stdout) }()
+
+	GetUUID = func() string {
+		return "random-id"
+	}
+
+	PrintNodeStep("m02", PreparingKubernetes, "Joining node m02")
+	actual := buf.String()
+
+	if actual != expected {
+		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
+	}
+}
+
+func TestPrintNodeError(t *testing.T) {
+	expected := `{"data":{"message":"error","node":"m03"},"datacontenttype":"application/json","id":"random-id","source":"https://minikube.sigs.k8s.io/","specversion":"1.0","type":"io.k8s.sigs.minikube.error"}`
+	expected += "\n"
+
+	buf := bytes.NewBuffer([]byte{})
+	SetOutputFile(buf)
+	defer func() { SetOutputFile(os.Stdout) }()
+
+	GetUUID = func() string {
+		return "random-id"
+	}
+
+	PrintNodeError("m03", "error")
+	actual := buf.String()
+
+	if actual != expected {
+		t.Fatalf("expected didn't match actual:\nExpected:\n%v\n\nActual:\n%v", expected, actual)
+	}
+}
@@ -43,7 +43,26 @@ func NewStep(message string) *Step {
 		"totalsteps":  Reg.totalSteps(),
 		"currentstep": Reg.currentStep(),
 		"message":     strings.TrimSpace(message),
-		"name":        string(Reg.current),
+		"name":        Reg.currentName(),
+	}}
+}
+
+// NodeStep reports the progress of one node, as nodes may be started in parallel
+type NodeStep struct {
+	data map[string]string
+}
+
+// Type returns the cloud events compatible type of this struct
+func (s *NodeStep) Type() string {
+	return "io.k8s.sigs.minikube.node.step"
+}
+
+// NewNodeStep returns a new node step type
+func NewNodeStep(node string, step RegStep, message string) *NodeStep {
+	return &NodeStep{data: map[string]string{
+		"node":    node,
+		"name":    string(step),
+		"message": strings.TrimSpace(message),
 	}}
 }
 
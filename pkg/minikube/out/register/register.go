@@ -19,6 +19,7 @@ package register
 
 import (
 	"fmt"
+	"sync"
 
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/trace"
@@ -61,6 +62,8 @@ type Register struct {
 	steps   map[RegStep][]RegStep
 	first   RegStep
 	current RegStep
+	// mu guards first and current, as nodes may be started in parallel
+	mu sync.Mutex
 }
 
 // Reg keeps track of all possible steps and the current step we are on
@@ -101,11 +104,16 @@ func init() {
 
 // totalSteps returns the total number of steps in the register
 func (r *Register) totalSteps() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return fmt.Sprintf("%d", len(r.steps[r.first])-1)
 }
 
 // currentStep returns the current step we are on
 func (r *Register) currentStep() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.first == RegStep("") {
 		return ""
 	}
@@ -126,9 +134,19 @@ func (r *Register) currentStep() string {
 	return ""
 }
 
+// currentName returns the name of the current step
+func (r *Register) currentName() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return string(r.current)
+}
+
 // SetStep sets the current step
 func (r *Register) SetStep(s RegStep) {
 	defer trace.StartSpan(string(s))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.first == RegStep("") {
 		_, ok := r.steps[s]
 		if ok {
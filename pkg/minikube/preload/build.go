@@ -0,0 +1,261 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preload builds preloaded images tarballs
+package preload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	// DefaultContainerName is the name of the container the tarball is built in
+	DefaultContainerName = "minikube-preload-build"
+	// dockerStorageDriver is the storage driver docker and containerd tarballs are built for
+	dockerStorageDriver = "overlay2"
+	// podmanStorageDriver is the storage driver cri-o tarballs are built for
+	podmanStorageDriver = "overlay"
+	// guestTarball is where the tarball is built within the container
+	guestTarball = "/preloaded-images.tar.lz4"
+)
+
+// Config describes a preloaded images tarball to build
+type Config struct {
+	KubernetesVersion string
+	ContainerRuntime  string
+	ExtraImages       []string // images to preload in addition to the Kubernetes ones
+	ContainerName     string   // defaults to DefaultContainerName
+	BaseImage         string   // defaults to kic.BaseImage
+}
+
+// normalizeRuntime returns the name preload tarballs use for a container runtime
+func normalizeRuntime(name string) string {
+	if name == "crio" {
+		return "cri-o"
+	}
+	return name
+}
+
+// Images returns the images the tarball holds
+func Images(cfg Config) ([]string, error) {
+	imgs, err := images.Kubeadm("", cfg.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "kubeadm images")
+	}
+	if normalizeRuntime(cfg.ContainerRuntime) != "docker" { // kic overlay image is only needed by containerd and cri-o https://github.com/kubernetes/minikube/issues/7428
+		imgs = append(imgs, images.KindNet(""))
+	}
+
+	seen := map[string]bool{}
+	for _, img := range imgs {
+		seen[img] = true
+	}
+	for _, img := range cfg.ExtraImages {
+		if !seen[img] {
+			seen[img] = true
+			imgs = append(imgs, img)
+		}
+	}
+	return imgs, nil
+}
+
+// Build builds the tarball in a temporary kic container, and writes it to dst on the host
+func Build(cfg Config, dst string) error {
+	name := cfg.ContainerName
+	if name == "" {
+		name = DefaultContainerName
+	}
+	baseImage := cfg.BaseImage
+	if baseImage == "" {
+		baseImage = kic.BaseImage
+	}
+	runtime := normalizeRuntime(cfg.ContainerRuntime)
+	imgs, err := Images(cfg)
+	if err != nil {
+		return err
+	}
+	sv, err := util.ParseKubernetesVersion(cfg.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse Kubernetes version")
+	}
+
+	driver := kic.NewDriver(kic.Config{
+		ClusterName:       name,
+		KubernetesVersion: cfg.KubernetesVersion,
+		ContainerRuntime:  runtime,
+		OCIBinary:         oci.Docker,
+		MachineName:       name,
+		ImageDigest:       baseImage,
+		StorePath:         localpath.MiniPath(),
+		CPU:               2,
+		Memory:            4000,
+		APIServerPort:     8080,
+	})
+
+	baseDir := filepath.Dir(driver.GetSSHKeyPath())
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	defer func() {
+		if err := driver.Remove(); err != nil {
+			klog.Warningf("failed to remove %s: %v", name, err)
+		}
+		os.RemoveAll(baseDir)
+	}()
+	if err := driver.Create(); err != nil {
+		return errors.Wrap(err, "creating kic driver")
+	}
+
+	runner := command.NewKICRunner(name, driver.OCIBinary)
+	if err := verifyStorage(runner, runtime); err != nil {
+		return errors.Wrap(err, "verifying storage")
+	}
+
+	co := cruntime.Config{
+		Type:              runtime,
+		Runner:            runner,
+		ImageRepository:   "",
+		KubernetesVersion: sv, //  this is just to satisfy cruntime and shouldnt matter what version.
+	}
+	cr, err := cruntime.New(co)
+	if err != nil {
+		return errors.Wrap(err, "failed create new runtime")
+	}
+	if err := cr.Enable(true, false); err != nil {
+		return errors.Wrap(err, "enable container runtime")
+	}
+
+	for _, img := range imgs {
+		img := img
+		pull := func() error {
+			return cr.PullImage(img)
+		}
+		// retry up to 5 times if network is bad
+		if err = retry.Expo(pull, time.Microsecond, time.Minute, 5); err != nil {
+			return errors.Wrapf(err, "pull image %s", img)
+		}
+	}
+
+	// Transfer in k8s binaries
+	kcfg := config.KubernetesConfig{
+		KubernetesVersion: cfg.KubernetesVersion,
+	}
+	if err := bsutil.TransferBinaries(kcfg, runner, sysinit.New(runner)); err != nil {
+		return errors.Wrap(err, "transferring k8s binaries")
+	}
+
+	if err := createImageTarball(runner, runtime); err != nil {
+		return errors.Wrap(err, "create tarball")
+	}
+	return copyTarballToHost(name, dst)
+}
+
+func verifyStorage(runner command.Runner, containerRuntime string) error {
+	if containerRuntime == "docker" || containerRuntime == "containerd" {
+		if err := verifyDockerStorage(runner); err != nil {
+			return errors.Wrap(err, "Docker storage type is incompatible")
+		}
+	}
+	if containerRuntime == "cri-o" {
+		if err := verifyPodmanStorage(runner); err != nil {
+			return errors.Wrap(err, "Podman storage type is incompatible")
+		}
+	}
+	return nil
+}
+
+func verifyDockerStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("docker", "info", "-f", "{{.Info.Driver}}"))
+	if err != nil {
+		return err
+	}
+	driver := strings.Trim(rr.Stdout.String(), " \n")
+	if driver != dockerStorageDriver {
+		return fmt.Errorf("docker storage driver %s does not match requested %s", driver, dockerStorageDriver)
+	}
+	return nil
+}
+
+func verifyPodmanStorage(runner command.Runner) error {
+	rr, err := runner.RunCmd(exec.Command("sudo", "podman", "info", "-f", "json"))
+	if err != nil {
+		return err
+	}
+	var info map[string]map[string]interface{}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &info); err != nil {
+		return err
+	}
+	driver := info["store"]["graphDriverName"]
+	if driver != podmanStorageDriver {
+		return fmt.Errorf("podman storage driver %s does not match requested %s", driver, podmanStorageDriver)
+	}
+	return nil
+}
+
+// tarballDirs returns the directories under /var a tarball holds for a container runtime
+func tarballDirs(containerRuntime string) []string {
+	dirs := []string{
+		"./lib/minikube/binaries",
+	}
+
+	switch containerRuntime {
+	case "docker":
+		dirs = append(dirs, fmt.Sprintf("./lib/docker/%s", dockerStorageDriver), "./lib/docker/image")
+	case "containerd":
+		dirs = append(dirs, "./lib/containerd")
+	case "cri-o":
+		dirs = append(dirs, "./lib/containers")
+	}
+	return dirs
+}
+
+func createImageTarball(runner command.Runner, containerRuntime string) error {
+	args := []string{"tar", "-I", "lz4", "-C", "/var", "-cf", guestTarball}
+	args = append(args, tarballDirs(containerRuntime)...)
+	if _, err := runner.RunCmd(exec.Command("sudo", args...)); err != nil {
+		return errors.Wrap(err, "tarball cmd")
+	}
+	return nil
+}
+
+func copyTarballToHost(name, dst string) error {
+	cmd := exec.Command(oci.Docker, "cp", fmt.Sprintf("%s:%s", name, guestTarball), dst)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "cp cmd: %s: %s", cmd.Args, out)
+	}
+	return nil
+}
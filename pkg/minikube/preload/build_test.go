@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preload
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+)
+
+func TestImages(t *testing.T) {
+	kubeadm, err := images.Kubeadm("", "v1.20.2")
+	if err != nil {
+		t.Fatalf("kubeadm images: %v", err)
+	}
+	extra := "registry.example.com/platform/base:1.0"
+
+	tests := []struct {
+		runtime string
+		extra   []string
+		want    []string
+	}{
+		{"docker", nil, kubeadm},
+		{"docker", []string{extra, kubeadm[0], extra}, append(append([]string{}, kubeadm...), extra)},
+		{"crio", []string{extra}, append(append([]string{}, kubeadm...), images.KindNet(""), extra)},
+		{"containerd", []string{images.KindNet("")}, append(append([]string{}, kubeadm...), images.KindNet(""))},
+	}
+	for _, tc := range tests {
+		got, err := Images(Config{KubernetesVersion: "v1.20.2", ContainerRuntime: tc.runtime, ExtraImages: tc.extra})
+		if err != nil {
+			t.Fatalf("Images: %v", err)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("Images(%s, %v) mismatch (-want +got):\n%s", tc.runtime, tc.extra, diff)
+		}
+	}
+}
+
+func TestTarballDirs(t *testing.T) {
+	tests := map[string][]string{
+		"docker":     {"./lib/minikube/binaries", "./lib/docker/overlay2", "./lib/docker/image"},
+		"containerd": {"./lib/minikube/binaries", "./lib/containerd"},
+		"cri-o":      {"./lib/minikube/binaries", "./lib/containers"},
+	}
+	for runtime, want := range tests {
+		if diff := cmp.Diff(want, tarballDirs(runtime)); diff != "" {
+			t.Errorf("tarballDirs(%s) mismatch (-want +got):\n%s", runtime, diff)
+		}
+	}
+}
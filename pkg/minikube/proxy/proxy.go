@@ -153,6 +153,77 @@ func UpdateTransport(cfg *rest.Config) *rest.Config {
 	return cfg
 }
 
+// IsLocalProxy returns whether the proxy url points to the host itself, which the nodes can't reach
+func IsLocalProxy(v string) (bool, error) {
+	normalizedURL := v
+	if !strings.Contains(v, "://") {
+		normalizedURL = "http://" + v // by default, assumes the url is HTTP scheme
+	}
+	u, err := url.Parse(normalizedURL)
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(u.Host, "localhost") || strings.HasPrefix(u.Host, "127.0"), nil
+}
+
+// GetEnv returns the value of a proxy environment variable, which may be set in lower case
+func GetEnv(k string) string {
+	if v := os.Getenv(k); v != "" {
+		return v
+	}
+	return os.Getenv(strings.ToLower(k))
+}
+
+// RuntimeEnv returns the proxy environment of the container runtime services, taken from the docker environment of the cluster,
+// whose NO_PROXY also excludes the given cluster addresses. It is empty when the cluster doesn't use a proxy.
+func RuntimeEnv(dockerEnv []string, excluded ...string) []string {
+	vars := map[string]string{}
+	for _, e := range dockerEnv {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			continue
+		}
+		// upper case variables take precedence, as in SetDockerEnv
+		k := strings.ToUpper(kv[0])
+		if _, ok := vars[k]; !ok || kv[0] == k {
+			vars[k] = kv[1]
+		}
+	}
+
+	env := []string{}
+	for _, k := range []string{"HTTP_PROXY", "HTTPS_PROXY"} {
+		v := vars[k]
+		if v == "" {
+			continue
+		}
+		local, err := IsLocalProxy(v)
+		if err != nil {
+			out.WarningT("Error parsing {{.name}}={{.value}}, {{.err}}", out.V{"name": k, "value": v, "err": err})
+			continue
+		}
+		if local {
+			out.WarningT("Local proxy ignored: not passing {{.name}}={{.value}} to the container runtime.", out.V{"name": k, "value": v})
+			continue
+		}
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	if len(env) == 0 {
+		return nil
+	}
+
+	noProxy := []string{}
+	seen := map[string]bool{}
+	for _, e := range append(append(strings.Split(vars["NO_PROXY"], ","), "localhost", "127.0.0.1"), excluded...) {
+		e = strings.TrimSpace(e)
+		if e == "" || seen[e] {
+			continue
+		}
+		seen[e] = true
+		noProxy = append(noProxy, e)
+	}
+	return append(env, fmt.Sprintf("NO_PROXY=%s", strings.Join(noProxy, ",")))
+}
+
 // SetDockerEnv sets the proxy environment variables in the docker environment.
 func SetDockerEnv() []string {
 	for _, k := range EnvVars {
@@ -161,21 +232,13 @@ func SetDockerEnv() []string {
 			// TODO (@medyagh): if user has both http_proxy & HTTPS_PROXY set merge them.
 			k = strings.ToUpper(k)
 			if k == "HTTP_PROXY" || k == "HTTPS_PROXY" {
-				isLocalProxy := func(url string) bool {
-					return strings.HasPrefix(url, "localhost") || strings.HasPrefix(url, "127.0")
-				}
-
-				normalizedURL := v
-				if !strings.Contains(v, "://") {
-					normalizedURL = "http://" + v // by default, assumes the url is HTTP scheme
-				}
-				u, err := url.Parse(normalizedURL)
+				local, err := IsLocalProxy(v)
 				if err != nil {
 					out.WarningT("Error parsing {{.name}}={{.value}}, {{.err}}", out.V{"name": k, "value": v, "err": err})
 					continue
 				}
 
-				if isLocalProxy(u.Host) {
+				if local {
 					out.WarningT("Local proxy ignored: not passing {{.name}}={{.value}} to docker env.", out.V{"name": k, "value": v})
 					continue
 				}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"k8s.io/client-go/rest"
 )
 
@@ -258,3 +259,32 @@ func TestUpdateTransport(t *testing.T) {
 		}
 	})
 }
+
+func TestRuntimeEnv(t *testing.T) {
+	var testCases = []struct {
+		description string
+		dockerEnv   []string
+		excluded    []string
+		want        []string
+	}{
+		{"no proxy", nil, []string{"192.168.49.2"}, nil},
+		{"local proxy", []string{"HTTP_PROXY=127.0.0.1:3128"}, nil, nil},
+		{"other variables", []string{"FOO=bar", "HTTP_PROXY="}, nil, nil},
+		{"proxy", []string{"HTTP_PROXY=http://proxy:3128", "https_proxy=http://proxy:3129", "NO_PROXY=example.com,192.168.49.2"},
+			[]string{"192.168.49.2", "192.168.49.0/24", "10.96.0.0/12", "10.244.0.0/16"},
+			[]string{"HTTP_PROXY=http://proxy:3128", "HTTPS_PROXY=http://proxy:3129", "NO_PROXY=example.com,192.168.49.2,localhost,127.0.0.1,192.168.49.0/24,10.96.0.0/12,10.244.0.0/16"}},
+		{"upper case first", []string{"http_proxy=http://lower:3128", "HTTP_PROXY=http://upper:3128"}, nil,
+			[]string{"HTTP_PROXY=http://upper:3128", "NO_PROXY=localhost,127.0.0.1"}},
+	}
+	// the proxy of the shell running minikube is not used
+	defer os.Setenv("HTTPS_PROXY", os.Getenv("HTTPS_PROXY"))
+	os.Setenv("HTTPS_PROXY", "http://shell:3128")
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			got := RuntimeEnv(tc.dockerEnv, tc.excluded...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("RuntimeEnv(%v, %v) diff (-want +got):\n%s", tc.dockerEnv, tc.excluded, diff)
+			}
+		})
+	}
+}
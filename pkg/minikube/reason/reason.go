@@ -201,6 +201,7 @@ var (
 		Issues:   []int{9165},
 	}
 
+	HostBundle              = Kind{ID: "HOST_BUNDLE", ExitCode: ExHostError}
 	HostCurrentUser         = Kind{ID: "HOST_CURRENT_USER", ExitCode: ExHostConfig}
 	HostDelCache            = Kind{ID: "HOST_DEL_CACHE", ExitCode: ExHostError}
 	HostKillMountProc       = Kind{ID: "HOST_KILL_MOUNT_PROC", ExitCode: ExHostError}
@@ -238,6 +239,12 @@ var (
 	GuestCert             = Kind{ID: "GUEST_CERT", ExitCode: ExGuestError}
 	GuestCpConfig         = Kind{ID: "GUEST_CP_CONFIG", ExitCode: ExGuestConfig}
 	GuestDeletion         = Kind{ID: "GUEST_DELETION", ExitCode: ExGuestError}
+	GuestImageBuild       = Kind{ID: "GUEST_IMAGE_BUILD", ExitCode: ExGuestError}
+	GuestImageList        = Kind{ID: "GUEST_IMAGE_LIST", ExitCode: ExGuestError}
+	GuestImageLoad        = Kind{ID: "GUEST_IMAGE_LOAD", ExitCode: ExGuestError}
+	GuestImagePull        = Kind{ID: "GUEST_IMAGE_PULL", ExitCode: ExGuestError}
+	GuestImageRemove      = Kind{ID: "GUEST_IMAGE_REMOVE", ExitCode: ExGuestError}
+	GuestImageSave        = Kind{ID: "GUEST_IMAGE_SAVE", ExitCode: ExGuestError}
 	GuestLoadHost         = Kind{ID: "GUEST_LOAD_HOST", ExitCode: ExGuestError}
 	GuestMount            = Kind{ID: "GUEST_MOUNT", ExitCode: ExGuestError}
 	GuestMountConflict    = Kind{ID: "GUEST_MOUNT_CONFLICT", ExitCode: ExGuestConflict}
@@ -247,8 +254,14 @@ var (
 	GuestNodeRetrieve     = Kind{ID: "GUEST_NODE_RETRIEVE", ExitCode: ExGuestNotFound}
 	GuestNodeStart        = Kind{ID: "GUEST_NODE_START", ExitCode: ExGuestError}
 	GuestPause            = Kind{ID: "GUEST_PAUSE", ExitCode: ExGuestError}
+	GuestPreloadBuild     = Kind{ID: "GUEST_PRELOAD_BUILD", ExitCode: ExGuestError}
 	GuestProfileDeletion  = Kind{ID: "GUEST_PROFILE_DELETION", ExitCode: ExGuestError}
 	GuestProvision        = Kind{ID: "GUEST_PROVISION", ExitCode: ExGuestError}
+	GuestRegistryAuth     = Kind{ID: "GUEST_REGISTRY_AUTH", ExitCode: ExGuestError}
+	GuestSnapshotDelete   = Kind{ID: "GUEST_SNAPSHOT_DELETE", ExitCode: ExGuestError}
+	GuestSnapshotList     = Kind{ID: "GUEST_SNAPSHOT_LIST", ExitCode: ExGuestError}
+	GuestSnapshotRestore  = Kind{ID: "GUEST_SNAPSHOT_RESTORE", ExitCode: ExGuestError}
+	GuestSnapshotSave     = Kind{ID: "GUEST_SNAPSHOT_SAVE", ExitCode: ExGuestError}
 	GuestStart            = Kind{ID: "GUEST_START", ExitCode: ExGuestError}
 	GuestStatus           = Kind{ID: "GUEST_STATUS", ExitCode: ExGuestError}
 	GuestStopTimeout      = Kind{ID: "GUEST_STOP_TIMEOUT", ExitCode: ExGuestTimeout}
@@ -260,8 +273,14 @@ var (
 	IfMountIP   = Kind{ID: "IF_MOUNT_IP", ExitCode: ExLocalNetworkError}
 	IfMountPort = Kind{ID: "IF_MOUNT_PORT", ExitCode: ExLocalNetworkError}
 	IfSSHClient = Kind{ID: "IF_SSH_CLIENT", ExitCode: ExLocalNetworkError}
+	IfPortInUse = Kind{
+		ID:       "IF_PORT_IN_USE",
+		ExitCode: ExLocalNetworkConflict,
+		Advice:   "Stop the process listening on port {{.port}}, or use a driver other than none",
+	}
 
 	InetCacheBinaries      = Kind{ID: "INET_CACHE_BINARIES", ExitCode: ExInternetError}
+	InetCacheBundle        = Kind{ID: "INET_CACHE_BUNDLE", ExitCode: ExInternetError}
 	InetCacheKubectl       = Kind{ID: "INET_CACHE_KUBECTL", ExitCode: ExInternetError}
 	InetCacheTar           = Kind{ID: "INET_CACHE_TAR", ExitCode: ExInternetError}
 	InetGetVersions        = Kind{ID: "INET_GET_VERSIONS", ExitCode: ExInternetError}
@@ -269,6 +288,30 @@ var (
 	InetReposUnavailable   = Kind{ID: "INET_REPOS_UNAVAILABLE", ExitCode: ExInternetError}
 	InetVersionUnavailable = Kind{ID: "INET_VERSION_UNAVAILABLE", ExitCode: ExInternetUnavailable}
 	InetVersionEmpty       = Kind{ID: "INET_VERSION_EMPTY", ExitCode: ExInternetConfig}
+	InetDNS                = Kind{
+		ID:       "INET_DNS",
+		ExitCode: ExInternetUnavailable,
+		Advice:   "Check the DNS servers of your host. If you are behind a firewall, you may need to configure minikube to use a proxy.",
+		URL:      proxyDoc,
+	}
+	InetUnreachable = Kind{
+		ID:       "INET_UNREACHABLE",
+		ExitCode: ExInternetUnavailable,
+		Advice:   "A firewall is likely blocking minikube from reaching {{.host}}. You may need to configure a proxy, or use --image-repository or --artifact-mirror.",
+		URL:      proxyDoc,
+	}
+	InetLocalProxy = Kind{
+		ID:       "INET_LOCAL_PROXY",
+		ExitCode: ExInternetConfig,
+		Advice:   "The cluster can't reach a proxy listening on localhost. Point {{.name}} at an address of the host the cluster can reach.",
+		URL:      proxyDoc,
+	}
+	InetNoProxy = Kind{
+		ID:       "INET_NO_PROXY",
+		ExitCode: ExInternetConfig,
+		Advice:   "Add the minikube IP to NO_PROXY, for example: export NO_PROXY=$NO_PROXY,{{.ip}}",
+		URL:      proxyDoc,
+	}
 
 	RuntimeEnable  = Kind{ID: "RUNTIME_ENABLE", ExitCode: ExRuntimeError}
 	RuntimeCache   = Kind{ID: "RUNTIME_CACHE", ExitCode: ExRuntimeError}
@@ -292,6 +335,8 @@ var (
 
 	KubernetesInstallFailed = Kind{ID: "K8S_INSTALL_FAILED", ExitCode: ExControlPlaneError}
 	KubernetesTooOld        = Kind{ID: "K8S_OLD_UNSUPPORTED", ExitCode: ExControlPlaneUnsupported}
+	KubernetesUpgradeSkew   = Kind{ID: "K8S_UPGRADE_SKEW", ExitCode: ExControlPlaneUnsupported, Advice: "Upgrade one minor version at a time by running minikube start with --kubernetes-version set to each intermediate version"}
+	KubernetesNodeSkew      = Kind{ID: "K8S_NODE_SKEW", ExitCode: ExControlPlaneUnsupported, Advice: "A node may run a Kubernetes version at most two minor versions older than the cluster, and never a newer one"}
 	KubernetesDowngrade     = Kind{
 		ID:       "K8S_DOWNGRADE_UNSUPPORTED",
 		ExitCode: ExControlPlaneUnsupported,
@@ -72,11 +72,11 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		StorePath:         localpath.MiniPath(),
 		ImageDigest:       cc.KicBaseImage,
 		Mounts:            mounts,
-		CPU:               cc.CPUs,
-		Memory:            cc.Memory,
+		CPU:               config.NodeCPUs(cc, n),
+		Memory:            config.NodeMemory(cc, n),
 		OCIBinary:         oci.Docker,
 		APIServerPort:     cc.Nodes[0].Port,
-		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		KubernetesVersion: config.NodeKubernetesVersion(cc, n),
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
 		ExtraArgs:         extraArgs,
 		Network:           cc.Network,
@@ -77,12 +77,12 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 			StorePath:   localpath.MiniPath(),
 			SSHUser:     "docker",
 		},
-		Memory:         cc.Memory,
-		CPU:            cc.CPUs,
+		Memory:         config.NodeMemory(cc, n),
+		CPU:            config.NodeCPUs(cc, n),
 		Network:        cc.KVMNetwork,
 		PrivateNetwork: "minikube-net",
 		Boot2DockerURL: download.LocalISOResource(cc.MinikubeISO),
-		DiskSize:       cc.DiskSize,
+		DiskSize:       config.NodeDiskSize(cc, n),
 		DiskPath:       filepath.Join(localpath.MiniPath(), "machines", name, fmt.Sprintf("%s.rawdisk", name)),
 		ISO:            filepath.Join(localpath.MiniPath(), "machines", name, "boot2docker.iso"),
 		GPU:            cc.KVMGPU,
@@ -81,11 +81,11 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		StorePath:         localpath.MiniPath(),
 		ImageDigest:       strings.Split(cc.KicBaseImage, "@")[0], // for podman does not support docker images references with both a tag and digest.
 		Mounts:            mounts,
-		CPU:               cc.CPUs,
-		Memory:            cc.Memory,
+		CPU:               config.NodeCPUs(cc, n),
+		Memory:            config.NodeMemory(cc, n),
 		OCIBinary:         oci.Podman,
 		APIServerPort:     cc.Nodes[0].Port,
-		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		KubernetesVersion: config.NodeKubernetesVersion(cc, n),
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
 		ExtraArgs:         extraArgs,
 	}), nil
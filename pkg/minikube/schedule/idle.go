@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+var (
+	idleStopScriptPath = path.Join(path.Dir(constants.IdleStopEnvFile), constants.IdleStopSystemdService)
+	idleStopUnitPath   = fmt.Sprintf("/etc/systemd/system/%s.service", constants.IdleStopSystemdService)
+	// idleStopMarkerPath is written by the primary control plane when the idle auto-stop stops it, within a directory which persists across restarts
+	idleStopMarkerPath = path.Join(path.Dir(constants.IdleStopEnvFile), "stopped")
+)
+
+// idleStopAnnotation is set on the node of the primary control plane when the idle auto-stop stops it, for the other nodes to follow
+const idleStopAnnotation = "minikube.k8s.io/idle-stop"
+
+// idleStopScript powers the node off once the API server has logged no audit event for $IDLE seconds.
+// The audit policy written by the bootstrapper only logs requests from outside the cluster.
+// Only the primary control plane watches its API server: the other nodes power off once it has annotated its node.
+var idleStopScript = `#!/bin/bash
+
+interval=${INTERVAL:-30}
+
+annotation() {
+	"${KUBECTL}" --kubeconfig="${KUBECONFIG}" get node "${NODE}" -o jsonpath="{.metadata.annotations.minikube\.k8s\.io/idle-stop}" 2>/dev/null
+}
+
+if [[ "${ROLE}" != "primary" ]]; then
+	echo "stopping the node once ${NODE} has been stopped by the idle auto-stop ..."
+	while true; do
+		sleep "${interval}"
+		if [[ -n "$(annotation)" ]]; then
+			echo "${NODE} was stopped by the idle auto-stop, running poweroff..."
+			systemctl poweroff
+			exit 0
+		fi
+	done
+fi
+
+echo "stopping the cluster after ${IDLE} seconds without API server requests ..."
+
+count_events() {
+	cat /var/log/pods/kube-system_kube-apiserver-*/kube-apiserver/*.log 2>/dev/null | grep -c "audit.k8s.io/v1"
+}
+
+last=$(count_events)
+idle=0
+while true; do
+	sleep "${interval}"
+	current=$(count_events)
+	if [[ "${current}" != "${last}" ]]; then
+		last=${current}
+		idle=0
+		continue
+	fi
+	idle=$((idle + interval))
+	if [[ ${idle} -ge ${IDLE} ]]; then
+		echo "no API server requests for ${idle} seconds, running poweroff..."
+		echo "${IDLE}" > "${MARKER}"
+		if "${KUBECTL}" --kubeconfig="${KUBECONFIG}" annotate --overwrite node "${NODE}" "${ANNOTATION}=$(date +%s)"; then
+			# give the other nodes time to notice before the API server goes away
+			sleep $((interval * 2))
+		fi
+		systemctl poweroff
+		exit 0
+	fi
+done
+`
+
+var idleStopUnit = fmt.Sprintf(`[Unit]
+Description=minikube idle stop
+
+[Service]
+Type=simple
+User=root
+ExecStart=%s
+EnvironmentFile=%s
+`, idleStopScriptPath, constants.IdleStopEnvFile)
+
+// IdleStop runs the service which stops the node once the API server has been idle for cc.AutoStopIdle,
+// or stops it if the idle auto-stop is disabled. The service is restarted on every start, so it is not enabled.
+// The primary control plane must be started first, as it clears the request to stop left by the last idle stop.
+func IdleStop(runner command.Runner, cc config.ClusterConfig, n config.Node) error {
+	primary := config.IsPrimaryControlPlane(cc, n)
+	if primary {
+		c := exec.Command("sudo", kapi.KubectlBinaryPath(config.NodeKubernetesVersion(cc, n)), "--kubeconfig="+idleStopKubeconfig(true),
+			"annotate", "node", bsutil.KubeNodeName(cc, n), idleStopAnnotation+"-")
+		if _, err := runner.RunCmd(c); err != nil {
+			klog.Warningf("unable to clear the %s annotation: %v", idleStopAnnotation, err)
+		}
+	}
+
+	sm := sysinit.New(runner)
+	if cc.AutoStopIdle == 0 {
+		if !sm.Active(constants.IdleStopSystemdService) {
+			return nil
+		}
+		klog.Infof("stopping %s, as the idle auto-stop is disabled", constants.IdleStopSystemdService)
+		return sm.Stop(constants.IdleStopSystemdService)
+	}
+	if sm.Name() != "systemd" {
+		return fmt.Errorf("the idle auto-stop requires systemd, but the node runs %s", sm.Name())
+	}
+
+	cp, err := config.PrimaryControlPlane(&cc)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+	env := idleEnvironment(cc.AutoStopIdle, primary, bsutil.KubeNodeName(cc, cp), kapi.KubectlBinaryPath(config.NodeKubernetesVersion(cc, n)))
+
+	if rr, err := runner.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(constants.IdleStopEnvFile))); err != nil {
+		return errors.Wrapf(err, "creating dirs: %v", rr.Output())
+	}
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget([]byte(idleStopScript), idleStopScriptPath, "0755"),
+		assets.NewMemoryAssetTarget([]byte(idleStopUnit), idleStopUnitPath, "0644"),
+		assets.NewMemoryAssetTarget(env, constants.IdleStopEnvFile, "0644"),
+	}
+	for _, f := range files {
+		if err := runner.Copy(f); err != nil {
+			return errors.Wrapf(err, "copying %s", f.GetTargetName())
+		}
+	}
+	return sm.Restart(constants.IdleStopSystemdService)
+}
+
+// IdleStopped returns whether the idle auto-stop stopped the primary control plane the last time it stopped,
+// and the idle time which stopped it. The record of the stop is removed, so this reports a stop once.
+func IdleStopped(runner command.Runner) (time.Duration, bool) {
+	rr, err := runner.RunCmd(exec.Command("sudo", "cat", idleStopMarkerPath))
+	if err != nil {
+		return 0, false
+	}
+	if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-f", idleStopMarkerPath)); err != nil {
+		klog.Warningf("unable to remove %s: %v", idleStopMarkerPath, err)
+	}
+	return parseIdleMarker(rr.Stdout.Bytes())
+}
+
+// idleStoppedContainer is IdleStopped for the stopped container of a node, which is left untouched
+func idleStoppedContainer(ociBin string, machineName string) (time.Duration, bool) {
+	// docker cp also reads from stopped containers, and writes a tar archive to stdout
+	c := exec.Command(ociBin, "cp", fmt.Sprintf("%s:%s", machineName, idleStopMarkerPath), "-")
+	data, err := c.Output()
+	if err != nil {
+		klog.Infof("no idle stop marker in %s: %v", machineName, err)
+		return 0, false
+	}
+	tr := tar.NewReader(bytes.NewReader(data))
+	if _, err := tr.Next(); err != nil {
+		return 0, false
+	}
+	marker, err := ioutil.ReadAll(tr)
+	if err != nil {
+		return 0, false
+	}
+	return parseIdleMarker(marker)
+}
+
+// parseIdleMarker parses the idle time in seconds written by the idle stop service
+func parseIdleMarker(marker []byte) (time.Duration, bool) {
+	secs, err := strconv.Atoi(strings.TrimSpace(string(marker)))
+	if err != nil {
+		klog.Warningf("invalid idle stop marker %q: %v", marker, err)
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// idleStopKubeconfig returns the kubeconfig the idle stop service uses: the admin one on the primary control plane, the kubelet one elsewhere
+func idleStopKubeconfig(primary bool) string {
+	if primary {
+		return path.Join(vmpath.GuestPersistentDir, "kubeconfig")
+	}
+	return "/etc/kubernetes/kubelet.conf"
+}
+
+// idleEnvironment returns the contents of the environment file of the minikube-idle-stop service
+func idleEnvironment(idle time.Duration, primary bool, primaryNode string, kubectl string) []byte {
+	role := "secondary"
+	if primary {
+		role = "primary"
+	}
+	env := []string{
+		fmt.Sprintf("IDLE=%d", int(idle.Seconds())),
+		"ROLE=" + role,
+		"NODE=" + primaryNode,
+		"KUBECTL=" + kubectl,
+		"KUBECONFIG=" + idleStopKubeconfig(primary),
+		"MARKER=" + idleStopMarkerPath,
+		"ANNOTATION=" + idleStopAnnotation,
+	}
+	return []byte(strings.Join(env, "\n") + "\n")
+}
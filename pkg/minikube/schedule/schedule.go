@@ -17,6 +17,7 @@ limitations under the License.
 package schedule
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,6 +27,18 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
+// Reasons recorded in the config of a cluster stopped by minikube
+const (
+	StoppedByUser     = "minikube stop was run"
+	StoppedBySchedule = "the scheduled stop was due"
+)
+
+// Reasons reported for a cluster which minikube has no record of stopping
+const (
+	StoppedOutside       = "stopped outside minikube"
+	StoppedOutsideOrIdle = "stopped outside minikube, or by the idle auto-stop"
+)
+
 // Daemonize daemonizes minikube so that scheduled stop happens as expected
 func Daemonize(profiles []string, duration time.Duration) error {
 	// save current time and expected duration in config
@@ -57,3 +70,75 @@ func Daemonize(profiles []string, duration time.Duration) error {
 	}
 	return nil
 }
+
+// TimeToStop returns how long is left before the scheduled stop of cc,
+// or false if no stop is scheduled or it is already due
+func TimeToStop(cc *config.ClusterConfig, now time.Time) (time.Duration, bool) {
+	if cc == nil || cc.ScheduledStop == nil {
+		return 0, false
+	}
+	initiationTime := time.Unix(cc.ScheduledStop.InitiationTime, 0)
+	left := initiationTime.Add(cc.ScheduledStop.Duration).Sub(now)
+	if left <= 0 {
+		return 0, false
+	}
+	return left, true
+}
+
+// Clear removes the scheduled stop saved in the config of each profile
+func Clear(profiles []string) error {
+	for _, p := range profiles {
+		cc, err := config.Load(p)
+		if err != nil {
+			return errors.Wrapf(err, "loading profile %s", p)
+		}
+		if cc.ScheduledStop == nil {
+			continue
+		}
+		cc.ScheduledStop = nil
+		if err := config.SaveProfile(p, cc); err != nil {
+			return errors.Wrapf(err, "saving profile %s", p)
+		}
+	}
+	return nil
+}
+
+// Stopped records why minikube stopped a profile, which no longer has a scheduled stop
+func Stopped(profile string, reason string) error {
+	cc, err := config.Load(profile)
+	if err != nil {
+		return errors.Wrapf(err, "loading profile %s", profile)
+	}
+	cc.ScheduledStop = nil
+	cc.StopReason = reason
+	return config.SaveProfile(profile, cc)
+}
+
+// StopReason returns why the stopped cluster of cc was stopped. Stops run by minikube record their reason.
+// Otherwise, the cluster stopped itself from within the node, or was stopped from outside minikube:
+// the idle auto-stop leaves a record on the node, which can only be read while the node is stopped for container drivers.
+func StopReason(cc *config.ClusterConfig) string {
+	if cc.StopReason != "" {
+		return cc.StopReason
+	}
+	if cc.AutoStopIdle > 0 && driver.IsKIC(cc.Driver) {
+		cp, err := config.PrimaryControlPlane(cc)
+		if err == nil {
+			if idle, ok := idleStoppedContainer(cc.Driver, config.MachineName(*cc, cp)); ok {
+				return IdleStopReason(idle)
+			}
+		}
+	}
+	if _, scheduled := TimeToStop(cc, time.Now()); cc.ScheduledStop != nil && !scheduled {
+		return StoppedBySchedule
+	}
+	if cc.AutoStopIdle > 0 && !driver.IsKIC(cc.Driver) {
+		return StoppedOutsideOrIdle
+	}
+	return StoppedOutside
+}
+
+// IdleStopReason is the stop reason of a cluster stopped by the idle auto-stop
+func IdleStopReason(idle time.Duration) string {
+	return fmt.Sprintf("the API server was idle for %s", idle)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestTimeToStop(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tests := []struct {
+		description string
+		cc          *config.ClusterConfig
+		want        time.Duration
+		scheduled   bool
+	}{
+		{"no config", nil, 0, false},
+		{"not scheduled", &config.ClusterConfig{}, 0, false},
+		{
+			description: "scheduled",
+			cc:          &config.ClusterConfig{ScheduledStop: &config.ScheduledStopConfig{InitiationTime: now.Add(-2 * time.Minute).Unix(), Duration: 5 * time.Minute}},
+			want:        3 * time.Minute,
+			scheduled:   true,
+		},
+		{
+			description: "already due",
+			cc:          &config.ClusterConfig{ScheduledStop: &config.ScheduledStopConfig{InitiationTime: now.Add(-10 * time.Minute).Unix(), Duration: 5 * time.Minute}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, scheduled := TimeToStop(test.cc, now)
+			if got != test.want || scheduled != test.scheduled {
+				t.Errorf("TimeToStop() = %v, %v; want %v, %v", got, scheduled, test.want, test.scheduled)
+			}
+		})
+	}
+}
+
+func TestStopReason(t *testing.T) {
+	due := &config.ScheduledStopConfig{InitiationTime: time.Now().Add(-time.Hour).Unix(), Duration: time.Minute}
+	tests := []struct {
+		description string
+		cc          config.ClusterConfig
+		want        string
+	}{
+		{"recorded", config.ClusterConfig{Name: "p1", Driver: "kvm2", StopReason: StoppedByUser, AutoStopIdle: time.Hour}, StoppedByUser},
+		{"scheduled stop in the node", config.ClusterConfig{Name: "p2", Driver: "kvm2", ScheduledStop: due}, StoppedBySchedule},
+		{"idle auto-stop enabled", config.ClusterConfig{Name: "p3", Driver: "kvm2", AutoStopIdle: 30 * time.Minute}, StoppedOutsideOrIdle},
+		{"unknown", config.ClusterConfig{Name: "p4", Driver: "kvm2"}, StoppedOutside},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			cc := test.cc
+			if got := StopReason(&cc); got != test.want {
+				t.Errorf("StopReason() = %q; want %q", got, test.want)
+			}
+			if cc.StopReason != test.cc.StopReason {
+				t.Errorf("StopReason() changed the config: %q", cc.StopReason)
+			}
+		})
+	}
+}
+
+func TestParseIdleMarker(t *testing.T) {
+	if idle, ok := parseIdleMarker([]byte("1800\n")); !ok || idle != 30*time.Minute {
+		t.Errorf("parseIdleMarker(1800) = %v, %v; want 30m, true", idle, ok)
+	}
+	if _, ok := parseIdleMarker([]byte("")); ok {
+		t.Errorf("parseIdleMarker of an empty marker should fail")
+	}
+}
+
+func TestIdleEnvironment(t *testing.T) {
+	got := string(idleEnvironment(time.Hour, false, "p1", "/var/lib/minikube/binaries/v1.20.0/kubectl"))
+	for _, want := range []string{"IDLE=3600\n", "ROLE=secondary\n", "NODE=p1\n", "KUBECONFIG=/etc/kubernetes/kubelet.conf\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("idleEnvironment() = %q, does not contain %q", got, want)
+		}
+	}
+	if got := string(idleEnvironment(time.Hour, true, "p1", "kubectl")); !strings.Contains(got, "ROLE=primary\n") {
+		t.Errorf("idleEnvironment() of the primary control plane = %q", got)
+	}
+}
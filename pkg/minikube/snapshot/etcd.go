@@ -0,0 +1,345 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	// etcdSnapshot is where an etcd snapshot is kept on a node while it is saved or restored
+	etcdSnapshot = "minikube-snapshot.db"
+	// etcdRestoreDir is the directory within the etcd data dir an etcd snapshot is restored into
+	etcdRestoreDir = "minikube-restore"
+	// etcdPeerPort is the port etcd members use to talk to each other
+	etcdPeerPort = 2380
+)
+
+// controlPlaneManifests are the static pods which must not run while etcd is restored
+var controlPlaneManifests = []string{"kube-apiserver.yaml", "kube-controller-manager.yaml", "kube-scheduler.yaml"}
+
+// nodeRuntime gives access to the container runtime and kubelet of a node
+type nodeRuntime struct {
+	node    config.Node
+	machine string
+	runner  command.Runner
+	cr      cruntime.Manager
+	init    sysinit.Manager
+	paused  []string // containers paused by pauseKubernetes
+	// etcdV2 is whether etcdctl defaults to the v2 API, which has no snapshot command, as in etcd 3.3 before Kubernetes v1.17
+	etcdV2 bool
+}
+
+// nodeRuntimes returns a nodeRuntime for every node of a cluster
+func nodeRuntimes(api libmachine.API, cc *config.ClusterConfig) ([]*nodeRuntime, error) {
+	kv, err := util.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse kubernetes version")
+	}
+	var nrs []*nodeRuntime
+	for _, n := range cc.Nodes {
+		machineName := config.MachineName(*cc, n)
+		h, err := machine.LoadHost(api, machineName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load host %s", machineName)
+		}
+		runner, err := machine.CommandRunner(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "command runner %s", machineName)
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			return nil, errors.Wrap(err, "container runtime")
+		}
+		nrs = append(nrs, &nodeRuntime{node: n, machine: machineName, runner: runner, cr: cr, init: sysinit.New(runner), etcdV2: kv.LT(semver.MustParse("1.17.0"))})
+	}
+	return nrs, nil
+}
+
+// pauseKubernetes stops the kubelet and pauses every Kubernetes container except etcd on every node,
+// so that the cluster can be saved at a single point in time while etcd stays reachable
+func pauseKubernetes(nrs []*nodeRuntime) error {
+	for _, nr := range nrs {
+		if err := nr.init.Stop("kubelet"); err != nil {
+			return errors.Wrapf(err, "stop kubelet on %s", nr.machine)
+		}
+		ids, err := nr.cr.ListContainers(cruntime.ListOptions{State: cruntime.Running})
+		if err != nil {
+			return errors.Wrapf(err, "list containers on %s", nr.machine)
+		}
+		etcd, err := nr.cr.ListContainers(cruntime.ListOptions{State: cruntime.Running, Name: "etcd"})
+		if err != nil {
+			return errors.Wrapf(err, "list etcd containers on %s", nr.machine)
+		}
+		ids = without(ids, etcd)
+		if len(ids) == 0 {
+			continue
+		}
+		if err := nr.cr.PauseContainers(ids); err != nil {
+			return errors.Wrapf(err, "pause containers on %s", nr.machine)
+		}
+		nr.paused = ids
+	}
+	return nil
+}
+
+// resumeKubernetes undoes pauseKubernetes, continuing past errors so that as much of the cluster as possible is running again
+func resumeKubernetes(nrs []*nodeRuntime) {
+	for _, nr := range nrs {
+		if len(nr.paused) > 0 {
+			if err := nr.cr.UnpauseContainers(nr.paused); err != nil {
+				klog.Errorf("unable to unpause containers on %s: %v", nr.machine, err)
+			}
+			nr.paused = nil
+		}
+		if err := nr.init.Start("kubelet"); err != nil {
+			klog.Errorf("unable to start kubelet on %s: %v", nr.machine, err)
+		}
+	}
+}
+
+// without returns the elements of ids which are not in exclude
+func without(ids []string, exclude []string) []string {
+	skip := map[string]bool{}
+	for _, id := range exclude {
+		skip[id] = true
+	}
+	var result []string
+	for _, id := range ids {
+		if !skip[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// etcdContainer returns the id of the running etcd container of a node, waiting for it to start
+func etcdContainer(nr *nodeRuntime, timeout time.Duration) (string, error) {
+	var id string
+	find := func() error {
+		ids, err := nr.cr.ListContainers(cruntime.ListOptions{State: cruntime.Running, Name: "etcd"})
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return fmt.Errorf("etcd is not running on %s", nr.machine)
+		}
+		id = ids[0]
+		return nil
+	}
+	if err := retry.Expo(find, time.Second, timeout); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// etcdctl runs etcdctl within an etcd container, as the API server may not be available to exec through
+func etcdctl(nr *nodeRuntime, id string, args ...string) error {
+	cmd := []string{"crictl", "exec", id, "etcdctl"}
+	if nr.etcdV2 {
+		// crictl exec can not set the environment, but the etcd 3.3 image has env
+		cmd = []string{"crictl", "exec", id, "env", "ETCDCTL_API=3", "etcdctl"}
+	}
+	if nr.cr.Name() == "Docker" {
+		cmd = []string{"docker", "exec", "-e", "ETCDCTL_API=3", id, "etcdctl"}
+	}
+	c := exec.Command("sudo", append(cmd, args...)...)
+	if _, err := nr.runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "etcdctl")
+	}
+	return nil
+}
+
+// saveEtcd takes an etcd snapshot on the primary control plane and copies it to dst
+func saveEtcd(nr *nodeRuntime, dst string) error {
+	id, err := etcdContainer(nr, time.Minute)
+	if err != nil {
+		return err
+	}
+
+	src := path.Join(bsutil.EtcdDataDir(), etcdSnapshot)
+	certs := path.Join(vmpath.GuestKubernetesCertsDir, "etcd")
+	err = etcdctl(nr, id, "--endpoints=https://127.0.0.1:2379",
+		"--cacert="+path.Join(certs, "ca.crt"), "--cert="+path.Join(certs, "healthcheck-client.crt"), "--key="+path.Join(certs, "healthcheck-client.key"),
+		"snapshot", "save", src)
+	if err != nil {
+		return errors.Wrap(err, "snapshot save")
+	}
+	defer func() {
+		if _, err := nr.runner.RunCmd(exec.Command("sudo", "rm", "-f", src)); err != nil {
+			klog.Warningf("unable to remove %s: %v", src, err)
+		}
+	}()
+	return nr.runner.CopyFrom(src, dst)
+}
+
+// restoreEtcd replaces the etcd data of every control plane with an etcd snapshot, before the rest of the control plane starts again.
+// The nodes must already have been restored, and the kubelet is left running on every node.
+func restoreEtcd(cc *config.ClusterConfig, nrs []*nodeRuntime, src string) error {
+	defer resumeKubernetes(nrs)
+
+	var cps []*nodeRuntime
+	var members []string
+	for _, nr := range nrs {
+		// A reverted virtual machine resumes with the containers paused when the snapshot was taken
+		paused, err := nr.cr.ListContainers(cruntime.ListOptions{State: cruntime.Paused})
+		if err != nil {
+			return errors.Wrapf(err, "list paused containers on %s", nr.machine)
+		}
+		if len(paused) > 0 {
+			if err := nr.cr.UnpauseContainers(paused); err != nil {
+				return errors.Wrapf(err, "unpause containers on %s", nr.machine)
+			}
+		}
+		if nr.node.ControlPlane {
+			cps = append(cps, nr)
+			members = append(members, fmt.Sprintf("%s=%s", bsutil.KubeNodeName(*cc, nr.node), peerURL(nr.node)))
+		}
+	}
+
+	// If the restore fails part way, bring back the whole control plane rather than leaving only etcd running
+	defer func() {
+		for _, nr := range cps {
+			if err := restoreManifests(nr); err != nil {
+				klog.Errorf("unable to restore manifests: %v", err)
+			}
+		}
+	}()
+
+	etcdDir := bsutil.EtcdDataDir()
+	db := path.Join(etcdDir, etcdSnapshot)
+	restoreDir := path.Join(etcdDir, etcdRestoreDir)
+	for _, nr := range cps {
+		if err := stopKubernetes(nr); err != nil {
+			return err
+		}
+		// Start the kubelet with only the etcd manifest, so that etcdctl can be run in the etcd image
+		if err := setAsideManifests(nr); err != nil {
+			return err
+		}
+		if err := nr.init.Start("kubelet"); err != nil {
+			return errors.Wrapf(err, "start kubelet on %s", nr.machine)
+		}
+		id, err := etcdContainer(nr, 2*time.Minute)
+		if err != nil {
+			return err
+		}
+
+		f, err := assets.NewFileAsset(src, etcdDir, etcdSnapshot, "0600")
+		if err != nil {
+			return errors.Wrap(err, "etcd snapshot")
+		}
+		if err := nr.runner.Copy(f); err != nil {
+			return errors.Wrapf(err, "copy etcd snapshot to %s", nr.machine)
+		}
+		if _, err := nr.runner.RunCmd(exec.Command("sudo", "rm", "-rf", restoreDir)); err != nil {
+			return errors.Wrapf(err, "remove %s on %s", restoreDir, nr.machine)
+		}
+		err = etcdctl(nr, id, "snapshot", "restore", db, "--data-dir="+restoreDir,
+			"--name="+bsutil.KubeNodeName(*cc, nr.node),
+			"--initial-cluster="+strings.Join(members, ","),
+			"--initial-advertise-peer-urls="+peerURL(nr.node))
+		if err != nil {
+			return errors.Wrapf(err, "snapshot restore on %s", nr.machine)
+		}
+	}
+
+	// Only swap in the restored data once every member has it, so the members agree on the cluster they form
+	for _, nr := range cps {
+		if err := stopKubernetes(nr); err != nil {
+			return err
+		}
+		swap := fmt.Sprintf("rm -rf %[1]s/member && mv %[2]s/member %[1]s/member && rm -rf %[2]s %[3]s", etcdDir, restoreDir, db)
+		if _, err := nr.runner.RunCmd(exec.Command("sudo", "/bin/bash", "-c", swap)); err != nil {
+			return errors.Wrapf(err, "replace etcd data on %s", nr.machine)
+		}
+		if err := restoreManifests(nr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stopKubernetes stops the kubelet and every running Kubernetes container of a node
+func stopKubernetes(nr *nodeRuntime) error {
+	if err := nr.init.Stop("kubelet"); err != nil {
+		return errors.Wrapf(err, "stop kubelet on %s", nr.machine)
+	}
+	ids, err := nr.cr.ListContainers(cruntime.ListOptions{State: cruntime.Running})
+	if err != nil {
+		return errors.Wrapf(err, "list containers on %s", nr.machine)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := nr.cr.StopContainers(ids); err != nil {
+		return errors.Wrapf(err, "stop containers on %s", nr.machine)
+	}
+	return nil
+}
+
+// manifestsBackupDir is where the control plane manifests are kept while etcd is restored
+func manifestsBackupDir() string {
+	return path.Join(vmpath.GuestPersistentDir, "snapshot-manifests")
+}
+
+// setAsideManifests moves the control plane manifests other than etcd out of the manifests directory
+func setAsideManifests(nr *nodeRuntime) error {
+	var srcs []string
+	for _, m := range controlPlaneManifests {
+		srcs = append(srcs, path.Join(vmpath.GuestManifestsDir, m))
+	}
+	c := exec.Command("sudo", "/bin/bash", "-c", fmt.Sprintf("mkdir -p %[1]s && mv -f %[2]s %[1]s/", manifestsBackupDir(), strings.Join(srcs, " ")))
+	if _, err := nr.runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "set aside manifests on %s", nr.machine)
+	}
+	return nil
+}
+
+// restoreManifests undoes setAsideManifests, if it was done
+func restoreManifests(nr *nodeRuntime) error {
+	c := exec.Command("sudo", "/bin/bash", "-c", fmt.Sprintf("if [ -d %[1]s ]; then mv -f %[1]s/*.yaml %[2]s/ && rmdir %[1]s; fi", manifestsBackupDir(), vmpath.GuestManifestsDir))
+	if _, err := nr.runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "restore manifests on %s", nr.machine)
+	}
+	return nil
+}
+
+// peerURL returns the URL other etcd members reach the member on a control plane at
+func peerURL(n config.Node) string {
+	return fmt.Sprintf("https://%s:%d", n.IP, etcdPeerPort)
+}
@@ -0,0 +1,467 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot saves and restores the state of a cluster
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/drivers/kic"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+const (
+	// metadataFile is the file within a snapshot directory describing the snapshot
+	metadataFile = "snapshot.json"
+	// etcdFile is the file within a snapshot directory holding the etcd snapshot
+	etcdFile = "etcd.db"
+)
+
+// validName matches names which are usable as an image tag and as a libvirt snapshot name
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// Snapshot describes a saved snapshot of a cluster
+type Snapshot struct {
+	Name              string
+	Profile           string
+	Driver            string
+	KubernetesVersion string
+	Created           time.Time
+	Size              int64 // bytes on disk, including committed container images
+	Nodes             []Node
+	Config            *config.ClusterConfig // the cluster config when the snapshot was taken
+}
+
+// Node describes the saved state of a node
+type Node struct {
+	Machine string
+	Image   string `json:",omitempty"` // committed container image, only for KIC
+	Volume  string `json:",omitempty"` // archive of the node volume within the snapshot directory, only for KIC
+}
+
+// Dir returns the directory a snapshot is stored in
+func Dir(profile string, name string) string {
+	return filepath.Join(localpath.Profile(profile), "snapshots", name)
+}
+
+// Supported returns whether snapshots are supported for a driver
+func Supported(drvName string) bool {
+	return driver.IsKIC(drvName) || drvName == driver.KVM2
+}
+
+// ValidName returns whether name is a valid snapshot name
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
+// Save takes a snapshot of a running cluster: an etcd snapshot, plus the filesystem of every node
+func Save(api libmachine.API, cc *config.ClusterConfig, name string) (*Snapshot, error) {
+	if !ValidName(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q: only alphanumeric characters, '_', '.' and '-' are permitted, starting with alphanumeric", name)
+	}
+	if !Supported(cc.Driver) {
+		return nil, fmt.Errorf("the %s driver does not support snapshots", cc.Driver)
+	}
+	dir := Dir(cc.Name, name)
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("snapshot %q already exists", name)
+	}
+
+	for _, n := range cc.Nodes {
+		machineName := config.MachineName(*cc, n)
+		st, err := machine.Status(api, machineName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "status %s", machineName)
+		}
+		if st != state.Running.String() {
+			return nil, fmt.Errorf("node %s must be running, but is %s", machineName, st)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	s := &Snapshot{
+		Name:              name,
+		Profile:           cc.Name,
+		Driver:            cc.Driver,
+		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		Created:           time.Now(),
+		Config:            cc,
+	}
+
+	err := func() error {
+		nrs, err := nodeRuntimes(api, cc)
+		if err != nil {
+			return err
+		}
+		// Every node is paused before anything is saved, so that etcd and the nodes are saved at the same point in time
+		defer resumeKubernetes(nrs)
+		if err := pauseKubernetes(nrs); err != nil {
+			return errors.Wrap(err, "pause")
+		}
+		if err := saveEtcd(primary(cc, nrs), filepath.Join(dir, etcdFile)); err != nil {
+			return errors.Wrap(err, "etcd snapshot")
+		}
+		for _, n := range cc.Nodes {
+			sn, err := saveNode(cc, n, name, dir)
+			if sn != nil {
+				s.Nodes = append(s.Nodes, *sn)
+			}
+			if err != nil {
+				return errors.Wrapf(err, "node %s", config.MachineName(*cc, n))
+			}
+		}
+		return writeMetadata(dir, s)
+	}()
+	if err != nil {
+		if rerr := remove(cc, s, dir); rerr != nil {
+			klog.Warningf("unable to remove the remains of snapshot %q: %v", name, rerr)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// Restore returns a cluster to the state of a snapshot, and leaves it running
+func Restore(api libmachine.API, cc *config.ClusterConfig, name string) error {
+	s, err := Load(cc.Name, name)
+	if err != nil {
+		return err
+	}
+	if s.Driver != cc.Driver {
+		return fmt.Errorf("snapshot %q was taken with the %s driver, but the cluster uses the %s driver", name, s.Driver, cc.Driver)
+	}
+
+	var want, got []string
+	for _, n := range s.Nodes {
+		want = append(want, n.Machine)
+	}
+	for _, n := range cc.Nodes {
+		got = append(got, config.MachineName(*cc, n))
+	}
+	sort.Strings(want)
+	sort.Strings(got)
+	if strings.Join(want, ",") != strings.Join(got, ",") {
+		return fmt.Errorf("snapshot %q has nodes %v, but the cluster now has nodes %v", name, want, got)
+	}
+
+	dir := Dir(cc.Name, name)
+	switch {
+	case driver.IsKIC(cc.Driver):
+		err = restoreKIC(api, cc.Driver, s, dir)
+	case cc.Driver == driver.KVM2:
+		err = restoreKVM(cc, s)
+	default:
+		err = fmt.Errorf("the %s driver does not support snapshots", cc.Driver)
+	}
+	if err != nil {
+		return err
+	}
+	if s.Config != nil {
+		if err := config.SaveProfile(cc.Name, s.Config); err != nil {
+			return errors.Wrap(err, "save profile")
+		}
+		cc = s.Config
+	}
+
+	nrs, err := nodeRuntimes(api, cc)
+	if err != nil {
+		return err
+	}
+	if err := restoreEtcd(cc, nrs, filepath.Join(dir, etcdFile)); err != nil {
+		return errors.Wrap(err, "restore etcd")
+	}
+	return waitForAPIServer(api, cc)
+}
+
+// Delete removes a snapshot, including its container images or libvirt snapshots
+func Delete(cc *config.ClusterConfig, name string) error {
+	s, err := Load(cc.Name, name)
+	if err != nil {
+		return err
+	}
+	return remove(cc, s, Dir(cc.Name, name))
+}
+
+// DeleteAll removes every snapshot of a cluster
+func DeleteAll(cc *config.ClusterConfig) error {
+	snapshots, err := List(cc.Name)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, s := range snapshots {
+		if err := remove(cc, s, Dir(cc.Name, s.Name)); err != nil {
+			klog.Warningf("unable to delete snapshot %q: %v", s.Name, err)
+			failed = append(failed, s.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to delete snapshots: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Load returns the snapshot of a profile with the given name
+func Load(profile string, name string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(filepath.Join(Dir(profile, name), metadataFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %q not found", name)
+		}
+		return nil, errors.Wrap(err, "reading snapshot")
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, errors.Wrapf(err, "parsing snapshot %q", name)
+	}
+	return s, nil
+}
+
+// List returns the snapshots of a profile, oldest first
+func List(profile string) ([]*Snapshot, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(localpath.Profile(profile), "snapshots"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		s, err := Load(profile, e.Name())
+		if err != nil {
+			klog.Warningf("skipping invalid snapshot %s: %v", e.Name(), err)
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+// primary returns the nodeRuntime of the primary control plane
+func primary(cc *config.ClusterConfig, nrs []*nodeRuntime) *nodeRuntime {
+	for _, nr := range nrs {
+		if nr.node.ControlPlane {
+			return nr
+		}
+	}
+	return nrs[0]
+}
+
+// saveNode snapshots the filesystem of a node
+func saveNode(cc *config.ClusterConfig, n config.Node, name string, dir string) (*Node, error) {
+	machineName := config.MachineName(*cc, n)
+	sn := &Node{Machine: machineName}
+
+	if cc.Driver == driver.KVM2 {
+		// libvirt snapshots of a running domain include its memory, and are stored by libvirt
+		if err := virsh(cc.KVMQemuURI, "snapshot-create-as", "--domain", machineName, "--name", name); err != nil {
+			return nil, err
+		}
+		return sn, nil
+	}
+
+	ociBin := cc.Driver
+	image := fmt.Sprintf("minikube-snapshot/%s:%s", strings.ToLower(machineName), name)
+	archive := machineName + "-var.tar.lz4"
+
+	// Pause the node so that its filesystem and volume are captured at the same point in time
+	if err := oci.PauseContainer(ociBin, machineName); err != nil {
+		return nil, errors.Wrap(err, "pause")
+	}
+	defer func() {
+		if err := oci.UnpauseContainer(ociBin, machineName); err != nil {
+			klog.Errorf("unable to unpause %s: %v", machineName, err)
+		}
+	}()
+
+	if err := oci.CommitContainer(ociBin, machineName, image); err != nil {
+		return nil, errors.Wrap(err, "commit")
+	}
+	sn.Image = image
+	// The committed image is used to run tar, as it is guaranteed to be available locally
+	if err := oci.ExportVolumeToTarball(ociBin, machineName, filepath.Join(dir, archive), image); err != nil {
+		return sn, errors.Wrap(err, "export volume")
+	}
+	sn.Volume = archive
+	return sn, nil
+}
+
+// restoreKIC replaces the containers and volumes of every node with their snapshot
+func restoreKIC(api libmachine.API, ociBin string, s *Snapshot, dir string) error {
+	for _, n := range s.Nodes {
+		if err := oci.DeleteContainer(ociBin, n.Machine); err != nil {
+			return errors.Wrapf(err, "delete %s", n.Machine)
+		}
+	}
+
+	for _, n := range s.Nodes {
+		if err := oci.RestoreVolumeFromTarball(ociBin, filepath.Join(dir, n.Volume), n.Machine, n.Image); err != nil {
+			return errors.Wrapf(err, "restore volume %s", n.Machine)
+		}
+
+		h, err := machine.LoadHost(api, n.Machine)
+		if err != nil {
+			return errors.Wrapf(err, "load host %s", n.Machine)
+		}
+		d, ok := h.Driver.(*kic.Driver)
+		if !ok {
+			return fmt.Errorf("unexpected driver for %s: %T", n.Machine, h.Driver)
+		}
+		if err := d.CreateFromImage(n.Image); err != nil {
+			return errors.Wrapf(err, "create %s", n.Machine)
+		}
+	}
+	return nil
+}
+
+// restoreKVM reverts every node to its libvirt snapshot
+func restoreKVM(cc *config.ClusterConfig, s *Snapshot) error {
+	for _, n := range s.Nodes {
+		if err := virsh(cc.KVMQemuURI, "snapshot-revert", "--domain", n.Machine, "--snapshotname", s.Name, "--running", "--force"); err != nil {
+			return errors.Wrapf(err, "revert %s", n.Machine)
+		}
+	}
+	return nil
+}
+
+// waitForAPIServer waits for the API server of a restored cluster to be running
+func waitForAPIServer(api libmachine.API, cc *config.ClusterConfig) error {
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+	h, err := machine.LoadHost(api, config.MachineName(*cc, cp))
+	if err != nil {
+		return errors.Wrap(err, "load host")
+	}
+
+	check := func() error {
+		runner, err := machine.CommandRunner(h)
+		if err != nil {
+			return err
+		}
+		hostname, _, port, err := driver.ControlPlaneEndpoint(cc, &cp, h.DriverName)
+		if err != nil {
+			return err
+		}
+		st, err := kverify.APIServerStatus(runner, hostname, port)
+		if err != nil {
+			return err
+		}
+		if st != state.Running {
+			return fmt.Errorf("apiserver is %s", st)
+		}
+		return nil
+	}
+	return retry.Expo(check, time.Second, 4*time.Minute)
+}
+
+// writeMetadata records the size of a snapshot, and writes its description to its directory
+func writeMetadata(dir string, s *Snapshot) error {
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			s.Size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "size")
+	}
+	for _, n := range s.Nodes {
+		if n.Image == "" {
+			continue
+		}
+		size, err := oci.ImageSize(s.Driver, n.Image)
+		if err != nil {
+			return errors.Wrapf(err, "image size %s", n.Image)
+		}
+		s.Size += size
+	}
+
+	data, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, metadataFile), data, 0o644)
+}
+
+// remove deletes a snapshot: its libvirt snapshots, its container images and its directory
+func remove(cc *config.ClusterConfig, s *Snapshot, dir string) error {
+	var errs []string
+	for _, n := range s.Nodes {
+		if cc.Driver == driver.KVM2 {
+			if err := virsh(cc.KVMQemuURI, "snapshot-delete", "--domain", n.Machine, "--snapshotname", s.Name); err != nil {
+				errs = append(errs, fmt.Sprintf("libvirt snapshot of %s: %v", n.Machine, err))
+			}
+		}
+		if n.Image != "" {
+			if err := oci.RemoveImage(s.Driver, n.Image); err != nil {
+				errs = append(errs, fmt.Sprintf("image %s: %v", n.Image, err))
+			}
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// virsh runs a virsh command against a libvirt connection
+func virsh(uri string, args ...string) error {
+	c := exec.Command("virsh", append([]string{"-c", uri}, args...)...)
+	klog.Infof("Run: %v", c.Args)
+	if out, err := c.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "%s: %s", strings.Join(c.Args, " "), out)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func TestValidName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"seeded":     true,
+		"v1.2_final": true,
+		"":           false,
+		"-seeded":    false,
+		"a/b":        false,
+		"a:b":        false,
+	} {
+		if got := ValidName(name); got != want {
+			t.Errorf("ValidName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmpDir)
+
+	snapshots, err := List("p1")
+	if err != nil || len(snapshots) != 0 {
+		t.Fatalf("List without snapshots = %v, %v; want no snapshots", snapshots, err)
+	}
+
+	now := time.Now()
+	for _, s := range []*Snapshot{
+		{Name: "newer", Profile: "p1", Driver: "kvm2", Created: now, Nodes: []Node{{Machine: "p1"}}},
+		{Name: "older", Profile: "p1", Driver: "kvm2", Created: now.Add(-time.Hour), Nodes: []Node{{Machine: "p1"}}},
+	} {
+		dir := Dir(s.Profile, s.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, etcdFile), make([]byte, 1024), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := writeMetadata(dir, s); err != nil {
+			t.Fatalf("writeMetadata: %v", err)
+		}
+	}
+	// Directories without a description are ignored
+	if err := os.MkdirAll(Dir("p1", "partial"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	snapshots, err = List("p1")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "older" || snapshots[1].Name != "newer" {
+		t.Fatalf("List = %+v, want [older newer]", snapshots)
+	}
+	if snapshots[0].Size != 1024 {
+		t.Errorf("Size = %d, want 1024", snapshots[0].Size)
+	}
+
+	if _, err := Load("p1", "missing"); err == nil {
+		t.Errorf("Load of a missing snapshot should fail")
+	}
+}
+
+func TestDeleteAll(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Setenv(localpath.MinikubeHome, os.Getenv(localpath.MinikubeHome))
+	os.Setenv(localpath.MinikubeHome, tmpDir)
+
+	cc := &config.ClusterConfig{Name: "p1", Driver: "docker"}
+	for _, name := range []string{"first", "second"} {
+		dir := Dir(cc.Name, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := writeMetadata(dir, &Snapshot{Name: name, Profile: cc.Name, Driver: cc.Driver, Created: time.Now(), Nodes: []Node{{Machine: "p1"}}}); err != nil {
+			t.Fatalf("writeMetadata: %v", err)
+		}
+	}
+
+	if err := Delete(cc, "missing"); err == nil {
+		t.Errorf("Delete of a missing snapshot should fail")
+	}
+	if err := Delete(cc, "first"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(Dir(cc.Name, "first")); !os.IsNotExist(err) {
+		t.Errorf("snapshot directory still exists after Delete: %v", err)
+	}
+	if err := DeleteAll(cc); err != nil {
+		t.Fatalf("DeleteAll: %v", err)
+	}
+	if snapshots, err := List(cc.Name); err != nil || len(snapshots) != 0 {
+		t.Errorf("List after DeleteAll = %v, %v; want no snapshots", snapshots, err)
+	}
+}
+
+func TestWithout(t *testing.T) {
+	got := without([]string{"a", "b", "c"}, []string{"b"})
+	if strings.Join(got, ",") != "a,c" {
+		t.Errorf("without = %v, want [a c]", got)
+	}
+}
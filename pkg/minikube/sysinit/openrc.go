@@ -24,6 +24,7 @@ import (
 	"html/template"
 	"os/exec"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -83,6 +84,12 @@ case "$1" in
 esac
 `))
 
+const (
+	// openrcEnvBegin and openrcEnvEnd delimit the block minikube manages in the conf.d file of a service
+	openrcEnvBegin = "# BEGIN minikube environment"
+	openrcEnvEnd   = "# END minikube environment"
+)
+
 // OpenRC is a service manager for OpenRC-like init systems
 type OpenRC struct {
 	r Runner
@@ -175,3 +182,32 @@ func (s *OpenRC) GenerateInitShim(svc string, binary string, unit string) ([]ass
 
 	return files, nil
 }
+
+// openrcEnvironment renders the conf.d block exporting the environment of a service
+func openrcEnvironment(env []string) string {
+	var b strings.Builder
+	b.WriteString(openrcEnvBegin + "\n")
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 {
+			klog.Warningf("skipping invalid environment variable %q", e)
+			continue
+		}
+		fmt.Fprintf(&b, "export %s='%s'\n", kv[0], strings.ReplaceAll(kv[1], "'", `'\''`))
+	}
+	b.WriteString(openrcEnvEnd + "\n")
+	return b.String()
+}
+
+// SetEnvironment sets the environment of a service in a block of its conf.d file, leaving the rest of the file alone
+func (s *OpenRC) SetEnvironment(svc string, env []string) error {
+	conf := path.Join("/etc/conf.d", svc)
+	clear := fmt.Sprintf("sudo mkdir -p %s && sudo touch %s && sudo sed -i '/^%s$/,/^%s$/d' %s", path.Dir(conf), conf, openrcEnvBegin, openrcEnvEnd, conf)
+	if _, err := s.r.RunCmd(exec.Command("/bin/bash", "-c", clear)); err != nil {
+		return errors.Wrap(err, "clear environment")
+	}
+	if len(env) == 0 {
+		return nil
+	}
+	return writeFile(s.r, conf, openrcEnvironment(env), true)
+}
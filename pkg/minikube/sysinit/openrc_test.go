@@ -0,0 +1,34 @@
+/*
+Copyright 2021 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sysinit
+
+import (
+	"testing"
+)
+
+func TestOpenRCEnvironment(t *testing.T) {
+	got := openrcEnvironment([]string{"HTTP_PROXY=http://proxy:3128", "NO_PROXY=localhost,10.96.0.0/12", "QUOTE=it's", "INVALID"})
+	want := `# BEGIN minikube environment
+export HTTP_PROXY='http://proxy:3128'
+export NO_PROXY='localhost,10.96.0.0/12'
+export QUOTE='it'\''s'
+# END minikube environment
+`
+	if got != want {
+		t.Errorf("openrcEnvironment() = %q, want %q", got, want)
+	}
+}
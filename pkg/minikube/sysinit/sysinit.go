@@ -17,7 +17,10 @@ limitations under the License.
 package sysinit
 
 import (
+	"encoding/base64"
+	"fmt"
 	"os/exec"
+	"path"
 
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
@@ -61,6 +64,9 @@ type Manager interface {
 
 	// GenerateInitShim generates any additional init files required for this service
 	GenerateInitShim(svc string, binary string, unit string) ([]assets.CopyableFile, error)
+
+	// SetEnvironment sets the environment (KEY=VALUE) of a service, taking effect when it is restarted
+	SetEnvironment(svc string, env []string) error
 }
 
 // New returns an appropriately configured service manager
@@ -86,3 +92,14 @@ func New(r Runner) Manager {
 	}
 	return &OpenRC{r: r}
 }
+
+// writeFile writes contents to a file, creating its directory if needed
+func writeFile(r Runner, dst string, contents string, appendTo bool) error {
+	tee := "sudo tee"
+	if appendTo {
+		tee = "sudo tee -a"
+	}
+	c := exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -p %s && printf %%s %s | base64 -d | %s %s >/dev/null", path.Dir(dst), base64.StdEncoding.EncodeToString([]byte(contents)), tee, dst))
+	_, err := r.RunCmd(c)
+	return err
+}
@@ -19,7 +19,9 @@ package sysinit
 
 import (
 	"errors"
+	"fmt"
 	"os/exec"
+	"strings"
 
 	"k8s.io/minikube/pkg/minikube/assets"
 )
@@ -105,6 +107,31 @@ func (s *Systemd) GenerateInitShim(svc string, binary string, unit string) ([]as
 	return nil, nil
 }
 
+// environmentDropIn returns the path of the drop-in holding the environment of a service
+func environmentDropIn(svc string) string {
+	return fmt.Sprintf("/etc/systemd/system/%s.service.d/10-minikube-env.conf", svc)
+}
+
+// systemdEnvironment renders a drop-in setting the environment of a service
+func systemdEnvironment(env []string) string {
+	var b strings.Builder
+	b.WriteString("[Service]\n")
+	for _, e := range env {
+		fmt.Fprintf(&b, "Environment=%q\n", strings.ReplaceAll(e, "%", "%%"))
+	}
+	return b.String()
+}
+
+// SetEnvironment sets the environment of a service through a drop-in, which is removed when env is empty
+func (s *Systemd) SetEnvironment(svc string, env []string) error {
+	dropIn := environmentDropIn(svc)
+	if len(env) == 0 {
+		_, err := s.r.RunCmd(exec.Command("sudo", "rm", "-f", dropIn))
+		return err
+	}
+	return writeFile(s.r, dropIn, systemdEnvironment(env), false)
+}
+
 func usesSystemd(r Runner) bool {
 	_, err := r.RunCmd(exec.Command("systemctl", "--version"))
 	return err == nil
@@ -55,3 +55,15 @@ func TestEnable(t *testing.T) {
 		})
 	}
 }
+
+func TestSystemdEnvironment(t *testing.T) {
+	got := systemdEnvironment([]string{"HTTP_PROXY=http://proxy:3128", "NO_PROXY=localhost,10.96.0.0/12", "PASSWORD=50%"})
+	want := `[Service]
+Environment="HTTP_PROXY=http://proxy:3128"
+Environment="NO_PROXY=localhost,10.96.0.0/12"
+Environment="PASSWORD=50%%"
+`
+	if got != want {
+		t.Errorf("systemdEnvironment() = %q, want %q", got, want)
+	}
+}
@@ -18,11 +18,15 @@ package util
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"fmt"
 	"io/ioutil"
 	"math/big"
 	"net"
@@ -35,6 +39,78 @@ import (
 	"k8s.io/minikube/pkg/util/lock"
 )
 
+const (
+	// RSA is the RSA key algorithm
+	RSA = "rsa"
+	// ECDSA is the ECDSA key algorithm
+	ECDSA = "ecdsa"
+)
+
+// CertOptions are the key, validity and groups of the certificates GenerateSignedCert generates
+type CertOptions struct {
+	// KeyAlgorithm is rsa or ecdsa
+	KeyAlgorithm string
+	// KeySize is the length of RSA keys in bits, or the size of the ECDSA curve: 256, 384 or 521
+	KeySize int
+	// Validity is how long certificates are valid for
+	Validity time.Duration
+	// Groups are the organizations of the subject, which Kubernetes authenticates as the groups of a client
+	Groups []string
+}
+
+// DefaultCertOptions are the options of the certificates minikube generates, unless configured otherwise
+var DefaultCertOptions = CertOptions{KeyAlgorithm: RSA, KeySize: 2048, Validity: time.Hour * 24 * 365}
+
+// WithDefaults returns the options, with the defaults for those which are unset
+func (o CertOptions) WithDefaults() CertOptions {
+	if o.KeyAlgorithm == "" {
+		o.KeyAlgorithm = DefaultCertOptions.KeyAlgorithm
+	}
+	if o.KeySize == 0 {
+		o.KeySize = DefaultCertOptions.KeySize
+		if o.KeyAlgorithm == ECDSA {
+			o.KeySize = 256
+		}
+	}
+	if o.Validity == 0 {
+		o.Validity = DefaultCertOptions.Validity
+	}
+	return o
+}
+
+// Validate returns an error if the options are not supported
+func (o CertOptions) Validate() error {
+	switch o.KeyAlgorithm {
+	case RSA:
+		if o.KeySize < 2048 {
+			return fmt.Errorf("RSA keys must be at least 2048 bits, not %d", o.KeySize)
+		}
+	case ECDSA:
+		if _, err := curve(o.KeySize); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported key algorithm %q, expected %s or %s", o.KeyAlgorithm, RSA, ECDSA)
+	}
+	if o.Validity <= 0 {
+		return fmt.Errorf("certificates must be valid for a positive duration, not %s", o.Validity)
+	}
+	return nil
+}
+
+// curve returns the ECDSA curve of a size
+func curve(size int) (elliptic.Curve, error) {
+	switch size {
+	case 256:
+		return elliptic.P256(), nil
+	case 384:
+		return elliptic.P384(), nil
+	case 521:
+		return elliptic.P521(), nil
+	}
+	return nil, fmt.Errorf("unsupported ECDSA curve size %d, expected 256, 384 or 521", size)
+}
+
 // GenerateCACert generates a CA certificate and RSA key for a common name
 func GenerateCACert(certPath, keyPath string, name string) error {
 	priv, err := rsa.GenerateKey(rand.Reader, 2048)
@@ -65,51 +141,47 @@ func GenerateCACert(certPath, keyPath string, name string) error {
 // Any parent directories of the certPath or keyPath will be created as needed with file mode 0755.
 
 // GenerateSignedCert generates a signed certificate and key
-func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS []string, signerCertPath, signerKeyPath string) error {
+func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS []string, signerCertPath, signerKeyPath string, opts CertOptions) error {
 	klog.Infof("Generating cert %s with IP's: %s", certPath, ips)
-	signerCertBytes, err := ioutil.ReadFile(signerCertPath)
-	if err != nil {
-		return errors.Wrap(err, "Error reading file: signerCertPath")
-	}
-	decodedSignerCert, _ := pem.Decode(signerCertBytes)
-	if decodedSignerCert == nil {
-		return errors.New("Unable to decode certificate")
+	opts = opts.WithDefaults()
+	if err := opts.Validate(); err != nil {
+		return errors.Wrap(err, "cert options")
 	}
-	signerCert, err := x509.ParseCertificate(decodedSignerCert.Bytes)
+	signerCert, err := readCert(signerCertPath)
 	if err != nil {
-		return errors.Wrap(err, "Error parsing certificate: decodedSignerCert.Bytes")
+		return errors.Wrap(err, "signerCertPath")
 	}
-	signerKeyBytes, err := ioutil.ReadFile(signerKeyPath)
+	signerKey, err := readPrivateKey(signerKeyPath)
 	if err != nil {
-		return errors.Wrap(err, "Error reading file: signerKeyPath")
+		return errors.Wrap(err, "signerKeyPath")
 	}
-	decodedSignerKey, _ := pem.Decode(signerKeyBytes)
-	if decodedSignerKey == nil {
-		return errors.New("Unable to decode key")
-	}
-	signerKey, err := x509.ParsePKCS1PrivateKey(decodedSignerKey.Bytes)
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
 	if err != nil {
-		return errors.Wrap(err, "Error parsing private key: decodedSignerKey.Bytes")
+		return errors.Wrap(err, "Error generating serial number")
 	}
-
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(2),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			CommonName:   cn,
-			Organization: []string{"system:masters"},
+			Organization: opts.Groups,
 		},
 		NotBefore: time.Now().Add(time.Hour * -24),
-		NotAfter:  time.Now().Add(time.Hour * 24 * 365),
+		NotAfter:  time.Now().Add(opts.Validity),
 
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		BasicConstraintsValid: true,
 	}
+	// ECDSA keys can't encipher other keys
+	if opts.KeyAlgorithm == ECDSA {
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+	}
 
 	template.IPAddresses = append(template.IPAddresses, ips...)
 	template.DNSNames = append(template.DNSNames, alternateDNS...)
 
-	priv, err := loadOrGeneratePrivateKey(keyPath)
+	priv, err := loadOrGeneratePrivateKey(keyPath, opts)
 	if err != nil {
 		return errors.Wrap(err, "Error loading or generating private key: keyPath")
 	}
@@ -117,26 +189,127 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
 }
 
-func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
+// ValidateCA returns the certificate of a CA, or an error if it can't sign certificates with the key
+func ValidateCA(certPath, keyPath string) (*x509.Certificate, error) {
+	cert, err := readCert(certPath)
+	if err != nil {
+		return nil, err
+	}
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return nil, fmt.Errorf("%s is not a CA certificate", certPath)
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, fmt.Errorf("%s is not allowed to sign certificates", certPath)
+	}
+	now := time.Now()
+	if now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("%s expired on %s", certPath, cert.NotAfter.Format(time.RFC3339))
+	}
+	if now.Before(cert.NotBefore) {
+		return nil, fmt.Errorf("%s is not valid before %s", certPath, cert.NotBefore.Format(time.RFC3339))
+	}
+
+	key, err := readPrivateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(cert.PublicKey) {
+		return nil, fmt.Errorf("the key %s does not match the certificate %s", keyPath, certPath)
+	}
+	return cert, nil
+}
+
+// readCert reads the first certificate of a PEM file
+func readCert(certPath string) (*x509.Certificate, error) {
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading certificate")
+	}
+	for {
+		var block *pem.Block
+		block, certBytes = pem.Decode(certBytes)
+		if block == nil {
+			return nil, errors.New("Unable to decode certificate")
+		}
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, errors.Wrap(err, "Error parsing certificate")
+			}
+			return cert, nil
+		}
+	}
+}
+
+// readPrivateKey reads a PKCS #1, SEC 1 or PKCS #8 encoded private key from a PEM file
+func readPrivateKey(keyPath string) (crypto.Signer, error) {
 	keyBytes, err := ioutil.ReadFile(keyPath)
-	if err == nil {
-		decodedKey, _ := pem.Decode(keyBytes)
-		if decodedKey != nil {
-			priv, err := x509.ParsePKCS1PrivateKey(decodedKey.Bytes)
-			if err == nil {
-				return priv, nil
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading key")
+	}
+	block, _ := pem.Decode(keyBytes)
+	if block == nil {
+		return nil, errors.New("Unable to decode key")
+	}
+	return parsePrivateKey(block.Bytes)
+}
+
+// parsePrivateKey parses a DER encoded RSA or ECDSA private key
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing private key")
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", key)
+}
+
+// loadOrGeneratePrivateKey loads the key at keyPath if it has the algorithm and size of the options, or else generates a new one
+func loadOrGeneratePrivateKey(keyPath string, opts CertOptions) (crypto.Signer, error) {
+	if priv, err := readPrivateKey(keyPath); err == nil {
+		switch k := priv.(type) {
+		case *rsa.PrivateKey:
+			if opts.KeyAlgorithm == RSA && k.N.BitLen() == opts.KeySize {
+				return k, nil
+			}
+		case *ecdsa.PrivateKey:
+			if opts.KeyAlgorithm == ECDSA && k.Curve.Params().BitSize == opts.KeySize {
+				return k, nil
 			}
 		}
 	}
-	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if opts.KeyAlgorithm == ECDSA {
+		c, err := curve(opts.KeySize)
+		if err != nil {
+			return nil, err
+		}
+		priv, err := ecdsa.GenerateKey(c, rand.Reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error generating ECDSA key")
+		}
+		return priv, nil
+	}
+	priv, err := rsa.GenerateKey(rand.Reader, opts.KeySize)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error generating RSA key")
 	}
 	return priv, nil
 }
 
-func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *rsa.PrivateKey, keyPath string, parent *x509.Certificate, signingKey *rsa.PrivateKey) error {
-	derBytes, err := x509.CreateCertificate(rand.Reader, template, parent, &signeeKey.PublicKey, signingKey)
+func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey crypto.Signer, keyPath string, parent *x509.Certificate, signingKey crypto.Signer) error {
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, parent, signeeKey.Public(), signingKey)
 	if err != nil {
 		return errors.Wrap(err, "Error creating certificate")
 	}
@@ -146,8 +319,22 @@ func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *r
 		return errors.Wrap(err, "Error encoding certificate")
 	}
 
+	keyBlock := &pem.Block{}
+	switch k := signeeKey.(type) {
+	case *rsa.PrivateKey:
+		keyBlock.Type = "RSA PRIVATE KEY"
+		keyBlock.Bytes = x509.MarshalPKCS1PrivateKey(k)
+	case *ecdsa.PrivateKey:
+		keyBlock.Type = "EC PRIVATE KEY"
+		keyBlock.Bytes, err = x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return errors.Wrap(err, "Error marshalling key")
+		}
+	default:
+		return fmt.Errorf("unsupported private key type %T", signeeKey)
+	}
 	keyBuffer := bytes.Buffer{}
-	if err := pem.Encode(&keyBuffer, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(signeeKey)}); err != nil {
+	if err := pem.Encode(&keyBuffer, keyBlock); err != nil {
 		return errors.Wrap(err, "Error encoding key")
 	}
 
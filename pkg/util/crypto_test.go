@@ -17,13 +17,16 @@ limitations under the License.
 package util
 
 import (
+	"crypto/ecdsa"
 	"crypto/x509"
 	"encoding/pem"
 	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -140,7 +143,7 @@ func TestGenerateSignedCert(t *testing.T) {
 		t.Run(test.description, func(t *testing.T) {
 			err := GenerateSignedCert(
 				certPath, keyPath, "minikube", ips, alternateDNS, test.signerCertPath,
-				test.signerKeyPath,
+				test.signerKeyPath, DefaultCertOptions,
 			)
 			if err != nil && !test.err {
 				t.Errorf("GenerateSignedCert() error = %v", err)
@@ -163,3 +166,97 @@ func TestGenerateSignedCert(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSignedCertOptions(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caCert := filepath.Join(tmpDir, "ca.crt")
+	caKey := filepath.Join(tmpDir, "ca.key")
+	if err := GenerateCACert(caCert, caKey, "minikubeCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	ecOpts := CertOptions{KeyAlgorithm: ECDSA, Validity: 48 * time.Hour, Groups: []string{"dev", "ops"}}
+	certPath := filepath.Join(tmpDir, "leaf.crt")
+	keyPath := filepath.Join(tmpDir, "leaf.key")
+	if err := GenerateSignedCert(certPath, keyPath, "minikube", nil, nil, caCert, caKey, ecOpts); err != nil {
+		t.Fatalf("GenerateSignedCert() error = %v", err)
+	}
+	c, err := readCert(certPath)
+	if err != nil {
+		t.Fatalf("readCert() error = %v", err)
+	}
+	if _, ok := c.PublicKey.(*ecdsa.PublicKey); !ok {
+		t.Errorf("public key is %T, want an ECDSA key", c.PublicKey)
+	}
+	if d := time.Until(c.NotAfter); d > 48*time.Hour || d < 47*time.Hour {
+		t.Errorf("certificate expires in %s, want 48h", d)
+	}
+	if got := strings.Join(c.Subject.Organization, ","); got != "dev,ops" {
+		t.Errorf("certificate groups are %q, want \"dev,ops\"", got)
+	}
+	first := c.SerialNumber
+
+	// regenerating with another algorithm replaces the key
+	if err := GenerateSignedCert(certPath, keyPath, "minikube", nil, nil, caCert, caKey, CertOptions{KeyAlgorithm: RSA, KeySize: 3072}); err != nil {
+		t.Fatalf("GenerateSignedCert() error = %v", err)
+	}
+	c, err = readCert(certPath)
+	if err != nil {
+		t.Fatalf("readCert() error = %v", err)
+	}
+	if c.PublicKeyAlgorithm != x509.RSA || c.SerialNumber.Cmp(first) == 0 {
+		t.Errorf("got a %s key and serial %s, want a new RSA key and serial", c.PublicKeyAlgorithm, c.SerialNumber)
+	}
+
+	if err := GenerateSignedCert(certPath, keyPath, "minikube", nil, nil, caCert, caKey, CertOptions{KeyAlgorithm: RSA, KeySize: 1024}); err == nil {
+		t.Errorf("GenerateSignedCert() with a 1024 bits RSA key should have returned an error")
+	}
+}
+
+func TestValidateCA(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caCert := filepath.Join(tmpDir, "ca.crt")
+	caKey := filepath.Join(tmpDir, "ca.key")
+	otherCert := filepath.Join(tmpDir, "other.crt")
+	otherKey := filepath.Join(tmpDir, "other.key")
+	leafCert := filepath.Join(tmpDir, "leaf.crt")
+	leafKey := filepath.Join(tmpDir, "leaf.key")
+	for _, ca := range [][]string{{caCert, caKey}, {otherCert, otherKey}} {
+		if err := GenerateCACert(ca[0], ca[1], "minikubeCA"); err != nil {
+			t.Fatalf("GenerateCACert() error = %v", err)
+		}
+	}
+	if err := GenerateSignedCert(leafCert, leafKey, "minikube", nil, nil, caCert, caKey, DefaultCertOptions); err != nil {
+		t.Fatalf("GenerateSignedCert() error = %v", err)
+	}
+
+	tests := []struct {
+		description string
+		cert        string
+		key         string
+		err         string
+	}{
+		{"valid", caCert, caKey, ""},
+		{"not a CA", leafCert, leafKey, "is not a CA certificate"},
+		{"mismatched key", caCert, otherKey, "does not match"},
+		{"missing key", caCert, filepath.Join(tmpDir, "missing"), "Error reading key"},
+	}
+	for _, test := range tests {
+		_, err := ValidateCA(test.cert, test.key)
+		if test.err == "" && err != nil {
+			t.Errorf("%s: ValidateCA() error = %v", test.description, err)
+		}
+		if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("%s: ValidateCA() error = %v, want %q", test.description, err, test.err)
+		}
+	}
+}